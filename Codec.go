@@ -0,0 +1,26 @@
+package godatacollections
+
+// Codec turns a T into bytes and back again
+// Used by the collections that support binary encoding so that they don't need to know how to encode what they store
+type Codec[T any] interface {
+	// Encode returns the bytes for t
+	Encode(t T) ([]byte, error)
+
+	// Decode returns the T for bytes that were made by Encode
+	// data may be reused once Decode returns so copy anything out of it that needs to be kept
+	Decode(data []byte) (T, error)
+}
+
+// CodecFuncs allows for using a pair of functions as a Codec
+type CodecFuncs[T any] struct {
+	EncodeFunc func(T) ([]byte, error)
+	DecodeFunc func([]byte) (T, error)
+}
+
+func (this CodecFuncs[T]) Encode(t T) ([]byte, error) {
+	return this.EncodeFunc(t)
+}
+
+func (this CodecFuncs[T]) Decode(data []byte) (T, error) {
+	return this.DecodeFunc(data)
+}
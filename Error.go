@@ -3,11 +3,14 @@ package godatacollections
 import "errors"
 
 const EMPTY_ERROR_MSG string = "empty error"
+const NOT_FOUND_ERROR_MSG string = "not found error"
 
 var emptyError error
+var notFoundError error
 
 func init() {
 	emptyError = errors.New(EMPTY_ERROR_MSG)
+	notFoundError = errors.New(NOT_FOUND_ERROR_MSG)
 }
 
 func EmptyError() error {
@@ -18,3 +21,12 @@ func IsEmptyError(err error) bool {
 	// Since TODO
 	return err == emptyError
 }
+
+// NotFoundError is returned when a lookup has no matching item, such as Floor of a key smaller than everything
+func NotFoundError() error {
+	return notFoundError
+}
+
+func IsNotFoundError(err error) bool {
+	return err == notFoundError
+}
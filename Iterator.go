@@ -13,3 +13,14 @@ type Iterator[T any] interface {
 	// HasNext returns if there is next item to pull
 	HasNext() bool
 }
+
+// MutableIterator is an Iterator that also allows for removing items from the underlying
+// collection while iterating over it
+type MutableIterator[T any] interface {
+	Iterator[T]
+
+	// Remove removes the item that was last returned by Next from the underlying collection
+	// Iteration continues with the item that would have come after the removed item
+	// Returns error if Next has not been called yet or the item has already been removed
+	Remove() error
+}
@@ -0,0 +1,26 @@
+package godatacollections
+
+// OrderedSet is a Set that keeps its items sorted by key
+// Iterator returns items in ascending key order
+type OrderedSet[K, T any] interface {
+	Set[K, T]
+
+	// Min returns the item with the smallest key
+	// Returns zero value and EmptyError if there are no items
+	Min() (T, error)
+
+	// Max returns the item with the largest key
+	// Returns zero value and EmptyError if there are no items
+	Max() (T, error)
+
+	// Floor returns the item with the largest key that is <= K
+	// Returns zero value and NotFoundError if every key is greater
+	Floor(K) (T, error)
+
+	// Ceiling returns the item with the smallest key that is >= K
+	// Returns zero value and NotFoundError if every key is less
+	Ceiling(K) (T, error)
+
+	// RangeIterator returns an iterator over the items with keys >= start and < end in ascending order
+	RangeIterator(start, end K) Iterator[T]
+}
@@ -0,0 +1,247 @@
+package bitset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+const wordSize = 64
+
+// BitSet is a set of non negative integers stored as one bit each
+// It grows as needed so any bit can be set, memory use is one bit per number up to the largest set
+type BitSet struct {
+	words []uint64
+}
+
+// NewBitSet creates a new empty BitSet with room for bits 0 to sizeHint-1 before it needs to grow
+func NewBitSet(sizeHint uint) *BitSet {
+	return &BitSet{words: make([]uint64, 0, wordsFor(sizeHint))}
+}
+
+func wordsFor(numBits uint) int {
+	return int((numBits + wordSize - 1) / wordSize)
+}
+
+// grow makes sure there is a word for bit
+func (this *BitSet) grow(bit uint) {
+	needed := int(bit/wordSize) + 1
+
+	if needed > len(this.words) {
+		this.words = append(this.words, make([]uint64, needed-len(this.words))...)
+	}
+}
+
+// trim drops trailing empty words so that sets with the same bits have the same words
+func (this *BitSet) trim() {
+	for len(this.words) > 0 && this.words[len(this.words)-1] == 0 {
+		this.words = this.words[:len(this.words)-1]
+	}
+}
+
+// Set turns bit on
+func (this *BitSet) Set(bit uint) {
+	this.grow(bit)
+	this.words[bit/wordSize] |= 1 << (bit % wordSize)
+}
+
+// Clear turns bit off
+func (this *BitSet) Clear(bit uint) {
+	if int(bit/wordSize) >= len(this.words) {
+		return
+	}
+
+	this.words[bit/wordSize] &^= 1 << (bit % wordSize)
+	this.trim()
+}
+
+// Test returns if bit is on
+func (this *BitSet) Test(bit uint) bool {
+	if int(bit/wordSize) >= len(this.words) {
+		return false
+	}
+
+	return this.words[bit/wordSize]&(1<<(bit%wordSize)) != 0
+}
+
+// Count returns the number of bits that are on
+func (this *BitSet) Count() int {
+	count := 0
+
+	for _, word := range this.words {
+		count += bits.OnesCount64(word)
+	}
+
+	return count
+}
+
+// IsEmpty returns if no bits are on
+func (this *BitSet) IsEmpty() bool {
+	// Trailing empty words are always trimmed so any word means a bit is on
+	return len(this.words) == 0
+}
+
+// Equal returns if both sets have exactly the same bits on
+func (this *BitSet) Equal(other *BitSet) bool {
+	if len(this.words) != len(other.words) {
+		return false
+	}
+
+	for i := range this.words {
+		if this.words[i] != other.words[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clone returns an independent copy
+func (this *BitSet) Clone() *BitSet {
+	return &BitSet{words: append([]uint64(nil), this.words...)}
+}
+
+// combine returns a new set where each word is op of the words from both sets, treating missing words as 0
+func (this *BitSet) combine(other *BitSet, op func(a, b uint64) uint64) *BitSet {
+	result := &BitSet{words: make([]uint64, max(len(this.words), len(other.words)))}
+
+	for i := range result.words {
+		var a, b uint64
+
+		if i < len(this.words) {
+			a = this.words[i]
+		}
+
+		if i < len(other.words) {
+			b = other.words[i]
+		}
+
+		result.words[i] = op(a, b)
+	}
+
+	result.trim()
+
+	return result
+}
+
+// And returns a new set of the bits that are on in both sets
+func (this *BitSet) And(other *BitSet) *BitSet {
+	return this.combine(other, func(a, b uint64) uint64 { return a & b })
+}
+
+// Or returns a new set of the bits that are on in either set
+func (this *BitSet) Or(other *BitSet) *BitSet {
+	return this.combine(other, func(a, b uint64) uint64 { return a | b })
+}
+
+// Xor returns a new set of the bits that are on in exactly one of the sets
+func (this *BitSet) Xor(other *BitSet) *BitSet {
+	return this.combine(other, func(a, b uint64) uint64 { return a ^ b })
+}
+
+// AndNot returns a new set of the bits that are on in this set but not in other
+func (this *BitSet) AndNot(other *BitSet) *BitSet {
+	return this.combine(other, func(a, b uint64) uint64 { return a &^ b })
+}
+
+// NextSetBit returns the first bit that is on at or after from
+// Returns NotFoundError if there is none
+func (this *BitSet) NextSetBit(from uint) (uint, error) {
+	wordIndex := int(from / wordSize)
+
+	if wordIndex >= len(this.words) {
+		return 0, godatacollections.NotFoundError()
+	}
+
+	// Mask off the bits before from in the first word
+	word := this.words[wordIndex] & (^uint64(0) << (from % wordSize))
+
+	for {
+		if word != 0 {
+			return uint(wordIndex)*wordSize + uint(bits.TrailingZeros64(word)), nil
+		}
+
+		wordIndex++
+
+		if wordIndex >= len(this.words) {
+			return 0, godatacollections.NotFoundError()
+		}
+
+		word = this.words[wordIndex]
+	}
+}
+
+// Iterator returns an iterator over the bits that are on from smallest to largest
+// The set must not be changed while iterating
+func (this *BitSet) Iterator() godatacollections.Iterator[uint] {
+	iter := &bitSetIterator{bitSet: this}
+	iter.next, iter.err = this.NextSetBit(0)
+
+	return iter
+}
+
+type bitSetIterator struct {
+	bitSet *BitSet
+	next   uint
+	// err is set once there is no next bit
+	err error
+}
+
+func (this *bitSetIterator) Close() error {
+	return nil
+}
+
+func (this *bitSetIterator) HasNext() bool {
+	return this.err == nil
+}
+
+func (this *bitSetIterator) Next() (uint, error) {
+	if this.err != nil {
+		return 0, errors.New("nothing left to iterate over")
+	}
+
+	retNext := this.next
+	this.next, this.err = this.bitSet.NextSetBit(retNext + 1)
+
+	return retNext, nil
+}
+
+// MarshalBinary writes the number of words as a uvarint followed by each word as 8 little endian bytes
+func (this *BitSet) MarshalBinary() ([]byte, error) {
+	data := binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+8*len(this.words)), uint64(len(this.words)))
+
+	for _, word := range this.words {
+		data = binary.LittleEndian.AppendUint64(data, word)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary replaces the set with one written by MarshalBinary
+func (this *BitSet) UnmarshalBinary(data []byte) error {
+	wordCount, n := binary.Uvarint(data)
+
+	if n <= 0 {
+		return errors.New("unable to unmarshal BitSet with bad word count")
+	}
+
+	data = data[n:]
+
+	if len(data)%8 != 0 || wordCount != uint64(len(data)/8) {
+		return fmt.Errorf("unable to unmarshal BitSet expecting %d words but got %d bytes", wordCount, len(data))
+	}
+
+	words := make([]uint64, wordCount)
+
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+
+	this.words = words
+	this.trim()
+
+	return nil
+}
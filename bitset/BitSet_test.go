@@ -0,0 +1,190 @@
+package bitset
+
+import (
+	"encoding"
+	"math/rand"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+var _ encoding.BinaryMarshaler = &BitSet{}
+var _ encoding.BinaryUnmarshaler = &BitSet{}
+
+func bitSetOf(bits ...uint) *BitSet {
+	bitSet := NewBitSet(0)
+
+	for _, bit := range bits {
+		bitSet.Set(bit)
+	}
+
+	return bitSet
+}
+
+func iterBits(iter godatacollections.Iterator[uint]) []uint {
+	bits := make([]uint, 0)
+
+	for iter.HasNext() {
+		bit, _ := iter.Next()
+		bits = append(bits, bit)
+	}
+
+	return bits
+}
+
+func expectBits(t *testing.T, actual []uint, expected ...uint) {
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, actual)
+	}
+
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("expected %v but got %v", expected, actual)
+		}
+	}
+}
+
+func TestBitSetSetClearTest(t *testing.T) {
+	bitSet := NewBitSet(10)
+
+	bitSet.Set(3)
+	bitSet.Set(64)
+	bitSet.Set(1000)
+
+	for _, bit := range []uint{3, 64, 1000} {
+		if !bitSet.Test(bit) {
+			t.Fatalf("expected %d to be set", bit)
+		}
+	}
+
+	for _, bit := range []uint{0, 2, 4, 63, 65, 999, 5000} {
+		if bitSet.Test(bit) {
+			t.Fatalf("did not expect %d to be set", bit)
+		}
+	}
+
+	if bitSet.Count() != 3 {
+		t.Fail()
+	}
+
+	bitSet.Clear(1000)
+	bitSet.Clear(5000)
+
+	if bitSet.Test(1000) || bitSet.Count() != 2 || len(bitSet.words) != 2 {
+		t.Fatal("expected clear to remove the bit and trim empty words")
+	}
+
+	bitSet.Clear(3)
+	bitSet.Clear(64)
+
+	if !bitSet.IsEmpty() {
+		t.Fail()
+	}
+}
+
+func TestBitSetOperations(t *testing.T) {
+	a := bitSetOf(1, 2, 3, 100, 200)
+	b := bitSetOf(2, 3, 4, 200)
+
+	expectBits(t, iterBits(a.And(b).Iterator()), 2, 3, 200)
+	expectBits(t, iterBits(a.Or(b).Iterator()), 1, 2, 3, 4, 100, 200)
+	expectBits(t, iterBits(a.Xor(b).Iterator()), 1, 4, 100)
+	expectBits(t, iterBits(a.AndNot(b).Iterator()), 1, 100)
+	expectBits(t, iterBits(b.AndNot(a).Iterator()), 4)
+
+	// Results with high bits cancelled out must still compare equal
+	if !a.Xor(a).Equal(NewBitSet(0)) || !a.And(bitSetOf(1)).Equal(bitSetOf(1)) {
+		t.Fail()
+	}
+
+	// Operations don't change their inputs
+	expectBits(t, iterBits(a.Iterator()), 1, 2, 3, 100, 200)
+}
+
+func TestBitSetNextSetBit(t *testing.T) {
+	bitSet := bitSetOf(0, 63, 64, 130)
+
+	for from, expected := range map[uint]uint{0: 0, 1: 63, 63: 63, 64: 64, 65: 130, 130: 130} {
+		if next, err := bitSet.NextSetBit(from); err != nil || next != expected {
+			t.Fatalf("expected %d from %d but got %d %v", expected, from, next, err)
+		}
+	}
+
+	if _, err := bitSet.NextSetBit(131); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+
+	if _, err := bitSet.NextSetBit(10000); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+}
+
+func TestBitSetIterator(t *testing.T) {
+	expectBits(t, iterBits(NewBitSet(0).Iterator()))
+
+	iter := bitSetOf(5).Iterator()
+	iter.Next()
+
+	if _, err := iter.Next(); err == nil {
+		t.Fail()
+	}
+}
+
+func TestBitSetRandomAgainstMap(t *testing.T) {
+	bitSet := NewBitSet(0)
+	expected := make(map[uint]bool)
+
+	for i := 0; i < 2000; i++ {
+		bit := uint(rand.Intn(700))
+
+		if rand.Intn(3) == 0 {
+			bitSet.Clear(bit)
+			delete(expected, bit)
+		} else {
+			bitSet.Set(bit)
+			expected[bit] = true
+		}
+	}
+
+	if bitSet.Count() != len(expected) {
+		t.Fatalf("expected %d bits but got %d", len(expected), bitSet.Count())
+	}
+
+	for _, bit := range iterBits(bitSet.Iterator()) {
+		if !expected[bit] {
+			t.Fatalf("did not expect %d", bit)
+		}
+	}
+}
+
+func TestBitSetBinaryRoundTrip(t *testing.T) {
+	original := bitSetOf(0, 7, 64, 1023)
+	clone := original.Clone()
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := NewBitSet(0)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.Equal(original) {
+		t.Fatalf("expected %v but got %v", iterBits(original.Iterator()), iterBits(decoded.Iterator()))
+	}
+
+	clone.Set(5)
+	if original.Test(5) {
+		t.Fatal("clone shares words with original")
+	}
+
+	if decoded.UnmarshalBinary(data[:len(data)-1]) == nil {
+		t.Fatal("expected error for truncated data")
+	}
+
+	if decoded.UnmarshalBinary(nil) == nil {
+		t.Fatal("expected error for empty data")
+	}
+}
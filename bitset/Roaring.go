@@ -0,0 +1,436 @@
+package bitset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+const (
+	arrayKind byte = iota
+	bitmapKind
+	runKind
+)
+
+// Roaring is a compressed set of uint32 for when BitSet would waste memory on large sparse sets
+// Values are split by their high 16 bits into containers of their low 16 bits
+// Each container is whichever of a sorted array, a bitmap or a list of runs is smallest for its values
+// Arrays and bitmaps are switched between automatically, call RunOptimize to also use runs where they are smaller
+type Roaring struct {
+	// keys are the high 16 bits for each container in the same order and are sorted
+	keys       []uint16
+	containers []roaringContainer
+	size       int
+}
+
+var _ godatacollections.Set[uint32, uint32] = &Roaring{}
+
+// NewRoaring creates a new empty Roaring
+func NewRoaring() *Roaring {
+	return &Roaring{}
+}
+
+func splitValue(v uint32) (uint16, uint16) {
+	return uint16(v >> 16), uint16(v)
+}
+
+// find returns the index of the container for high or where it would go if there isn't one
+func (this *Roaring) find(high uint16) (int, bool) {
+	index := sort.Search(len(this.keys), func(i int) bool { return this.keys[i] >= high })
+
+	return index, index < len(this.keys) && this.keys[index] == high
+}
+
+// Len returns the number of values in the set
+func (this *Roaring) Len() int {
+	return this.size
+}
+
+// Insert adds v to the set
+// An error is returned if v is already in the set
+func (this *Roaring) Insert(v uint32) error {
+	high, low := splitValue(v)
+	index, found := this.find(high)
+
+	if !found {
+		this.keys = append(this.keys, 0)
+		copy(this.keys[index+1:], this.keys[index:])
+		this.keys[index] = high
+
+		this.containers = append(this.containers, nil)
+		copy(this.containers[index+1:], this.containers[index:])
+		this.containers[index] = &arrayContainer{}
+	}
+
+	container, added := this.containers[index].add(low)
+
+	if !added {
+		return fmt.Errorf("unable to insert duplicate value %d", v)
+	}
+
+	this.containers[index] = container
+	this.size++
+
+	return nil
+}
+
+// Remove takes v out of the set
+// An error is returned if v was not in the set
+func (this *Roaring) Remove(v uint32) error {
+	high, low := splitValue(v)
+	index, found := this.find(high)
+
+	if !found {
+		return fmt.Errorf("unable to delete value %d due to it not existing in set", v)
+	}
+
+	container, removed := this.containers[index].remove(low)
+
+	if !removed {
+		return fmt.Errorf("unable to delete value %d due to it not existing in set", v)
+	}
+
+	if container == nil || container.cardinality() == 0 {
+		this.keys = append(this.keys[:index], this.keys[index+1:]...)
+		this.containers = append(this.containers[:index], this.containers[index+1:]...)
+	} else {
+		this.containers[index] = container
+	}
+
+	this.size--
+
+	return nil
+}
+
+// Contains returns if v is in the set
+func (this *Roaring) Contains(v uint32) bool {
+	high, low := splitValue(v)
+	index, found := this.find(high)
+
+	return found && this.containers[index].contains(low)
+}
+
+// GetByKey returns v if it is in the set and 0 if it is not
+// Values are their own keys so this is only here to be a Set
+func (this *Roaring) GetByKey(v uint32) uint32 {
+	if this.Contains(v) {
+		return v
+	}
+
+	return 0
+}
+
+// Clone returns an independent copy
+func (this *Roaring) Clone() *Roaring {
+	clone := &Roaring{keys: append([]uint16(nil), this.keys...), containers: make([]roaringContainer, len(this.containers)), size: this.size}
+
+	for i, container := range this.containers {
+		clone.containers[i] = container.clone()
+	}
+
+	return clone
+}
+
+// Equal returns if both sets have exactly the same values
+func (this *Roaring) Equal(other *Roaring) bool {
+	if this.size != other.size || len(this.keys) != len(other.keys) {
+		return false
+	}
+
+	for i := range this.keys {
+		if this.keys[i] != other.keys[i] || this.containers[i].cardinality() != other.containers[i].cardinality() {
+			return false
+		}
+
+		// Same cardinality so nothing left in a means the containers match
+		if andNotContainers(this.containers[i], other.containers[i]) != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// append adds container to the end of the set, skipping it if it is empty
+func (this *Roaring) append(high uint16, container roaringContainer) {
+	if container == nil {
+		return
+	}
+
+	this.keys = append(this.keys, high)
+	this.containers = append(this.containers, container)
+	this.size += container.cardinality()
+}
+
+// combine walks the keys of both sets together building a new set
+// op is used for keys in both sets, keys in only one set have their container copied if the matching keep is true
+func (this *Roaring) combine(other *Roaring, op func(a, b roaringContainer) roaringContainer, keepThis, keepOther bool) *Roaring {
+	result := NewRoaring()
+	i, j := 0, 0
+
+	for i < len(this.keys) || j < len(other.keys) {
+		switch {
+		case j == len(other.keys) || (i < len(this.keys) && this.keys[i] < other.keys[j]):
+			if keepThis {
+				result.append(this.keys[i], this.containers[i].clone())
+			}
+			i++
+		case i == len(this.keys) || other.keys[j] < this.keys[i]:
+			if keepOther {
+				result.append(other.keys[j], other.containers[j].clone())
+			}
+			j++
+		default:
+			result.append(this.keys[i], op(this.containers[i], other.containers[j]))
+			i++
+			j++
+		}
+	}
+
+	return result
+}
+
+// And returns a new set of the values in both sets
+// Only containers whose keys are in both sets are looked at and arrays are intersected without becoming bitmaps
+func (this *Roaring) And(other *Roaring) *Roaring {
+	return this.combine(other, andContainers, false, false)
+}
+
+// Or returns a new set of the values in either set
+func (this *Roaring) Or(other *Roaring) *Roaring {
+	return this.combine(other, orContainers, true, true)
+}
+
+// AndNot returns a new set of the values in this set but not in other
+func (this *Roaring) AndNot(other *Roaring) *Roaring {
+	return this.combine(other, andNotContainers, true, false)
+}
+
+// Xor returns a new set of the values in exactly one of the sets
+func (this *Roaring) Xor(other *Roaring) *Roaring {
+	return this.combine(other, xorContainers, true, true)
+}
+
+// RunOptimize switches each container to runs where that is smaller, and back from runs where it no longer is
+// Best called once a set is done being built as changing a run container unpacks it
+func (this *Roaring) RunOptimize() {
+	for i, container := range this.containers {
+		this.containers[i] = optimizeContainer(container)
+	}
+}
+
+// Iterator returns an iterator over the values from smallest to largest
+// The set must not be changed while iterating
+func (this *Roaring) Iterator() godatacollections.Iterator[uint32] {
+	iter := &roaringIterator{roaring: this}
+	iter.advance(0, 0)
+
+	return iter
+}
+
+type roaringIterator struct {
+	roaring *Roaring
+	// index is the container next comes from, it is past the last container when there is nothing left
+	index int
+	next  uint16
+}
+
+// advance finds the first value at or after from in the container at index or the ones after it
+func (this *roaringIterator) advance(index, from int) {
+	for ; index < len(this.roaring.containers); index, from = index+1, 0 {
+		if next, found := this.roaring.containers[index].nextFrom(from); found {
+			this.index, this.next = index, next
+			return
+		}
+	}
+
+	this.index = index
+}
+
+func (this *roaringIterator) Close() error {
+	return nil
+}
+
+func (this *roaringIterator) HasNext() bool {
+	return this.index < len(this.roaring.containers)
+}
+
+func (this *roaringIterator) Next() (uint32, error) {
+	if !this.HasNext() {
+		return 0, errors.New("nothing left to iterate over")
+	}
+
+	retNext := uint32(this.roaring.keys[this.index])<<16 | uint32(this.next)
+	this.advance(this.index, int(this.next)+1)
+
+	return retNext, nil
+}
+
+// MarshalBinary writes the number of containers as a uvarint followed by each container
+// A container is its key as 2 little endian bytes, a byte for its kind and then
+//
+//	array: the number of values as a uvarint followed by each value as 2 little endian bytes
+//	bitmap: 1024 words as 8 little endian bytes each
+//	run: the number of runs as a uvarint followed by the start and last of each run as 2 little endian bytes each
+//
+// This is not the format used by other Roaring libraries
+func (this *Roaring) MarshalBinary() ([]byte, error) {
+	data := binary.AppendUvarint(nil, uint64(len(this.containers)))
+
+	for i, container := range this.containers {
+		data = binary.LittleEndian.AppendUint16(data, this.keys[i])
+
+		switch c := container.(type) {
+		case *arrayContainer:
+			data = append(data, arrayKind)
+			data = binary.AppendUvarint(data, uint64(len(c.values)))
+
+			for _, v := range c.values {
+				data = binary.LittleEndian.AppendUint16(data, v)
+			}
+		case *bitmapContainer:
+			data = append(data, bitmapKind)
+
+			for _, word := range c.words {
+				data = binary.LittleEndian.AppendUint64(data, word)
+			}
+		case *runContainer:
+			data = append(data, runKind)
+			data = binary.AppendUvarint(data, uint64(len(c.runs)))
+
+			for _, run := range c.runs {
+				data = binary.LittleEndian.AppendUint16(data, run.start)
+				data = binary.LittleEndian.AppendUint16(data, run.last)
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary replaces the set with one written by MarshalBinary
+func (this *Roaring) UnmarshalBinary(data []byte) error {
+	containerCount, n := binary.Uvarint(data)
+
+	if n <= 0 {
+		return errors.New("unable to unmarshal Roaring with bad container count")
+	}
+
+	data = data[n:]
+	// Every container takes at least 4 bytes so this stops a bad count from allocating too much
+	if containerCount > uint64(len(data)/4) {
+		return fmt.Errorf("unable to unmarshal Roaring expecting %d containers but got %d bytes", containerCount, len(data))
+	}
+
+	result := &Roaring{keys: make([]uint16, 0, containerCount), containers: make([]roaringContainer, 0, containerCount)}
+
+	for i := uint64(0); i < containerCount; i++ {
+		if len(data) < 3 {
+			return errors.New("unable to unmarshal Roaring with truncated container")
+		}
+
+		high := binary.LittleEndian.Uint16(data)
+		kind := data[2]
+		data = data[3:]
+
+		if len(result.keys) > 0 && high <= result.keys[len(result.keys)-1] {
+			return fmt.Errorf("unable to unmarshal Roaring with container key %d out of order", high)
+		}
+
+		var container roaringContainer
+		var err error
+
+		switch kind {
+		case arrayKind:
+			container, data, err = unmarshalArray(data)
+		case bitmapKind:
+			container, data, err = unmarshalBitmap(data)
+		case runKind:
+			container, data, err = unmarshalRuns(data)
+		default:
+			err = fmt.Errorf("unable to unmarshal Roaring with unknown container kind %d", kind)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		result.append(high, container)
+	}
+
+	if len(data) != 0 {
+		return fmt.Errorf("unable to unmarshal Roaring with %d bytes left over", len(data))
+	}
+
+	*this = *result
+
+	return nil
+}
+
+func unmarshalArray(data []byte) (roaringContainer, []byte, error) {
+	count, n := binary.Uvarint(data)
+
+	if n <= 0 || count == 0 || count > uint64(len(data[n:])/2) {
+		return nil, nil, errors.New("unable to unmarshal Roaring with bad array container")
+	}
+
+	data = data[n:]
+	array := &arrayContainer{values: make([]uint16, count)}
+
+	for i := range array.values {
+		array.values[i] = binary.LittleEndian.Uint16(data[2*i:])
+
+		if i > 0 && array.values[i] <= array.values[i-1] {
+			return nil, nil, errors.New("unable to unmarshal Roaring with unsorted array container")
+		}
+	}
+
+	return array, data[2*count:], nil
+}
+
+func unmarshalBitmap(data []byte) (roaringContainer, []byte, error) {
+	if len(data) < 8*bitmapWords {
+		return nil, nil, errors.New("unable to unmarshal Roaring with truncated bitmap container")
+	}
+
+	bitmap := &bitmapContainer{}
+
+	for i := range bitmap.words {
+		bitmap.words[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+
+	bitmap.recount()
+
+	if bitmap.card == 0 {
+		return nil, nil, errors.New("unable to unmarshal Roaring with empty bitmap container")
+	}
+
+	return bitmap, data[8*bitmapWords:], nil
+}
+
+func unmarshalRuns(data []byte) (roaringContainer, []byte, error) {
+	count, n := binary.Uvarint(data)
+
+	if n <= 0 || count == 0 || count > uint64(len(data[n:])/4) {
+		return nil, nil, errors.New("unable to unmarshal Roaring with bad run container")
+	}
+
+	data = data[n:]
+	runs := &runContainer{runs: make([]roaringRun, count)}
+
+	for i := range runs.runs {
+		run := roaringRun{start: binary.LittleEndian.Uint16(data[4*i:]), last: binary.LittleEndian.Uint16(data[4*i+2:])}
+
+		// Runs must not touch either as then they should have been one run
+		if run.last < run.start || (i > 0 && int(run.start) <= int(runs.runs[i-1].last)+1) {
+			return nil, nil, errors.New("unable to unmarshal Roaring with unsorted run container")
+		}
+
+		runs.runs[i] = run
+	}
+
+	return runs, data[4*count:], nil
+}
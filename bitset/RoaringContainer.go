@@ -0,0 +1,471 @@
+package bitset
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// arrayMaxSize is the most values an array container holds before a bitmap is smaller
+// 4096 values at 2 bytes each is the same 8KB a bitmap of 65536 bits takes
+const arrayMaxSize = 4096
+
+const bitmapWords = 65536 / wordSize
+
+// roaringContainer holds the low 16 bits of every value in a Roaring that shares the same high 16 bits
+// There are three kinds, each smallest for a different shape of data:
+//
+//	arrayContainer for a few scattered values
+//	bitmapContainer for many scattered values
+//	runContainer for values that come in long unbroken runs
+//
+// add and remove return the container to use from then on as they may switch to another kind
+type roaringContainer interface {
+	contains(v uint16) bool
+	add(v uint16) (roaringContainer, bool)
+	remove(v uint16) (roaringContainer, bool)
+	cardinality() int
+	// nextFrom returns the smallest value >= from. from is an int so that it can be 65536 to mean past the end
+	nextFrom(from int) (uint16, bool)
+	forEach(func(v uint16))
+	toBitmap() *bitmapContainer
+	clone() roaringContainer
+}
+
+type arrayContainer struct {
+	// values are sorted
+	values []uint16
+}
+
+func (this *arrayContainer) find(v uint16) (int, bool) {
+	index := sort.Search(len(this.values), func(i int) bool { return this.values[i] >= v })
+
+	return index, index < len(this.values) && this.values[index] == v
+}
+
+func (this *arrayContainer) contains(v uint16) bool {
+	_, found := this.find(v)
+
+	return found
+}
+
+func (this *arrayContainer) add(v uint16) (roaringContainer, bool) {
+	index, found := this.find(v)
+
+	if found {
+		return this, false
+	}
+
+	if len(this.values) >= arrayMaxSize {
+		bitmap := this.toBitmap()
+		return bitmap.add(v)
+	}
+
+	this.values = append(this.values, 0)
+	copy(this.values[index+1:], this.values[index:])
+	this.values[index] = v
+
+	return this, true
+}
+
+func (this *arrayContainer) remove(v uint16) (roaringContainer, bool) {
+	index, found := this.find(v)
+
+	if !found {
+		return this, false
+	}
+
+	this.values = append(this.values[:index], this.values[index+1:]...)
+
+	return this, true
+}
+
+func (this *arrayContainer) cardinality() int {
+	return len(this.values)
+}
+
+func (this *arrayContainer) nextFrom(from int) (uint16, bool) {
+	index := sort.Search(len(this.values), func(i int) bool { return int(this.values[i]) >= from })
+
+	if index == len(this.values) {
+		return 0, false
+	}
+
+	return this.values[index], true
+}
+
+func (this *arrayContainer) forEach(f func(v uint16)) {
+	for _, v := range this.values {
+		f(v)
+	}
+}
+
+func (this *arrayContainer) toBitmap() *bitmapContainer {
+	bitmap := &bitmapContainer{}
+
+	for _, v := range this.values {
+		bitmap.words[v/wordSize] |= 1 << (v % wordSize)
+	}
+
+	bitmap.card = len(this.values)
+
+	return bitmap
+}
+
+func (this *arrayContainer) clone() roaringContainer {
+	return &arrayContainer{values: append([]uint16(nil), this.values...)}
+}
+
+type bitmapContainer struct {
+	words [bitmapWords]uint64
+	card  int
+}
+
+func (this *bitmapContainer) contains(v uint16) bool {
+	return this.words[v/wordSize]&(1<<(v%wordSize)) != 0
+}
+
+func (this *bitmapContainer) add(v uint16) (roaringContainer, bool) {
+	if this.contains(v) {
+		return this, false
+	}
+
+	this.words[v/wordSize] |= 1 << (v % wordSize)
+	this.card++
+
+	return this, true
+}
+
+func (this *bitmapContainer) remove(v uint16) (roaringContainer, bool) {
+	if !this.contains(v) {
+		return this, false
+	}
+
+	this.words[v/wordSize] &^= 1 << (v % wordSize)
+	this.card--
+
+	return shrinkBitmap(this), true
+}
+
+func (this *bitmapContainer) cardinality() int {
+	return this.card
+}
+
+func (this *bitmapContainer) nextFrom(from int) (uint16, bool) {
+	if from >= 65536 {
+		return 0, false
+	}
+
+	wordIndex := from / wordSize
+	word := this.words[wordIndex] & (^uint64(0) << (from % wordSize))
+
+	for {
+		if word != 0 {
+			return uint16(wordIndex*wordSize + bits.TrailingZeros64(word)), true
+		}
+
+		wordIndex++
+
+		if wordIndex >= bitmapWords {
+			return 0, false
+		}
+
+		word = this.words[wordIndex]
+	}
+}
+
+func (this *bitmapContainer) forEach(f func(v uint16)) {
+	for wordIndex, word := range this.words {
+		for word != 0 {
+			f(uint16(wordIndex*wordSize + bits.TrailingZeros64(word)))
+			// Clear the lowest bit
+			word &= word - 1
+		}
+	}
+}
+
+func (this *bitmapContainer) toBitmap() *bitmapContainer {
+	bitmapCopy := *this
+
+	return &bitmapCopy
+}
+
+func (this *bitmapContainer) clone() roaringContainer {
+	return this.toBitmap()
+}
+
+func (this *bitmapContainer) recount() {
+	this.card = 0
+
+	for _, word := range this.words {
+		this.card += bits.OnesCount64(word)
+	}
+}
+
+// shrinkBitmap returns an array container instead once bitmap has few enough values, or nil if it is empty
+func shrinkBitmap(bitmap *bitmapContainer) roaringContainer {
+	if bitmap.card == 0 {
+		return nil
+	}
+
+	if bitmap.card > arrayMaxSize {
+		return bitmap
+	}
+
+	array := &arrayContainer{values: make([]uint16, 0, bitmap.card)}
+	bitmap.forEach(func(v uint16) { array.values = append(array.values, v) })
+
+	return array
+}
+
+// roaringRun is the values from start to last inclusive. last is used instead of a length so 65536 values still fit
+type roaringRun struct {
+	start uint16
+	last  uint16
+}
+
+type runContainer struct {
+	// runs are sorted and never touch or overlap
+	runs []roaringRun
+}
+
+// findRun returns the index of the first run that ends at or after v
+func (this *runContainer) findRun(v int) int {
+	return sort.Search(len(this.runs), func(i int) bool { return int(this.runs[i].last) >= v })
+}
+
+func (this *runContainer) contains(v uint16) bool {
+	index := this.findRun(int(v))
+
+	return index < len(this.runs) && this.runs[index].start <= v
+}
+
+// unpack turns the runs into whichever of array or bitmap fits their values
+// Runs are only made by RunOptimize so changes go through one of the other kinds
+func (this *runContainer) unpack() roaringContainer {
+	if this.cardinality() > arrayMaxSize {
+		return this.toBitmap()
+	}
+
+	array := &arrayContainer{values: make([]uint16, 0, this.cardinality())}
+	this.forEach(func(v uint16) { array.values = append(array.values, v) })
+
+	return array
+}
+
+func (this *runContainer) add(v uint16) (roaringContainer, bool) {
+	if this.contains(v) {
+		return this, false
+	}
+
+	return this.unpack().add(v)
+}
+
+func (this *runContainer) remove(v uint16) (roaringContainer, bool) {
+	if !this.contains(v) {
+		return this, false
+	}
+
+	unpacked, _ := this.unpack().remove(v)
+
+	if unpacked == nil || unpacked.cardinality() == 0 {
+		return nil, true
+	}
+
+	return unpacked, true
+}
+
+func (this *runContainer) cardinality() int {
+	card := 0
+
+	for _, run := range this.runs {
+		card += int(run.last-run.start) + 1
+	}
+
+	return card
+}
+
+func (this *runContainer) nextFrom(from int) (uint16, bool) {
+	index := this.findRun(from)
+
+	if index == len(this.runs) {
+		return 0, false
+	}
+
+	return uint16(max(int(this.runs[index].start), from)), true
+}
+
+func (this *runContainer) forEach(f func(v uint16)) {
+	for _, run := range this.runs {
+		for v := int(run.start); v <= int(run.last); v++ {
+			f(uint16(v))
+		}
+	}
+}
+
+func (this *runContainer) toBitmap() *bitmapContainer {
+	bitmap := &bitmapContainer{}
+	this.forEach(func(v uint16) { bitmap.words[v/wordSize] |= 1 << (v % wordSize) })
+	bitmap.card = this.cardinality()
+
+	return bitmap
+}
+
+func (this *runContainer) clone() roaringContainer {
+	return &runContainer{runs: append([]roaringRun(nil), this.runs...)}
+}
+
+// countRuns returns how many runs container's values make
+func countRuns(container roaringContainer) int {
+	runs := 0
+	last := -2
+
+	container.forEach(func(v uint16) {
+		if int(v) != last+1 {
+			runs++
+		}
+		last = int(v)
+	})
+
+	return runs
+}
+
+// toRuns returns container's values as runs
+func toRuns(container roaringContainer) *runContainer {
+	runContainer := &runContainer{}
+
+	container.forEach(func(v uint16) {
+		lastIndex := len(runContainer.runs) - 1
+
+		if lastIndex >= 0 && int(runContainer.runs[lastIndex].last)+1 == int(v) {
+			runContainer.runs[lastIndex].last = v
+		} else {
+			runContainer.runs = append(runContainer.runs, roaringRun{start: v, last: v})
+		}
+	})
+
+	return runContainer
+}
+
+// optimizeContainer returns whichever kind of container holds container's values in the fewest bytes
+func optimizeContainer(container roaringContainer) roaringContainer {
+	card := container.cardinality()
+	runBytes := 4 * countRuns(container)
+	otherBytes := 8192
+
+	if card <= arrayMaxSize {
+		otherBytes = 2 * card
+	}
+
+	if runBytes < otherBytes {
+		if _, isRun := container.(*runContainer); isRun {
+			return container
+		}
+		return toRuns(container)
+	}
+
+	if run, isRun := container.(*runContainer); isRun {
+		return run.unpack()
+	}
+
+	return container
+}
+
+// andContainers returns the values in both containers or nil if there are none
+// Arrays are filtered rather than turned into bitmaps as an intersection is never bigger than its smaller side
+func andContainers(a, b roaringContainer) roaringContainer {
+	if _, isArray := b.(*arrayContainer); isArray {
+		a, b = b, a
+	}
+
+	if array, isArray := a.(*arrayContainer); isArray {
+		result := &arrayContainer{}
+
+		for _, v := range array.values {
+			if b.contains(v) {
+				result.values = append(result.values, v)
+			}
+		}
+
+		if len(result.values) == 0 {
+			return nil
+		}
+
+		return result
+	}
+
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x & y })
+}
+
+// orContainers returns the values in either container
+func orContainers(a, b roaringContainer) roaringContainer {
+	aArray, aIsArray := a.(*arrayContainer)
+	bArray, bIsArray := b.(*arrayContainer)
+
+	if aIsArray && bIsArray && len(aArray.values)+len(bArray.values) <= arrayMaxSize {
+		// Merge the two sorted arrays
+		result := &arrayContainer{values: make([]uint16, 0, len(aArray.values)+len(bArray.values))}
+		i, j := 0, 0
+
+		for i < len(aArray.values) || j < len(bArray.values) {
+			if j == len(bArray.values) || (i < len(aArray.values) && aArray.values[i] < bArray.values[j]) {
+				result.values = append(result.values, aArray.values[i])
+				i++
+			} else if i == len(aArray.values) || bArray.values[j] < aArray.values[i] {
+				result.values = append(result.values, bArray.values[j])
+				j++
+			} else {
+				result.values = append(result.values, aArray.values[i])
+				i++
+				j++
+			}
+		}
+
+		return result
+	}
+
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x | y })
+}
+
+// andNotContainers returns the values in a but not in b or nil if there are none
+func andNotContainers(a, b roaringContainer) roaringContainer {
+	if array, isArray := a.(*arrayContainer); isArray {
+		result := &arrayContainer{}
+
+		for _, v := range array.values {
+			if !b.contains(v) {
+				result.values = append(result.values, v)
+			}
+		}
+
+		if len(result.values) == 0 {
+			return nil
+		}
+
+		return result
+	}
+
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x &^ y })
+}
+
+// xorContainers returns the values in exactly one of the containers or nil if there are none
+func xorContainers(a, b roaringContainer) roaringContainer {
+	return bitmapOp(a, b, func(x, y uint64) uint64 { return x ^ y })
+}
+
+// bitmapOp applies op to each word of both containers as bitmaps
+func bitmapOp(a, b roaringContainer, op func(x, y uint64) uint64) roaringContainer {
+	result := a.toBitmap()
+	bBitmap, isBitmap := b.(*bitmapContainer)
+
+	if !isBitmap {
+		bBitmap = b.toBitmap()
+	}
+
+	for i := range result.words {
+		result.words[i] = op(result.words[i], bBitmap.words[i])
+	}
+
+	result.recount()
+
+	return shrinkBitmap(result)
+}
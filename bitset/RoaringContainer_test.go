@@ -0,0 +1,107 @@
+package bitset
+
+import "testing"
+
+func containerValues(container roaringContainer) []uint {
+	values := make([]uint, 0)
+	container.forEach(func(v uint16) { values = append(values, uint(v)) })
+
+	return values
+}
+
+func TestArrayContainerSwitchesToBitmapAndBack(t *testing.T) {
+	var container roaringContainer = &arrayContainer{}
+
+	for v := 0; v <= arrayMaxSize; v++ {
+		container, _ = container.add(uint16(2 * v))
+	}
+
+	if _, isBitmap := container.(*bitmapContainer); !isBitmap || container.cardinality() != arrayMaxSize+1 {
+		t.Fatal("expected array to become a bitmap once it is too big")
+	}
+
+	container, _ = container.remove(0)
+
+	if _, isArray := container.(*arrayContainer); !isArray || container.cardinality() != arrayMaxSize {
+		t.Fatal("expected bitmap to become an array once it is small enough")
+	}
+
+	if container.contains(0) || !container.contains(2) || container.contains(3) {
+		t.Fail()
+	}
+}
+
+func TestRunContainer(t *testing.T) {
+	runs := &runContainer{runs: []roaringRun{{start: 5, last: 7}, {start: 10, last: 10}, {start: 65530, last: 65535}}}
+
+	if runs.cardinality() != 10 || !runs.contains(6) || runs.contains(8) || !runs.contains(65535) {
+		t.Fail()
+	}
+
+	for from, expected := range map[int]uint16{0: 5, 6: 6, 8: 10, 11: 65530, 65535: 65535} {
+		if next, found := runs.nextFrom(from); !found || next != expected {
+			t.Fatalf("expected %d from %d but got %d", expected, from, next)
+		}
+	}
+
+	if _, found := runs.nextFrom(65536); found {
+		t.Fail()
+	}
+
+	changed, added := runs.add(8)
+
+	if _, isArray := changed.(*arrayContainer); !isArray || !added {
+		t.Fatal("expected adding to runs to unpack them")
+	}
+
+	expectBits(t, containerValues(changed), 5, 6, 7, 8, 10, 65530, 65531, 65532, 65533, 65534, 65535)
+	// The original runs are left alone
+	expectBits(t, containerValues(runs), 5, 6, 7, 10, 65530, 65531, 65532, 65533, 65534, 65535)
+
+	single := &runContainer{runs: []roaringRun{{start: 1, last: 1}}}
+
+	if emptied, removed := single.remove(1); emptied != nil || !removed {
+		t.Fatal("expected removing the last value to leave nothing")
+	}
+}
+
+func TestOptimizeContainerPicksSmallest(t *testing.T) {
+	full := &bitmapContainer{}
+
+	for i := range full.words {
+		full.words[i] = ^uint64(0)
+	}
+	full.card = 65536
+
+	runs, isRun := optimizeContainer(full).(*runContainer)
+
+	if !isRun || len(runs.runs) != 1 || runs.cardinality() != 65536 {
+		t.Fatal("expected a full bitmap to become one run")
+	}
+
+	scattered := &arrayContainer{values: []uint16{1, 3, 5}}
+
+	if optimizeContainer(scattered) != scattered {
+		t.Fatal("expected scattered values to stay an array")
+	}
+
+	if _, isArray := optimizeContainer(&runContainer{runs: []roaringRun{{1, 1}, {3, 3}}}).(*arrayContainer); !isArray {
+		t.Fatal("expected runs of one value to go back to an array")
+	}
+}
+
+func TestContainerOperationsMixKinds(t *testing.T) {
+	array := &arrayContainer{values: []uint16{1, 2, 100, 5000}}
+	bitmap := (&arrayContainer{values: []uint16{2, 3, 5000, 6000}}).toBitmap()
+	runs := &runContainer{runs: []roaringRun{{start: 0, last: 2}, {start: 4999, last: 5001}}}
+
+	expectBits(t, containerValues(andContainers(array, bitmap)), 2, 5000)
+	expectBits(t, containerValues(andContainers(bitmap, runs)), 2, 5000)
+	expectBits(t, containerValues(orContainers(array, runs)), 0, 1, 2, 100, 4999, 5000, 5001)
+	expectBits(t, containerValues(andNotContainers(runs, array)), 0, 4999, 5001)
+	expectBits(t, containerValues(xorContainers(array, bitmap)), 1, 3, 100, 6000)
+
+	if andContainers(array, &arrayContainer{values: []uint16{7}}) != nil || xorContainers(array, array) != nil {
+		t.Fatal("expected empty results to be nil")
+	}
+}
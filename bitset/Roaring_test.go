@@ -0,0 +1,261 @@
+package bitset
+
+import (
+	"encoding"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+var _ encoding.BinaryMarshaler = &Roaring{}
+var _ encoding.BinaryUnmarshaler = &Roaring{}
+
+func roaringOf(values ...uint32) *Roaring {
+	roaring := NewRoaring()
+
+	for _, v := range values {
+		roaring.Insert(v)
+	}
+
+	return roaring
+}
+
+func iterUint32s(iter godatacollections.Iterator[uint32]) []uint32 {
+	values := make([]uint32, 0)
+
+	for iter.HasNext() {
+		v, _ := iter.Next()
+		values = append(values, v)
+	}
+
+	return values
+}
+
+func expectUint32s(t *testing.T, actual []uint32, expected ...uint32) {
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, actual)
+	}
+
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("expected %v but got %v", expected, actual)
+		}
+	}
+}
+
+func TestRoaringInsertRemoveContains(t *testing.T) {
+	roaring := NewRoaring()
+
+	for _, v := range []uint32{7, 1 << 20, 3, 0xFFFFFFFF, 65536} {
+		if err := roaring.Insert(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if roaring.Insert(7) == nil {
+		t.Fatal("expected error inserting a duplicate")
+	}
+
+	if roaring.Len() != 5 || len(roaring.keys) != 4 {
+		t.Fatalf("expected 5 values in 4 containers but got %d in %d", roaring.Len(), len(roaring.keys))
+	}
+
+	if !roaring.Contains(0xFFFFFFFF) || roaring.Contains(8) || roaring.Contains(65537) {
+		t.Fail()
+	}
+
+	if roaring.GetByKey(3) != 3 || roaring.GetByKey(4) != 0 {
+		t.Fail()
+	}
+
+	expectUint32s(t, iterUint32s(roaring.Iterator()), 3, 7, 65536, 1<<20, 0xFFFFFFFF)
+
+	if err := roaring.Remove(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+
+	if roaring.Remove(1<<20) == nil || roaring.Remove(8) == nil {
+		t.Fatal("expected error removing a missing value")
+	}
+
+	if roaring.Len() != 4 || len(roaring.keys) != 3 {
+		t.Fatal("expected the emptied container to be dropped")
+	}
+}
+
+func TestRoaringIterator(t *testing.T) {
+	expectUint32s(t, iterUint32s(NewRoaring().Iterator()))
+
+	iter := roaringOf(5).Iterator()
+	iter.Next()
+
+	if _, err := iter.Next(); err == nil {
+		t.Fail()
+	}
+}
+
+func TestRoaringOperations(t *testing.T) {
+	a := roaringOf(1, 2, 3, 70000, 200000)
+	b := roaringOf(2, 3, 4, 200000, 300000)
+
+	expectUint32s(t, iterUint32s(a.And(b).Iterator()), 2, 3, 200000)
+	expectUint32s(t, iterUint32s(a.Or(b).Iterator()), 1, 2, 3, 4, 70000, 200000, 300000)
+	expectUint32s(t, iterUint32s(a.Xor(b).Iterator()), 1, 4, 70000, 300000)
+	expectUint32s(t, iterUint32s(a.AndNot(b).Iterator()), 1, 70000)
+
+	if a.And(b).Len() != 3 || a.Xor(a).Len() != 0 || len(a.Xor(a).keys) != 0 {
+		t.Fatal("expected sizes to be tracked and empty containers dropped")
+	}
+
+	// Operations don't change their inputs
+	expectUint32s(t, iterUint32s(a.Iterator()), 1, 2, 3, 70000, 200000)
+
+	if !a.Equal(a.Clone()) || a.Equal(b) || !a.And(b).Equal(b.And(a)) {
+		t.Fail()
+	}
+}
+
+func TestRoaringDenseRangeUsesRuns(t *testing.T) {
+	roaring := NewRoaring()
+
+	for v := uint32(100000); v < 300000; v++ {
+		roaring.Insert(v)
+	}
+
+	for _, container := range roaring.containers {
+		if _, isBitmap := container.(*bitmapContainer); !isBitmap {
+			t.Fatal("expected dense containers to be bitmaps")
+		}
+	}
+
+	before := roaring.Clone()
+	roaring.RunOptimize()
+
+	for _, container := range roaring.containers {
+		if _, isRun := container.(*runContainer); !isRun {
+			t.Fatal("expected RunOptimize to turn a solid range into runs")
+		}
+	}
+
+	if !roaring.Equal(before) || roaring.Len() != 200000 {
+		t.Fatal("expected RunOptimize to keep the same values")
+	}
+
+	// Changing a run container still works
+	roaring.Remove(150000)
+	roaring.Insert(5)
+
+	if roaring.Contains(150000) || !roaring.Contains(150001) || !roaring.Contains(5) || roaring.Len() != 200000 {
+		t.Fail()
+	}
+}
+
+func TestRoaringRandomAgainstMap(t *testing.T) {
+	a, b := NewRoaring(), NewRoaring()
+	inA, inB := make(map[uint32]bool), make(map[uint32]bool)
+
+	// Mix a dense range with sparse values so every kind of container gets made
+	randomValue := func() uint32 {
+		if rand.Intn(2) == 0 {
+			return uint32(rand.Intn(20000))
+		}
+		return rand.Uint32()
+	}
+
+	for i := 0; i < 20000; i++ {
+		v := randomValue()
+
+		if rand.Intn(4) == 0 {
+			a.Remove(v)
+			delete(inA, v)
+		} else {
+			a.Insert(v)
+			inA[v] = true
+		}
+
+		b.Insert(randomValue())
+	}
+
+	for _, v := range iterUint32s(b.Iterator()) {
+		inB[v] = true
+	}
+
+	a.RunOptimize()
+
+	expected := func(keep func(x, y bool) bool) []uint32 {
+		values := make([]uint32, 0)
+
+		for v := range inA {
+			if keep(true, inB[v]) {
+				values = append(values, v)
+			}
+		}
+
+		for v := range inB {
+			if !inA[v] && keep(false, true) {
+				values = append(values, v)
+			}
+		}
+
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+		return values
+	}
+
+	if a.Len() != len(inA) {
+		t.Fatalf("expected %d values but got %d", len(inA), a.Len())
+	}
+
+	expectUint32s(t, iterUint32s(a.Iterator()), expected(func(x, y bool) bool { return x })...)
+	expectUint32s(t, iterUint32s(a.And(b).Iterator()), expected(func(x, y bool) bool { return x && y })...)
+	expectUint32s(t, iterUint32s(a.Or(b).Iterator()), expected(func(x, y bool) bool { return x || y })...)
+	expectUint32s(t, iterUint32s(a.Xor(b).Iterator()), expected(func(x, y bool) bool { return x != y })...)
+	expectUint32s(t, iterUint32s(a.AndNot(b).Iterator()), expected(func(x, y bool) bool { return x && !y })...)
+}
+
+func TestRoaringBinaryRoundTrip(t *testing.T) {
+	original := roaringOf(1, 5, 1<<20, 0xFFFFFFFF)
+
+	for v := uint32(70000); v < 80000; v++ {
+		original.Insert(v)
+	}
+
+	for v := uint32(200000); v < 262144; v += 3 {
+		original.Insert(v)
+	}
+
+	original.RunOptimize()
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := NewRoaring()
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.Equal(original) || decoded.Len() != original.Len() {
+		t.Fatal("expected decoded set to match the original")
+	}
+
+	if decoded.UnmarshalBinary(data[:len(data)-1]) == nil {
+		t.Fatal("expected error for truncated data")
+	}
+
+	if decoded.UnmarshalBinary(append(data, 0)) == nil {
+		t.Fatal("expected error for trailing data")
+	}
+
+	if decoded.UnmarshalBinary(nil) == nil {
+		t.Fatal("expected error for empty data")
+	}
+
+	// A failed unmarshal leaves the set alone
+	if !decoded.Equal(original) {
+		t.Fail()
+	}
+}
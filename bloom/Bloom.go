@@ -0,0 +1,50 @@
+package bloom
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ZacharyDuve/godatacollections/internal/hashmix"
+)
+
+// optimalSize returns the number of slots and hashes for a filter holding capacity keys at falsePositiveRate
+// Uses the usual m = -n ln(p) / ln(2)^2 and k = m/n ln(2)
+func optimalSize(capacity uint64, falsePositiveRate float64) (uint64, uint64, error) {
+	if capacity < 1 {
+		return 0, 0, fmt.Errorf("unable to size filter with capacity %d, needs to be at least 1", capacity)
+	}
+
+	if !(falsePositiveRate > 0 && falsePositiveRate < 1) {
+		return 0, 0, fmt.Errorf("unable to size filter with false positive rate %v, needs to be between 0 and 1", falsePositiveRate)
+	}
+
+	numSlots := uint64(math.Ceil(-float64(capacity) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	numHashes := uint64(math.Round(float64(numSlots) / float64(capacity) * math.Ln2))
+
+	return numSlots, max(numHashes, 1), nil
+}
+
+func checkSize(numSlots, numHashes uint64) error {
+	if numSlots < 1 {
+		return errors.New("unable to create filter with no slots")
+	}
+
+	if numHashes < 1 {
+		return errors.New("unable to create filter with no hashes")
+	}
+
+	return nil
+}
+
+// locations calls f with each of the numHashes slots for hash
+// The slots come from double hashing so only the one hash of the key is needed
+func locations(hash, numHashes, numSlots uint64, f func(slot uint64)) {
+	first := hashmix.Mix(hash)
+	// Made odd only so that it is never 0, which would put every hash on the same slot
+	second := hashmix.Mix(first) | 1
+
+	for i := uint64(0); i < numHashes; i++ {
+		f((first + i*second) % numSlots)
+	}
+}
@@ -0,0 +1,180 @@
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+const wordSize = 64
+
+// BloomFilter is a set of keys that can say a key was definitely never added or might have been added
+// It never gives a false negative so it is good for skipping lookups in something slow like a disk backed Set
+// It takes a fixed number of bits no matter how many keys are added, adding more than it was sized for raises the false positive rate
+// Keys are never stored, only their hashes, so there is no way to remove or list them
+type BloomFilter[K any] struct {
+	hashFunc  func(K) uint64
+	words     []uint64
+	numBits   uint64
+	numHashes uint64
+}
+
+// NewBloomFilter creates a new empty BloomFilter sized so that capacity keys give about falsePositiveRate false positives
+func NewBloomFilter[K any](capacity uint64, falsePositiveRate float64, hashFunc func(K) uint64) (*BloomFilter[K], error) {
+	numBits, numHashes, err := optimalSize(capacity, falsePositiveRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBloomFilterWithSize(numBits, numHashes, hashFunc)
+}
+
+// NewBloomFilterWithSize creates a new empty BloomFilter with exactly numBits bits and numHashes hashes per key
+func NewBloomFilterWithSize[K any](numBits, numHashes uint64, hashFunc func(K) uint64) (*BloomFilter[K], error) {
+	if hashFunc == nil {
+		return nil, errors.New("unable to create BloomFilter without a function to hash Keys")
+	}
+
+	if err := checkSize(numBits, numHashes); err != nil {
+		return nil, err
+	}
+
+	return &BloomFilter[K]{hashFunc: hashFunc, words: make([]uint64, wordsFor(numBits)), numBits: numBits, numHashes: numHashes}, nil
+}
+
+// wordsFor returns how many words hold numBits bits
+func wordsFor(numBits uint64) int {
+	if numBits%wordSize == 0 {
+		return int(numBits / wordSize)
+	}
+
+	return int(numBits/wordSize) + 1
+}
+
+// NumBits returns the number of bits the filter uses
+func (this *BloomFilter[K]) NumBits() uint64 {
+	return this.numBits
+}
+
+// NumHashes returns the number of bits set for each key
+func (this *BloomFilter[K]) NumHashes() uint64 {
+	return this.numHashes
+}
+
+// Add records key as being in the filter
+func (this *BloomFilter[K]) Add(key K) {
+	locations(this.hashFunc(key), this.numHashes, this.numBits, func(bit uint64) {
+		this.words[bit/wordSize] |= 1 << (bit % wordSize)
+	})
+}
+
+// MightContain returns false if key was definitely never added and true if it probably was
+func (this *BloomFilter[K]) MightContain(key K) bool {
+	found := true
+
+	locations(this.hashFunc(key), this.numHashes, this.numBits, func(bit uint64) {
+		found = found && this.words[bit/wordSize]&(1<<(bit%wordSize)) != 0
+	})
+
+	return found
+}
+
+// ApproximateLen estimates how many distinct keys have been added from how many bits are set
+func (this *BloomFilter[K]) ApproximateLen() uint64 {
+	setBits := 0
+
+	for _, word := range this.words {
+		setBits += bits.OnesCount64(word)
+	}
+
+	if uint64(setBits) == this.numBits {
+		// Every bit is on so there is no telling, the filter is useless at this point
+		return math.MaxUint64
+	}
+
+	m, k := float64(this.numBits), float64(this.numHashes)
+
+	return uint64(math.Round(-m / k * math.Log(1-float64(setBits)/m)))
+}
+
+// Clear removes every key
+func (this *BloomFilter[K]) Clear() {
+	clear(this.words)
+}
+
+// Union returns a new filter that might contain any key that either filter might contain
+// Both filters need the same number of bits and hashes and should use the same hash function
+func (this *BloomFilter[K]) Union(other *BloomFilter[K]) (*BloomFilter[K], error) {
+	if this.numBits != other.numBits || this.numHashes != other.numHashes {
+		return nil, fmt.Errorf("unable to union BloomFilter of %d bits and %d hashes with one of %d bits and %d hashes", this.numBits, this.numHashes, other.numBits, other.numHashes)
+	}
+
+	result := &BloomFilter[K]{hashFunc: this.hashFunc, words: make([]uint64, len(this.words)), numBits: this.numBits, numHashes: this.numHashes}
+
+	for i := range result.words {
+		result.words[i] = this.words[i] | other.words[i]
+	}
+
+	return result, nil
+}
+
+// MarshalBinary writes the number of bits and hashes as uvarints followed by each word of bits as 8 little endian bytes
+// The hash function is not written so it is up to the reader to use the same one
+func (this *BloomFilter[K]) MarshalBinary() ([]byte, error) {
+	data := binary.AppendUvarint(nil, this.numBits)
+	data = binary.AppendUvarint(data, this.numHashes)
+
+	for _, word := range this.words {
+		data = binary.LittleEndian.AppendUint64(data, word)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary replaces the filter with one written by MarshalBinary, keeping its hash function
+func (this *BloomFilter[K]) UnmarshalBinary(data []byte) error {
+	numBits, numHashes, data, err := readSize(data)
+	if err != nil {
+		return err
+	}
+
+	wordCount := uint64(wordsFor(numBits))
+
+	if len(data)%8 != 0 || wordCount != uint64(len(data)/8) {
+		return fmt.Errorf("unable to unmarshal BloomFilter expecting %d words but got %d bytes", wordCount, len(data))
+	}
+
+	words := make([]uint64, wordCount)
+
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+
+	this.words, this.numBits, this.numHashes = words, numBits, numHashes
+
+	return nil
+}
+
+// readSize reads the number of slots and hashes written at the start of a filter and returns the rest of data
+func readSize(data []byte) (uint64, uint64, []byte, error) {
+	numSlots, n := binary.Uvarint(data)
+
+	if n <= 0 {
+		return 0, 0, nil, errors.New("unable to unmarshal filter with bad size")
+	}
+
+	data = data[n:]
+	numHashes, n := binary.Uvarint(data)
+
+	if n <= 0 {
+		return 0, 0, nil, errors.New("unable to unmarshal filter with bad number of hashes")
+	}
+
+	if err := checkSize(numSlots, numHashes); err != nil {
+		return 0, 0, nil, err
+	}
+
+	return numSlots, numHashes, data[n:], nil
+}
@@ -0,0 +1,195 @@
+package bloom
+
+import (
+	"encoding"
+	"encoding/binary"
+	"hash/fnv"
+	"testing"
+)
+
+var _ encoding.BinaryMarshaler = &BloomFilter[int]{}
+var _ encoding.BinaryUnmarshaler = &BloomFilter[int]{}
+
+func hashInt(i int) uint64 {
+	hash := fnv.New64a()
+	hash.Write(binary.LittleEndian.AppendUint64(nil, uint64(i)))
+
+	return hash.Sum64()
+}
+
+// identityHash is about as bad a hash as there is, to check that keys still get spread out
+func identityHash(i int) uint64 {
+	return uint64(i)
+}
+
+func TestNewBloomFilterErrors(t *testing.T) {
+	if _, err := NewBloomFilter[int](100, 0.01, nil); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewBloomFilter(0, 0.01, hashInt); err == nil {
+		t.Fail()
+	}
+
+	for _, rate := range []float64{0, 1, -0.5, 2} {
+		if _, err := NewBloomFilter(100, rate, hashInt); err == nil {
+			t.Fatalf("expected error for rate %v", rate)
+		}
+	}
+
+	if _, err := NewBloomFilterWithSize(0, 3, hashInt); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewBloomFilterWithSize(100, 0, hashInt); err == nil {
+		t.Fail()
+	}
+}
+
+func TestBloomFilterSizing(t *testing.T) {
+	filter, _ := NewBloomFilter(1000, 0.01, hashInt)
+
+	// About 9.6 bits and 7 hashes per key for 1%
+	if filter.NumBits() != 9586 || filter.NumHashes() != 7 {
+		t.Fatalf("expected 9586 bits and 7 hashes but got %d and %d", filter.NumBits(), filter.NumHashes())
+	}
+}
+
+func TestBloomFilterNoFalseNegatives(t *testing.T) {
+	for _, hashFunc := range []func(int) uint64{hashInt, identityHash} {
+		filter, _ := NewBloomFilter(1000, 0.01, hashFunc)
+
+		for i := 0; i < 1000; i++ {
+			filter.Add(i * 7)
+		}
+
+		for i := 0; i < 1000; i++ {
+			if !filter.MightContain(i * 7) {
+				t.Fatalf("false negative for %d", i*7)
+			}
+		}
+	}
+}
+
+// falsePositiveRate adds capacity keys then returns how often keys that were never added are reported
+func falsePositiveRate(mightContain func(int) bool, add func(int), capacity int) float64 {
+	for i := 0; i < capacity; i++ {
+		add(i)
+	}
+
+	falsePositives := 0
+	trials := 100000
+
+	for i := capacity; i < capacity+trials; i++ {
+		if mightContain(i) {
+			falsePositives++
+		}
+	}
+
+	return float64(falsePositives) / float64(trials)
+}
+
+func TestBloomFilterFalsePositiveRate(t *testing.T) {
+	for _, hashFunc := range []func(int) uint64{hashInt, identityHash} {
+		for _, rate := range []float64{0.1, 0.01, 0.001} {
+			filter, _ := NewBloomFilter(10000, rate, hashFunc)
+			actual := falsePositiveRate(filter.MightContain, filter.Add, 10000)
+
+			if actual > 1.5*rate {
+				t.Fatalf("expected false positive rate near %v but got %v", rate, actual)
+			}
+		}
+	}
+}
+
+func TestBloomFilterApproximateLen(t *testing.T) {
+	filter, _ := NewBloomFilter(10000, 0.01, hashInt)
+
+	if filter.ApproximateLen() != 0 {
+		t.Fail()
+	}
+
+	for i := 0; i < 5000; i++ {
+		// Adding twice doesn't count twice
+		filter.Add(i)
+		filter.Add(i)
+	}
+
+	if approx := filter.ApproximateLen(); approx < 4800 || approx > 5200 {
+		t.Fatalf("expected about 5000 but got %d", approx)
+	}
+
+	filter.Clear()
+
+	if filter.MightContain(1) || filter.ApproximateLen() != 0 {
+		t.Fail()
+	}
+}
+
+func TestBloomFilterUnion(t *testing.T) {
+	a, _ := NewBloomFilter(1000, 0.01, hashInt)
+	b, _ := NewBloomFilter(1000, 0.01, hashInt)
+
+	for i := 0; i < 500; i++ {
+		a.Add(i)
+		b.Add(i + 500)
+	}
+
+	union, err := a.Union(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		if !union.MightContain(i) {
+			t.Fatalf("false negative for %d", i)
+		}
+	}
+
+	// Inputs are left alone
+	if a.ApproximateLen() > 550 {
+		t.Fail()
+	}
+
+	other, _ := NewBloomFilter(5000, 0.01, hashInt)
+
+	if _, err := a.Union(other); err == nil {
+		t.Fatal("expected error for filters of different sizes")
+	}
+}
+
+func TestBloomFilterBinaryRoundTrip(t *testing.T) {
+	original, _ := NewBloomFilter(1000, 0.01, hashInt)
+
+	for i := 0; i < 1000; i += 3 {
+		original.Add(i)
+	}
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, _ := NewBloomFilterWithSize(1, 1, hashInt)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.NumBits() != original.NumBits() || decoded.NumHashes() != original.NumHashes() {
+		t.Fatal("expected size to be decoded")
+	}
+
+	for i := 0; i < 2000; i++ {
+		if decoded.MightContain(i) != original.MightContain(i) {
+			t.Fatalf("decoded filter disagrees on %d", i)
+		}
+	}
+
+	if decoded.UnmarshalBinary(data[:len(data)-1]) == nil {
+		t.Fatal("expected error for truncated data")
+	}
+
+	if decoded.UnmarshalBinary(nil) == nil {
+		t.Fatal("expected error for empty data")
+	}
+}
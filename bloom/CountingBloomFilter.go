@@ -0,0 +1,136 @@
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// CountingBloomFilter is a BloomFilter that keeps a count in each slot instead of a bit so that keys can be removed
+// It takes 8 times the memory of a BloomFilter with the same false positive rate
+// A count that reaches 255 sticks there as it no longer knows how many keys use it, so removing never causes false negatives
+type CountingBloomFilter[K any] struct {
+	hashFunc  func(K) uint64
+	counts    []uint8
+	numHashes uint64
+}
+
+// NewCountingBloomFilter creates a new empty CountingBloomFilter
+// Arguments are the same as for NewBloomFilter
+func NewCountingBloomFilter[K any](capacity uint64, falsePositiveRate float64, hashFunc func(K) uint64) (*CountingBloomFilter[K], error) {
+	numCounts, numHashes, err := optimalSize(capacity, falsePositiveRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCountingBloomFilterWithSize(numCounts, numHashes, hashFunc)
+}
+
+// NewCountingBloomFilterWithSize creates a new empty CountingBloomFilter with exactly numCounts counts and numHashes hashes per key
+func NewCountingBloomFilterWithSize[K any](numCounts, numHashes uint64, hashFunc func(K) uint64) (*CountingBloomFilter[K], error) {
+	if hashFunc == nil {
+		return nil, errors.New("unable to create CountingBloomFilter without a function to hash Keys")
+	}
+
+	if err := checkSize(numCounts, numHashes); err != nil {
+		return nil, err
+	}
+
+	return &CountingBloomFilter[K]{hashFunc: hashFunc, counts: make([]uint8, numCounts), numHashes: numHashes}, nil
+}
+
+// NumCounts returns the number of counts the filter uses
+func (this *CountingBloomFilter[K]) NumCounts() uint64 {
+	return uint64(len(this.counts))
+}
+
+// NumHashes returns the number of counts used for each key
+func (this *CountingBloomFilter[K]) NumHashes() uint64 {
+	return this.numHashes
+}
+
+// Add records key as being in the filter
+// Adding the same key twice means it needs to be removed twice
+func (this *CountingBloomFilter[K]) Add(key K) {
+	locations(this.hashFunc(key), this.numHashes, uint64(len(this.counts)), func(slot uint64) {
+		if this.counts[slot] < 255 {
+			this.counts[slot]++
+		}
+	})
+}
+
+// MightContain returns false if key was definitely never added, or was removed as many times as it was added, and true if it probably is in the filter
+func (this *CountingBloomFilter[K]) MightContain(key K) bool {
+	found := true
+
+	locations(this.hashFunc(key), this.numHashes, uint64(len(this.counts)), func(slot uint64) {
+		found = found && this.counts[slot] != 0
+	})
+
+	return found
+}
+
+// Remove takes key out of the filter
+// Returns NotFoundError if key is definitely not in the filter
+// Only keys that were added should be removed, removing a key that is only a false positive takes counts away from other keys
+func (this *CountingBloomFilter[K]) Remove(key K) error {
+	if !this.MightContain(key) {
+		return godatacollections.NotFoundError()
+	}
+
+	locations(this.hashFunc(key), this.numHashes, uint64(len(this.counts)), func(slot uint64) {
+		if this.counts[slot] < 255 {
+			this.counts[slot]--
+		}
+	})
+
+	return nil
+}
+
+// Clear removes every key
+func (this *CountingBloomFilter[K]) Clear() {
+	clear(this.counts)
+}
+
+// Union returns a new filter holding the keys of both filters, as if every key added to either was added to it
+// Both filters need the same number of counts and hashes and should use the same hash function
+func (this *CountingBloomFilter[K]) Union(other *CountingBloomFilter[K]) (*CountingBloomFilter[K], error) {
+	if len(this.counts) != len(other.counts) || this.numHashes != other.numHashes {
+		return nil, fmt.Errorf("unable to union CountingBloomFilter of %d counts and %d hashes with one of %d counts and %d hashes", len(this.counts), this.numHashes, len(other.counts), other.numHashes)
+	}
+
+	result := &CountingBloomFilter[K]{hashFunc: this.hashFunc, counts: make([]uint8, len(this.counts)), numHashes: this.numHashes}
+
+	for i := range result.counts {
+		result.counts[i] = uint8(min(int(this.counts[i])+int(other.counts[i]), 255))
+	}
+
+	return result, nil
+}
+
+// MarshalBinary writes the number of counts and hashes as uvarints followed by each count as a byte
+// The hash function is not written so it is up to the reader to use the same one
+func (this *CountingBloomFilter[K]) MarshalBinary() ([]byte, error) {
+	data := binary.AppendUvarint(nil, uint64(len(this.counts)))
+	data = binary.AppendUvarint(data, this.numHashes)
+
+	return append(data, this.counts...), nil
+}
+
+// UnmarshalBinary replaces the filter with one written by MarshalBinary, keeping its hash function
+func (this *CountingBloomFilter[K]) UnmarshalBinary(data []byte) error {
+	numCounts, numHashes, data, err := readSize(data)
+	if err != nil {
+		return err
+	}
+
+	if numCounts != uint64(len(data)) {
+		return fmt.Errorf("unable to unmarshal CountingBloomFilter expecting %d counts but got %d bytes", numCounts, len(data))
+	}
+
+	this.counts, this.numHashes = append([]uint8(nil), data...), numHashes
+
+	return nil
+}
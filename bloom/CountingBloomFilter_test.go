@@ -0,0 +1,159 @@
+package bloom
+
+import (
+	"encoding"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+var _ encoding.BinaryMarshaler = &CountingBloomFilter[int]{}
+var _ encoding.BinaryUnmarshaler = &CountingBloomFilter[int]{}
+
+func TestNewCountingBloomFilterErrors(t *testing.T) {
+	if _, err := NewCountingBloomFilter[int](100, 0.01, nil); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewCountingBloomFilter(100, 1, hashInt); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewCountingBloomFilterWithSize(0, 1, hashInt); err == nil {
+		t.Fail()
+	}
+}
+
+func TestCountingBloomFilterRemove(t *testing.T) {
+	filter, _ := NewCountingBloomFilter(1000, 0.01, hashInt)
+
+	for i := 0; i < 1000; i++ {
+		filter.Add(i)
+	}
+
+	for i := 0; i < 1000; i += 2 {
+		if err := filter.Remove(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Removing never causes false negatives for what is left
+	for i := 1; i < 1000; i += 2 {
+		if !filter.MightContain(i) {
+			t.Fatalf("false negative for %d", i)
+		}
+	}
+
+	removed := 0
+
+	for i := 0; i < 1000; i += 2 {
+		if !filter.MightContain(i) {
+			removed++
+		}
+	}
+
+	if removed < 450 {
+		t.Fatalf("expected most removed keys to be gone but only %d were", removed)
+	}
+
+	for i := 1; i < 1000; i += 2 {
+		filter.Remove(i)
+	}
+
+	if err := filter.Remove(1); !godatacollections.IsNotFoundError(err) {
+		t.Fatal("expected NotFoundError removing from an empty filter")
+	}
+}
+
+func TestCountingBloomFilterDuplicatesNeedRemovingTwice(t *testing.T) {
+	filter, _ := NewCountingBloomFilter(100, 0.01, hashInt)
+
+	filter.Add(5)
+	filter.Add(5)
+	filter.Remove(5)
+
+	if !filter.MightContain(5) {
+		t.Fatal("expected key added twice to survive one remove")
+	}
+
+	filter.Remove(5)
+
+	if filter.MightContain(5) {
+		t.Fail()
+	}
+}
+
+func TestCountingBloomFilterSaturates(t *testing.T) {
+	filter, _ := NewCountingBloomFilterWithSize(8, 1, identityHash)
+
+	for i := 0; i < 300; i++ {
+		filter.Add(1)
+	}
+
+	for i := 0; i < 300; i++ {
+		filter.Remove(1)
+	}
+
+	if !filter.MightContain(1) {
+		t.Fatal("expected a saturated count to stay set")
+	}
+}
+
+func TestCountingBloomFilterFalsePositiveRate(t *testing.T) {
+	filter, _ := NewCountingBloomFilter(10000, 0.01, hashInt)
+
+	if actual := falsePositiveRate(filter.MightContain, filter.Add, 10000); actual > 0.015 {
+		t.Fatalf("expected false positive rate near 0.01 but got %v", actual)
+	}
+}
+
+func TestCountingBloomFilterUnion(t *testing.T) {
+	a, _ := NewCountingBloomFilter(1000, 0.01, hashInt)
+	b, _ := NewCountingBloomFilter(1000, 0.01, hashInt)
+
+	a.Add(1)
+	b.Add(1)
+	b.Add(2)
+
+	union, err := a.Union(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	union.Remove(1)
+
+	if !union.MightContain(1) || !union.MightContain(2) {
+		t.Fatal("expected union to count keys from both filters")
+	}
+
+	other, _ := NewCountingBloomFilter(10, 0.01, hashInt)
+
+	if _, err := a.Union(other); err == nil {
+		t.Fail()
+	}
+}
+
+func TestCountingBloomFilterBinaryRoundTrip(t *testing.T) {
+	original, _ := NewCountingBloomFilter(1000, 0.01, hashInt)
+
+	for i := 0; i < 500; i++ {
+		original.Add(i)
+	}
+
+	data, _ := original.MarshalBinary()
+	decoded, _ := NewCountingBloomFilterWithSize(1, 1, hashInt)
+
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 500; i++ {
+		if err := decoded.Remove(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if decoded.UnmarshalBinary(data[:len(data)-1]) == nil || decoded.UnmarshalBinary(nil) == nil {
+		t.Fatal("expected error for bad data")
+	}
+}
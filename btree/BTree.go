@@ -0,0 +1,532 @@
+package btree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// BTree is an in memory B-tree
+// Each node holds many keys in a slice instead of tree.BST's one key per node, which means far fewer
+// pointers to chase and much better use of the cpu cache when there are millions of small keys
+type BTree[K, T any] struct {
+	kCompFunc func(K, K) int
+	tToKFunc  func(T) K
+	zeroValue T
+	// degree is the minimum degree. Every node other than the root has between degree-1 and 2*degree-1 keys
+	degree int
+	root   *bTreeNode[K, T]
+	size   int
+}
+
+type bTreeNode[K, T any] struct {
+	keys []K
+	ts   []T
+	// children is nil for leaves, otherwise there is always one more child than keys
+	children []*bTreeNode[K, T]
+}
+
+func (this *bTreeNode[K, T]) isLeaf() bool {
+	return this.children == nil
+}
+
+// NewBTree creates a new empty B-tree
+// degree is the minimum degree of the tree and must be at least 2
+//
+//	Nodes hold up to 2*degree-1 keys so larger degrees mean wider and shallower trees
+//
+// kCompFunc, tToKFunc and tZeroValue are the same as for tree.NewBST
+func NewBTree[K, T any](degree int, kCompFunc func(K, K) int, tToKFunc func(T) K, tZeroValue T) (*BTree[K, T], error) {
+	if degree < 2 {
+		return nil, fmt.Errorf("unable to create BTree with degree %d, must be at least 2", degree)
+	}
+
+	if kCompFunc == nil {
+		return nil, errors.New("unable to create BTree without a function to compare Keys")
+	}
+
+	if tToKFunc == nil {
+		return nil, errors.New("unable to create BTree without a function to convert T to a Key")
+	}
+
+	return &BTree[K, T]{kCompFunc: kCompFunc, tToKFunc: tToKFunc, zeroValue: tZeroValue, degree: degree}, nil
+}
+
+// Len returns the number of items in the tree
+func (this *BTree[K, T]) Len() int {
+	return this.size
+}
+
+// search returns the index of the first key in node that is >= key and if that key is equal to key
+func (this *BTree[K, T]) search(node *bTreeNode[K, T], key K) (int, bool) {
+	low, high := 0, len(node.keys)
+
+	for low < high {
+		mid := int(uint(low+high) >> 1)
+		if this.kCompFunc(node.keys[mid], key) < 0 {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+
+	return low, low < len(node.keys) && this.kCompFunc(node.keys[low], key) == 0
+}
+
+func (this *BTree[K, T]) maxKeys() int {
+	return 2*this.degree - 1
+}
+
+// Insert splits full nodes on the way down so that there is always room to insert at the leaf
+func (this *BTree[K, T]) Insert(newT T) error {
+	newKey := this.tToKFunc(newT)
+
+	if this.root == nil {
+		this.root = &bTreeNode[K, T]{keys: []K{newKey}, ts: []T{newT}}
+		this.size++
+		return nil
+	}
+
+	if len(this.root.keys) == this.maxKeys() {
+		// Full root so the tree grows a level
+		newRoot := &bTreeNode[K, T]{children: []*bTreeNode[K, T]{this.root}}
+		this.splitChild(newRoot, 0)
+		this.root = newRoot
+	}
+
+	curNode := this.root
+
+	for {
+		idx, found := this.search(curNode, newKey)
+
+		if found {
+			return fmt.Errorf("unable to insert duplicate T for key %v", newKey)
+		}
+
+		if curNode.isLeaf() {
+			curNode.keys = insertAt(curNode.keys, idx, newKey)
+			curNode.ts = insertAt(curNode.ts, idx, newT)
+			this.size++
+			return nil
+		}
+
+		if len(curNode.children[idx].keys) == this.maxKeys() {
+			this.splitChild(curNode, idx)
+
+			// The middle key of the child moved up to idx so figure out which half we belong in
+			curComp := this.kCompFunc(newKey, curNode.keys[idx])
+			if curComp == 0 {
+				return fmt.Errorf("unable to insert duplicate T for key %v", newKey)
+			} else if curComp > 0 {
+				idx++
+			}
+		}
+
+		curNode = curNode.children[idx]
+	}
+}
+
+// splitChild splits the full child at idx in two, moving its middle key up into parent
+func (this *BTree[K, T]) splitChild(parent *bTreeNode[K, T], idx int) {
+	child := parent.children[idx]
+	mid := this.degree - 1
+
+	right := &bTreeNode[K, T]{
+		keys: append([]K(nil), child.keys[mid+1:]...),
+		ts:   append([]T(nil), child.ts[mid+1:]...),
+	}
+
+	if !child.isLeaf() {
+		right.children = append([]*bTreeNode[K, T](nil), child.children[mid+1:]...)
+		clear(child.children[mid+1:])
+		child.children = child.children[:mid+1]
+	}
+
+	parent.keys = insertAt(parent.keys, idx, child.keys[mid])
+	parent.ts = insertAt(parent.ts, idx, child.ts[mid])
+	parent.children = insertAt(parent.children, idx+1, right)
+
+	clear(child.ts[mid:])
+	child.keys = child.keys[:mid]
+	child.ts = child.ts[:mid]
+}
+
+func (this *BTree[K, T]) Contains(key K) bool {
+	_, found := this.get(key)
+
+	return found
+}
+
+func (this *BTree[K, T]) GetByKey(key K) T {
+	t, _ := this.get(key)
+
+	return t
+}
+
+func (this *BTree[K, T]) get(key K) (T, bool) {
+	curNode := this.root
+
+	for curNode != nil {
+		idx, found := this.search(curNode, key)
+
+		if found {
+			return curNode.ts[idx], true
+		}
+
+		if curNode.isLeaf() {
+			break
+		}
+
+		curNode = curNode.children[idx]
+	}
+
+	return this.zeroValue, false
+}
+
+// Remove makes sure that every node it goes down into has at least degree keys
+// so that a key can always be taken out without the node becoming too small
+func (this *BTree[K, T]) Remove(key K) error {
+	if this.root == nil {
+		return fmt.Errorf("unable to delete item with key %v due to it not existing in tree", key)
+	}
+
+	removed := this.remove(this.root, key)
+
+	// Merges on the way down happen even when the key turns out to be missing
+	if len(this.root.keys) == 0 {
+		// Root has been emptied out by a merge (or the last item was removed) so the tree shrinks a level
+		if this.root.isLeaf() {
+			this.root = nil
+		} else {
+			this.root = this.root.children[0]
+		}
+	}
+
+	if !removed {
+		return fmt.Errorf("unable to delete item with key %v due to it not existing in tree", key)
+	}
+
+	this.size--
+
+	return nil
+}
+
+func (this *BTree[K, T]) remove(node *bTreeNode[K, T], key K) bool {
+	idx, found := this.search(node, key)
+
+	if node.isLeaf() {
+		if !found {
+			return false
+		}
+
+		node.keys = removeAt(node.keys, idx)
+		node.ts = removeAt(node.ts, idx)
+		return true
+	}
+
+	if found {
+		left := node.children[idx]
+		right := node.children[idx+1]
+
+		if len(left.keys) >= this.degree {
+			// Replace with the predecessor then remove the predecessor from the left side
+			predecessor := left
+			for !predecessor.isLeaf() {
+				predecessor = predecessor.children[len(predecessor.children)-1]
+			}
+			last := len(predecessor.keys) - 1
+			node.keys[idx], node.ts[idx] = predecessor.keys[last], predecessor.ts[last]
+			return this.remove(left, node.keys[idx])
+		}
+
+		if len(right.keys) >= this.degree {
+			// Replace with the successor then remove the successor from the right side
+			successor := right
+			for !successor.isLeaf() {
+				successor = successor.children[0]
+			}
+			node.keys[idx], node.ts[idx] = successor.keys[0], successor.ts[0]
+			return this.remove(right, node.keys[idx])
+		}
+
+		// Both sides are as small as they can be so combine them with the key and remove from that
+		this.mergeChildren(node, idx)
+		return this.remove(left, key)
+	}
+
+	if len(node.children[idx].keys) < this.degree {
+		idx = this.growChild(node, idx)
+	}
+
+	return this.remove(node.children[idx], key)
+}
+
+// growChild makes sure the child at idx has at least degree keys by borrowing from or merging with a sibling
+// Returns the index of the child that now covers the keys of the old child
+func (this *BTree[K, T]) growChild(parent *bTreeNode[K, T], idx int) int {
+	child := parent.children[idx]
+
+	if idx > 0 && len(parent.children[idx-1].keys) >= this.degree {
+		// Rotate right: parent key comes down to child and left sibling's last key goes up
+		left := parent.children[idx-1]
+		last := len(left.keys) - 1
+
+		child.keys = insertAt(child.keys, 0, parent.keys[idx-1])
+		child.ts = insertAt(child.ts, 0, parent.ts[idx-1])
+		parent.keys[idx-1], parent.ts[idx-1] = left.keys[last], left.ts[last]
+		left.keys = removeAt(left.keys, last)
+		left.ts = removeAt(left.ts, last)
+
+		if !left.isLeaf() {
+			child.children = insertAt(child.children, 0, left.children[last+1])
+			left.children = removeAt(left.children, last+1)
+		}
+
+		return idx
+	}
+
+	if idx < len(parent.children)-1 && len(parent.children[idx+1].keys) >= this.degree {
+		// Rotate left: parent key comes down to child and right sibling's first key goes up
+		right := parent.children[idx+1]
+
+		child.keys = append(child.keys, parent.keys[idx])
+		child.ts = append(child.ts, parent.ts[idx])
+		parent.keys[idx], parent.ts[idx] = right.keys[0], right.ts[0]
+		right.keys = removeAt(right.keys, 0)
+		right.ts = removeAt(right.ts, 0)
+
+		if !right.isLeaf() {
+			child.children = append(child.children, right.children[0])
+			right.children = removeAt(right.children, 0)
+		}
+
+		return idx
+	}
+
+	if idx < len(parent.children)-1 {
+		this.mergeChildren(parent, idx)
+		return idx
+	}
+
+	this.mergeChildren(parent, idx-1)
+	return idx - 1
+}
+
+// mergeChildren combines the child at idx, the key at idx and the child at idx+1 into the child at idx
+func (this *BTree[K, T]) mergeChildren(parent *bTreeNode[K, T], idx int) {
+	left := parent.children[idx]
+	right := parent.children[idx+1]
+
+	left.keys = append(left.keys, parent.keys[idx])
+	left.ts = append(left.ts, parent.ts[idx])
+	left.keys = append(left.keys, right.keys...)
+	left.ts = append(left.ts, right.ts...)
+
+	if !left.isLeaf() {
+		left.children = append(left.children, right.children...)
+	}
+
+	parent.keys = removeAt(parent.keys, idx)
+	parent.ts = removeAt(parent.ts, idx)
+	parent.children = removeAt(parent.children, idx+1)
+}
+
+// Min returns the item with the smallest key
+// Returns zero value and EmptyError if the tree is empty
+func (this *BTree[K, T]) Min() (T, error) {
+	if this.root == nil {
+		return this.zeroValue, godatacollections.EmptyError()
+	}
+
+	curNode := this.root
+	for !curNode.isLeaf() {
+		curNode = curNode.children[0]
+	}
+
+	return curNode.ts[0], nil
+}
+
+// Max returns the item with the largest key
+// Returns zero value and EmptyError if the tree is empty
+func (this *BTree[K, T]) Max() (T, error) {
+	if this.root == nil {
+		return this.zeroValue, godatacollections.EmptyError()
+	}
+
+	curNode := this.root
+	for !curNode.isLeaf() {
+		curNode = curNode.children[len(curNode.children)-1]
+	}
+
+	return curNode.ts[len(curNode.ts)-1], nil
+}
+
+// Floor returns the item with the largest key that is <= key
+// Returns zero value and NotFoundError if every key is greater
+func (this *BTree[K, T]) Floor(key K) (T, error) {
+	best, hasBest := this.zeroValue, false
+	curNode := this.root
+
+	for curNode != nil {
+		idx, found := this.search(curNode, key)
+
+		if found {
+			return curNode.ts[idx], nil
+		}
+
+		if idx > 0 {
+			// Key before idx is less than key so it is a candidate, but there could be a closer one below
+			best, hasBest = curNode.ts[idx-1], true
+		}
+
+		if curNode.isLeaf() {
+			break
+		}
+
+		curNode = curNode.children[idx]
+	}
+
+	if !hasBest {
+		return this.zeroValue, godatacollections.NotFoundError()
+	}
+
+	return best, nil
+}
+
+// Ceiling returns the item with the smallest key that is >= key
+// Returns zero value and NotFoundError if every key is less
+func (this *BTree[K, T]) Ceiling(key K) (T, error) {
+	best, hasBest := this.zeroValue, false
+	curNode := this.root
+
+	for curNode != nil {
+		idx, found := this.search(curNode, key)
+
+		if found {
+			return curNode.ts[idx], nil
+		}
+
+		if idx < len(curNode.keys) {
+			// Key at idx is greater than key so it is a candidate, but there could be a closer one below
+			best, hasBest = curNode.ts[idx], true
+		}
+
+		if curNode.isLeaf() {
+			break
+		}
+
+		curNode = curNode.children[idx]
+	}
+
+	if !hasBest {
+		return this.zeroValue, godatacollections.NotFoundError()
+	}
+
+	return best, nil
+}
+
+// Iterator returns an iterator over every item in key order
+func (this *BTree[K, T]) Iterator() godatacollections.Iterator[T] {
+	iter := &bTreeIterator[K, T]{bTree: this}
+
+	for curNode := this.root; curNode != nil; {
+		iter.path = append(iter.path, bTreeIterFrame[K, T]{node: curNode})
+
+		if curNode.isLeaf() {
+			break
+		}
+
+		curNode = curNode.children[0]
+	}
+
+	iter.settle()
+
+	return iter
+}
+
+// RangeIterator returns an iterator over the items with keys >= start and < end in key order
+func (this *BTree[K, T]) RangeIterator(start, end K) godatacollections.Iterator[T] {
+	iter := &bTreeIterator[K, T]{bTree: this, end: &end}
+
+	for curNode := this.root; curNode != nil; {
+		idx, found := this.search(curNode, start)
+		iter.path = append(iter.path, bTreeIterFrame[K, T]{node: curNode, idx: idx})
+
+		// Everything in the child at idx is less than the key at idx, so if that key is start there is no need to go down
+		if found || curNode.isLeaf() {
+			break
+		}
+
+		curNode = curNode.children[idx]
+	}
+
+	iter.settle()
+
+	return iter
+}
+
+// bTreeIterFrame is a node the iterator is part way through
+// idx is the next key to return from the node once the child before it has been finished
+type bTreeIterFrame[K, T any] struct {
+	node *bTreeNode[K, T]
+	idx  int
+}
+
+type bTreeIterator[K, T any] struct {
+	bTree *BTree[K, T]
+	path  []bTreeIterFrame[K, T]
+	// end is nil when there is no upper bound
+	end *K
+}
+
+// settle pops off every frame that has no keys left so that the top of path is the next item
+func (this *bTreeIterator[K, T]) settle() {
+	for len(this.path) > 0 {
+		top := this.path[len(this.path)-1]
+
+		if top.idx < len(top.node.keys) {
+			if this.end != nil && this.bTree.kCompFunc(top.node.keys[top.idx], *this.end) >= 0 {
+				this.path = this.path[:0]
+			}
+			return
+		}
+
+		this.path = this.path[:len(this.path)-1]
+	}
+}
+
+func (this *bTreeIterator[K, T]) Close() error {
+	return nil
+}
+
+func (this *bTreeIterator[K, T]) HasNext() bool {
+	return len(this.path) > 0
+}
+
+func (this *bTreeIterator[K, T]) Next() (T, error) {
+	if len(this.path) == 0 {
+		return this.bTree.zeroValue, errors.New("nothing left to iterate over")
+	}
+
+	top := &this.path[len(this.path)-1]
+	retT := top.node.ts[top.idx]
+	top.idx++
+
+	if !top.node.isLeaf() {
+		// Everything in the child after the key we just returned comes next, starting from its left most item
+		for curNode := top.node.children[top.idx]; curNode != nil; {
+			this.path = append(this.path, bTreeIterFrame[K, T]{node: curNode})
+
+			if curNode.isLeaf() {
+				break
+			}
+
+			curNode = curNode.children[0]
+		}
+	}
+
+	this.settle()
+
+	return retT, nil
+}
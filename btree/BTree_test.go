@@ -0,0 +1,422 @@
+package btree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+	"github.com/ZacharyDuve/godatacollections/tree"
+)
+
+func intBTree(degree int) *BTree[int, int] {
+	bTree, _ := NewBTree(degree, compInts, func(i int) int { return i }, -1)
+
+	return bTree
+}
+
+func intIterValues(iter godatacollections.Iterator[int]) []int {
+	values := make([]int, 0)
+
+	for iter.HasNext() {
+		curVal, _ := iter.Next()
+		values = append(values, curVal)
+	}
+
+	return values
+}
+
+// checkBTree makes sure that every node follows the B-tree rules and keys are in order
+func checkBTree[K, T any](t *testing.T, bTree *BTree[K, T]) {
+	if bTree.root == nil {
+		return
+	}
+
+	leafDepth := -1
+
+	var walk func(node *bTreeNode[K, T], depth int, isRoot bool)
+	walk = func(node *bTreeNode[K, T], depth int, isRoot bool) {
+		if !isRoot && len(node.keys) < bTree.degree-1 {
+			t.Fatalf("node has %d keys which is below the minimum of %d", len(node.keys), bTree.degree-1)
+		}
+
+		if len(node.keys) > bTree.maxKeys() {
+			t.Fatalf("node has %d keys which is above the max of %d", len(node.keys), bTree.maxKeys())
+		}
+
+		if len(node.keys) != len(node.ts) {
+			t.Fatal("keys and ts are out of sync")
+		}
+
+		for i := 1; i < len(node.keys); i++ {
+			if bTree.kCompFunc(node.keys[i-1], node.keys[i]) >= 0 {
+				t.Fatal("keys are out of order within a node")
+			}
+		}
+
+		if node.isLeaf() {
+			if leafDepth == -1 {
+				leafDepth = depth
+			} else if leafDepth != depth {
+				t.Fatal("leaves are at different depths")
+			}
+			return
+		}
+
+		if len(node.children) != len(node.keys)+1 {
+			t.Fatal("internal node has wrong number of children")
+		}
+
+		for _, child := range node.children {
+			walk(child, depth+1, false)
+		}
+	}
+
+	walk(bTree.root, 0, true)
+}
+
+func TestBTreeImplementsOrderedSet(t *testing.T) {
+	var _ godatacollections.OrderedSet[int, int] = intBTree(2)
+}
+
+func TestNewBTreeReturnsErrorForBadArgs(t *testing.T) {
+	if _, err := NewBTree(1, compInts, func(i int) int { return i }, -1); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewBTree[int, int](2, nil, func(i int) int { return i }, -1); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewBTree[int, int](2, compInts, nil, -1); err == nil {
+		t.Fail()
+	}
+}
+
+func TestBTreeInsertAndLookup(t *testing.T) {
+	for _, degree := range []int{2, 3, 16} {
+		bTree := intBTree(degree)
+
+		for _, curVal := range rand.Perm(1000) {
+			if err := bTree.Insert(curVal); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		checkBTree(t, bTree)
+
+		if bTree.Len() != 1000 {
+			t.Fatalf("expected 1000 items but got %d", bTree.Len())
+		}
+
+		for i := 0; i < 1000; i++ {
+			if !bTree.Contains(i) || bTree.GetByKey(i) != i {
+				t.Fatalf("expected to find %d", i)
+			}
+		}
+
+		if bTree.Contains(1000) || bTree.GetByKey(-5) != -1 {
+			t.Fail()
+		}
+
+		expectIDs(t, intIterValues(bTree.Iterator()), idRange(0, 1000))
+	}
+}
+
+func TestBTreeInsertDuplicateReturnsError(t *testing.T) {
+	bTree := intBTree(2)
+
+	for i := 0; i < 100; i++ {
+		bTree.Insert(i)
+	}
+
+	for i := 0; i < 100; i++ {
+		if bTree.Insert(i) == nil {
+			t.Fatalf("expected duplicate error for %d", i)
+		}
+	}
+
+	if bTree.Len() != 100 {
+		t.Fail()
+	}
+
+	checkBTree(t, bTree)
+}
+
+func TestBTreeRemove(t *testing.T) {
+	for _, degree := range []int{2, 3, 16} {
+		bTree := intBTree(degree)
+
+		for _, curVal := range rand.Perm(1000) {
+			bTree.Insert(curVal)
+		}
+
+		remaining := make([]int, 0)
+		for _, curVal := range rand.Perm(1000) {
+			if curVal%3 == 0 {
+				continue
+			}
+
+			if err := bTree.Remove(curVal); err != nil {
+				t.Fatal(err)
+			}
+
+			checkBTree(t, bTree)
+		}
+
+		for i := 0; i < 1000; i += 3 {
+			remaining = append(remaining, i)
+		}
+
+		expectIDs(t, intIterValues(bTree.Iterator()), remaining)
+
+		if bTree.Remove(1) == nil {
+			t.Fatal("expected error removing missing key")
+		}
+
+		for _, curVal := range remaining {
+			if err := bTree.Remove(curVal); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if bTree.root != nil || bTree.Len() != 0 {
+			t.Fatal("expected empty tree")
+		}
+	}
+}
+
+// Removing a missing key can still merge nodes on the way down, which used to leave an empty root behind
+func TestBTreeRemoveMissingKeysAgainstMap(t *testing.T) {
+	for seed := int64(0); seed < 100; seed++ {
+		random := rand.New(rand.NewSource(seed))
+		bTree := intBTree(2 + int(seed%3))
+		expected := make(map[int]bool)
+
+		for op := 0; op < 400; op++ {
+			key := random.Intn(60)
+
+			if random.Intn(2) == 0 {
+				err := bTree.Insert(key)
+
+				if (err == nil) == expected[key] {
+					t.Fatalf("seed %d: insert of %d gave %v", seed, key, err)
+				}
+				expected[key] = true
+			} else {
+				err := bTree.Remove(key)
+
+				if (err == nil) != expected[key] {
+					t.Fatalf("seed %d: remove of %d gave %v", seed, key, err)
+				}
+				delete(expected, key)
+			}
+
+			if bTree.root != nil && len(bTree.root.keys) == 0 {
+				t.Fatalf("seed %d: root was left with no keys", seed)
+			}
+
+			checkBTree(t, bTree)
+		}
+
+		if bTree.Len() != len(expected) {
+			t.Fatalf("seed %d: expected %d items but got %d", seed, len(expected), bTree.Len())
+		}
+
+		for key := range expected {
+			if !bTree.Contains(key) {
+				t.Fatalf("seed %d: missing %d", seed, key)
+			}
+		}
+	}
+}
+
+func TestBTreeRemoveFromEmptyReturnsError(t *testing.T) {
+	if intBTree(2).Remove(1) == nil {
+		t.Fail()
+	}
+}
+
+func TestBTreeOrderedNavigation(t *testing.T) {
+	bTree := intBTree(2)
+
+	if _, err := bTree.Min(); !godatacollections.IsEmptyError(err) {
+		t.Fatal("expected empty error")
+	}
+
+	if _, err := bTree.Max(); !godatacollections.IsEmptyError(err) {
+		t.Fatal("expected empty error")
+	}
+
+	for _, curVal := range rand.Perm(100) {
+		bTree.Insert(curVal * 2)
+	}
+
+	if minVal, _ := bTree.Min(); minVal != 0 {
+		t.Fatalf("expected min 0 but got %d", minVal)
+	}
+
+	if maxVal, _ := bTree.Max(); maxVal != 198 {
+		t.Fatalf("expected max 198 but got %d", maxVal)
+	}
+
+	for key := 0; key < 200; key++ {
+		expectedFloor := key - key%2
+		if floorVal, err := bTree.Floor(key); err != nil || floorVal != expectedFloor {
+			t.Fatalf("expected floor of %d to be %d but got %d", key, expectedFloor, floorVal)
+		}
+
+		expectedCeiling := key + key%2
+		ceilingVal, err := bTree.Ceiling(key)
+		if expectedCeiling > 198 {
+			if !godatacollections.IsNotFoundError(err) {
+				t.Fatalf("expected no ceiling for %d", key)
+			}
+		} else if err != nil || ceilingVal != expectedCeiling {
+			t.Fatalf("expected ceiling of %d to be %d but got %d", key, expectedCeiling, ceilingVal)
+		}
+	}
+
+	if _, err := bTree.Floor(-1); !godatacollections.IsNotFoundError(err) {
+		t.Fatal("expected no floor below min")
+	}
+}
+
+func TestBTreeRangeIterator(t *testing.T) {
+	bTree := intBTree(2)
+
+	for _, curVal := range rand.Perm(200) {
+		bTree.Insert(curVal * 2)
+	}
+
+	for start := -3; start < 405; start += 7 {
+		for _, width := range []int{0, 1, 2, 13, 100} {
+			expected := make([]int, 0)
+			for curVal := 0; curVal < 400; curVal += 2 {
+				if curVal >= start && curVal < start+width {
+					expected = append(expected, curVal)
+				}
+			}
+
+			expectIDs(t, intIterValues(bTree.RangeIterator(start, start+width)), expected)
+		}
+	}
+
+	expectIDs(t, intIterValues(intBTree(2).RangeIterator(0, 10)), []int{})
+}
+
+// -------------------------------------- Benchmarks ------------------------------------------
+// There is no self balancing tree such as an AVL tree in the repo to compare against
+// so a BST rebalanced with Rebalance after it is built stands in as the balanced baseline for lookups and iteration
+// Insert has no balanced baseline as Rebalance only balances a tree that is already built
+
+const benchSize = 100_000
+
+func benchKeys() []int {
+	return rand.New(rand.NewSource(1)).Perm(benchSize)
+}
+
+func benchBST(keys []int) *tree.BST[int, int] {
+	bst, _ := tree.NewBST(compInts, func(i int) int { return i }, -1)
+
+	for _, curKey := range keys {
+		bst.Insert(curKey)
+	}
+
+	return bst
+}
+
+func benchBalancedBST(keys []int) *tree.BST[int, int] {
+	bst := benchBST(keys)
+	bst.Rebalance()
+
+	return bst
+}
+
+func benchBTree(keys []int) *BTree[int, int] {
+	bTree := intBTree(32)
+
+	for _, curKey := range keys {
+		bTree.Insert(curKey)
+	}
+
+	return bTree
+}
+
+func BenchmarkBSTInsert(b *testing.B) {
+	keys := benchKeys()
+
+	for i := 0; i < b.N; i++ {
+		benchBST(keys)
+	}
+}
+
+func BenchmarkBTreeInsert(b *testing.B) {
+	keys := benchKeys()
+
+	for i := 0; i < b.N; i++ {
+		benchBTree(keys)
+	}
+}
+
+func BenchmarkBSTLookup(b *testing.B) {
+	keys := benchKeys()
+	bst := benchBST(keys)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		bst.Contains(keys[i%benchSize])
+	}
+}
+
+func BenchmarkBalancedBSTLookup(b *testing.B) {
+	keys := benchKeys()
+	bst := benchBalancedBST(keys)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		bst.Contains(keys[i%benchSize])
+	}
+}
+
+func BenchmarkBTreeLookup(b *testing.B) {
+	keys := benchKeys()
+	bTree := benchBTree(keys)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		bTree.Contains(keys[i%benchSize])
+	}
+}
+
+func BenchmarkBSTIterate(b *testing.B) {
+	bst := benchBST(benchKeys())
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for iter := bst.Iterator(); iter.HasNext(); {
+			iter.Next()
+		}
+	}
+}
+
+func BenchmarkBalancedBSTIterate(b *testing.B) {
+	bst := benchBalancedBST(benchKeys())
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for iter := bst.Iterator(); iter.HasNext(); {
+			iter.Next()
+		}
+	}
+}
+
+func BenchmarkBTreeIterate(b *testing.B) {
+	bTree := benchBTree(benchKeys())
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for iter := bTree.Iterator(); iter.HasNext(); {
+			iter.Next()
+		}
+	}
+}
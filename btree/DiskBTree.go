@@ -0,0 +1,687 @@
+package btree
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+const (
+	defaultDiskPageSize   = 4096
+	defaultDiskCachePages = 1024
+	minDiskPageSize       = 256
+	maxDiskPageSize       = 1 << 16
+)
+
+// DiskBTreeConfig allows for tuning a DiskBTree
+// Zero values use the defaults
+type DiskBTreeConfig struct {
+	// PageSize is the size in bytes of every page in the file. Defaults to 4096
+	// Only used when creating a new file, existing files keep the page size they were made with
+	PageSize int
+	// CachePages is how many clean pages are kept in memory. Defaults to 1024
+	// Pages changed since the last Sync are always kept in memory on top of this
+	CachePages int
+}
+
+// DiskBTree is a B+ tree that lives in a file so that it can hold more than fits in memory
+// Items are stored in the leaves in key order and internal nodes only hold separator keys
+// Just like tree.BST it stores items T that each have a unique key K
+//
+// Changes are kept in memory until Sync (or Close) is called. Sync goes through a write ahead log
+// so that a crash leaves the file as it was at the last Sync
+// Removing items does not merge nodes that become under full, nodes are only freed once they are empty
+//
+// DiskBTree is not safe for use by multiple go routines at once
+type DiskBTree[K, T any] struct {
+	kCompFunc  func(K, K) int
+	tToKFunc   func(T) K
+	kCodec     godatacollections.Codec[K]
+	tCodec     godatacollections.Codec[T]
+	zeroValue  T
+	file       *os.File
+	wal        *os.File
+	pageSize   int
+	cachePages int
+	// maxEntrySize is the largest an encoded item or key is allowed to be
+	// Keeping it to a quarter of a page ensures that splitting a full page always gives two pages that fit
+	maxEntrySize int
+	meta         diskMeta
+	metaDirty    bool
+	cache        map[uint64]*diskNode[K, T]
+	lru          *list.List
+	err          error
+}
+
+// OpenDiskBTree opens the DiskBTree stored at path, creating it if it doesn't exist
+// The write ahead log is stored next to it at path + ".wal"
+// kCompFunc, tToKFunc and tZeroValue are the same as for tree.NewBST
+// kCodec and tCodec turn keys and items into bytes for storing in pages
+func OpenDiskBTree[K, T any](path string, kCompFunc func(K, K) int, tToKFunc func(T) K, kCodec godatacollections.Codec[K], tCodec godatacollections.Codec[T], tZeroValue T, config DiskBTreeConfig) (*DiskBTree[K, T], error) {
+	if kCompFunc == nil {
+		return nil, errors.New("unable to create DiskBTree without a function to compare Keys")
+	}
+
+	if tToKFunc == nil {
+		return nil, errors.New("unable to create DiskBTree without a function to convert T to a Key")
+	}
+
+	if kCodec == nil || tCodec == nil {
+		return nil, errors.New("unable to create DiskBTree without codecs for K and T")
+	}
+
+	if config.PageSize == 0 {
+		config.PageSize = defaultDiskPageSize
+	}
+
+	if config.CachePages == 0 {
+		config.CachePages = defaultDiskCachePages
+	}
+
+	if config.PageSize < minDiskPageSize || config.PageSize > maxDiskPageSize {
+		return nil, fmt.Errorf("unable to create DiskBTree with page size %d, must be between %d and %d", config.PageSize, minDiskPageSize, maxDiskPageSize)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+
+	if err != nil {
+		return nil, err
+	}
+
+	wal, err := os.OpenFile(path+".wal", os.O_RDWR|os.O_CREATE, 0644)
+
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	bTree := &DiskBTree[K, T]{
+		kCompFunc:  kCompFunc,
+		tToKFunc:   tToKFunc,
+		kCodec:     kCodec,
+		tCodec:     tCodec,
+		zeroValue:  tZeroValue,
+		file:       file,
+		wal:        wal,
+		cachePages: config.CachePages,
+		cache:      make(map[uint64]*diskNode[K, T]),
+		lru:        list.New(),
+	}
+
+	if err = bTree.load(config.PageSize); err != nil {
+		file.Close()
+		wal.Close()
+		return nil, err
+	}
+
+	return bTree, nil
+}
+
+// load repairs the file from the WAL if needed and then reads the meta page, or sets up a new file if it is empty
+func (this *DiskBTree[K, T]) load(newPageSize int) error {
+	if err := this.recoverWAL(); err != nil {
+		return err
+	}
+
+	info, err := this.file.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	if info.Size() == 0 {
+		this.setPageSize(newPageSize)
+		this.meta = diskMeta{pageSize: uint32(newPageSize), pageCount: 1}
+
+		root, err := this.allocNode(diskPageTypeLeaf)
+
+		if err != nil {
+			return err
+		}
+
+		this.meta.root = root.page
+
+		return this.Sync()
+	}
+
+	metaPage := make([]byte, minDiskPageSize)
+
+	if _, err = this.file.ReadAt(metaPage, 0); err != nil {
+		return err
+	}
+
+	if this.meta, err = decodeDiskMeta(metaPage); err != nil {
+		return err
+	}
+
+	this.setPageSize(int(this.meta.pageSize))
+
+	return nil
+}
+
+func (this *DiskBTree[K, T]) setPageSize(pageSize int) {
+	this.pageSize = pageSize
+	this.maxEntrySize = (pageSize - diskNodeHeaderSize - diskChildSize) / 4
+}
+
+// Close commits any changes and closes the file
+func (this *DiskBTree[K, T]) Close() error {
+	syncErr := this.Sync()
+	fileErr := this.file.Close()
+	walErr := this.wal.Close()
+
+	return errors.Join(syncErr, fileErr, walErr)
+}
+
+// Len returns the number of items in the tree
+func (this *DiskBTree[K, T]) Len() int {
+	return int(this.meta.itemCount)
+}
+
+// Err returns the last error hit by Contains or GetByKey
+// Those methods have no way of returning errors due to the Set interface so they are stashed here instead
+func (this *DiskBTree[K, T]) Err() error {
+	return this.err
+}
+
+// diskPathStep is an internal node on the way down to a leaf and which child was taken from it
+type diskPathStep[K, T any] struct {
+	node     *diskNode[K, T]
+	childIdx int
+}
+
+// findLeaf walks from the root down to the leaf that key belongs in
+func (this *DiskBTree[K, T]) findLeaf(key K) ([]diskPathStep[K, T], *diskNode[K, T], error) {
+	path := make([]diskPathStep[K, T], 0)
+
+	curNode, err := this.node(this.meta.root)
+
+	for err == nil && curNode.pageType == diskPageTypeInternal {
+		childIdx := this.upperBound(curNode.keys, key)
+		path = append(path, diskPathStep[K, T]{node: curNode, childIdx: childIdx})
+		curNode, err = this.node(curNode.children[childIdx])
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return path, curNode, nil
+}
+
+// lowerBound returns the index of the first key that is >= key
+func (this *DiskBTree[K, T]) lowerBound(keys []K, key K) int {
+	low, high := 0, len(keys)
+
+	for low < high {
+		mid := int(uint(low+high) >> 1)
+		if this.kCompFunc(keys[mid], key) < 0 {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+
+	return low
+}
+
+// upperBound returns the index of the first key that is > key
+// For an internal node that is the index of the child that key belongs in
+func (this *DiskBTree[K, T]) upperBound(keys []K, key K) int {
+	low, high := 0, len(keys)
+
+	for low < high {
+		mid := int(uint(low+high) >> 1)
+		if this.kCompFunc(keys[mid], key) <= 0 {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+
+	return low
+}
+
+func (this *DiskBTree[K, T]) Insert(newT T) error {
+	defer this.evict()
+
+	newEntry, err := this.tCodec.Encode(newT)
+
+	if err != nil {
+		return err
+	}
+
+	newKey := this.tToKFunc(newT)
+	keyEntry, err := this.kCodec.Encode(newKey)
+
+	if err != nil {
+		return err
+	}
+
+	if len(newEntry) > this.maxEntrySize || len(keyEntry) > this.maxEntrySize {
+		return fmt.Errorf("unable to insert T for key %v as it is larger than the max of %d bytes", newKey, this.maxEntrySize)
+	}
+
+	path, leaf, err := this.findLeaf(newKey)
+
+	if err != nil {
+		return err
+	}
+
+	idx := this.lowerBound(leaf.keys, newKey)
+
+	if idx < len(leaf.keys) && this.kCompFunc(leaf.keys[idx], newKey) == 0 {
+		return fmt.Errorf("unable to insert duplicate T for key %v", newKey)
+	}
+
+	leaf.keys = insertAt(leaf.keys, idx, newKey)
+	leaf.ts = insertAt(leaf.ts, idx, newT)
+	leaf.entries = insertAt(leaf.entries, idx, newEntry)
+	this.markDirty(leaf)
+
+	this.meta.itemCount++
+	this.metaDirty = true
+
+	if leaf.size() <= this.pageSize {
+		return nil
+	}
+
+	return this.split(path, leaf)
+}
+
+// split splits an over full node and pushes the separator up into its parent, splitting parents as needed
+func (this *DiskBTree[K, T]) split(path []diskPathStep[K, T], overFull *diskNode[K, T]) error {
+	for {
+		right, err := this.allocNode(overFull.pageType)
+
+		if err != nil {
+			return err
+		}
+
+		var sepKey K
+		var sepEntry []byte
+
+		if overFull.pageType == diskPageTypeLeaf {
+			mid := splitIndex(overFull.entries)
+
+			right.keys = append(right.keys, overFull.keys[mid:]...)
+			right.ts = append(right.ts, overFull.ts[mid:]...)
+			right.entries = append(right.entries, overFull.entries[mid:]...)
+			overFull.keys = overFull.keys[:mid:mid]
+			overFull.ts = overFull.ts[:mid:mid]
+			overFull.entries = overFull.entries[:mid:mid]
+
+			// Separator is a copy of the first key on the right. Everything >= it goes right
+			sepKey = right.keys[0]
+			if sepEntry, err = this.kCodec.Encode(sepKey); err != nil {
+				return err
+			}
+		} else {
+			// The middle key moves up into the parent instead of being copied
+			mid := splitIndex(overFull.entries)
+
+			sepKey = overFull.keys[mid]
+			sepEntry = overFull.entries[mid]
+
+			right.keys = append(right.keys, overFull.keys[mid+1:]...)
+			right.entries = append(right.entries, overFull.entries[mid+1:]...)
+			right.children = append(right.children, overFull.children[mid+1:]...)
+			overFull.keys = overFull.keys[:mid:mid]
+			overFull.entries = overFull.entries[:mid:mid]
+			overFull.children = overFull.children[: mid+1 : mid+1]
+		}
+
+		this.markDirty(overFull)
+
+		if len(path) == 0 {
+			// Root was split so the tree grows a level
+			newRoot, err := this.allocNode(diskPageTypeInternal)
+
+			if err != nil {
+				return err
+			}
+
+			newRoot.keys = append(newRoot.keys, sepKey)
+			newRoot.entries = append(newRoot.entries, sepEntry)
+			newRoot.children = append(newRoot.children, overFull.page, right.page)
+
+			this.meta.root = newRoot.page
+			this.metaDirty = true
+
+			return nil
+		}
+
+		parentStep := path[len(path)-1]
+		path = path[:len(path)-1]
+		parent := parentStep.node
+
+		parent.keys = insertAt(parent.keys, parentStep.childIdx, sepKey)
+		parent.entries = insertAt(parent.entries, parentStep.childIdx, sepEntry)
+		parent.children = insertAt(parent.children, parentStep.childIdx+1, right.page)
+		this.markDirty(parent)
+
+		if parent.size() <= this.pageSize {
+			return nil
+		}
+
+		overFull = parent
+	}
+}
+
+// splitIndex returns the index that splits entries into two halves of about the same number of bytes
+// Always leaves at least one entry on each side
+func splitIndex(entries [][]byte) int {
+	total := 0
+	for _, curEntry := range entries {
+		total += len(curEntry)
+	}
+
+	soFar := 0
+	for i, curEntry := range entries {
+		soFar += len(curEntry)
+		if soFar >= total/2 {
+			return min(max(i, 1), len(entries)-1)
+		}
+	}
+
+	return len(entries) / 2
+}
+
+func insertAt[E any](s []E, idx int, e E) []E {
+	var zero E
+	s = append(s, zero)
+	copy(s[idx+1:], s[idx:])
+	s[idx] = e
+
+	return s
+}
+
+func removeAt[E any](s []E, idx int) []E {
+	copy(s[idx:], s[idx+1:])
+	var zero E
+	s[len(s)-1] = zero
+
+	return s[:len(s)-1]
+}
+
+func (this *DiskBTree[K, T]) Contains(key K) bool {
+	_, found := this.get(key)
+
+	return found
+}
+
+func (this *DiskBTree[K, T]) GetByKey(key K) T {
+	t, _ := this.get(key)
+
+	return t
+}
+
+func (this *DiskBTree[K, T]) get(key K) (T, bool) {
+	defer this.evict()
+
+	_, leaf, err := this.findLeaf(key)
+
+	if err != nil {
+		this.err = err
+		return this.zeroValue, false
+	}
+
+	idx := this.lowerBound(leaf.keys, key)
+
+	if idx < len(leaf.keys) && this.kCompFunc(leaf.keys[idx], key) == 0 {
+		return leaf.ts[idx], true
+	}
+
+	return this.zeroValue, false
+}
+
+func (this *DiskBTree[K, T]) Remove(key K) error {
+	defer this.evict()
+
+	path, leaf, err := this.findLeaf(key)
+
+	if err != nil {
+		return err
+	}
+
+	idx := this.lowerBound(leaf.keys, key)
+
+	if idx >= len(leaf.keys) || this.kCompFunc(leaf.keys[idx], key) != 0 {
+		return fmt.Errorf("unable to delete item with key %v due to it not existing in tree", key)
+	}
+
+	leaf.keys = removeAt(leaf.keys, idx)
+	leaf.ts = removeAt(leaf.ts, idx)
+	leaf.entries = removeAt(leaf.entries, idx)
+	this.markDirty(leaf)
+
+	this.meta.itemCount--
+	this.metaDirty = true
+
+	// Free nodes that are now empty all the way up. Root is never freed here
+	emptyNode := leaf
+	for len(path) > 0 && emptyNode.isEmpty() {
+		parentStep := path[len(path)-1]
+		path = path[:len(path)-1]
+		parent := parentStep.node
+
+		if len(parent.keys) > 0 {
+			if parentStep.childIdx == 0 {
+				parent.keys = removeAt(parent.keys, 0)
+				parent.entries = removeAt(parent.entries, 0)
+			} else {
+				parent.keys = removeAt(parent.keys, parentStep.childIdx-1)
+				parent.entries = removeAt(parent.entries, parentStep.childIdx-1)
+			}
+		}
+		parent.children = removeAt(parent.children, parentStep.childIdx)
+		this.markDirty(parent)
+
+		this.freeNode(emptyNode)
+		emptyNode = parent
+	}
+
+	return this.collapseRoot()
+}
+
+// collapseRoot shrinks the tree while the root is an internal node with only one (or no) child
+func (this *DiskBTree[K, T]) collapseRoot() error {
+	for {
+		root, err := this.node(this.meta.root)
+
+		if err != nil {
+			return err
+		}
+
+		if root.pageType != diskPageTypeInternal || len(root.children) > 1 {
+			return nil
+		}
+
+		if len(root.children) == 0 {
+			// Everything is gone so go back to a single empty leaf
+			root.pageType = diskPageTypeLeaf
+			root.keys = make([]K, 0)
+			root.ts = make([]T, 0)
+			root.entries = make([][]byte, 0)
+			root.children = nil
+			this.markDirty(root)
+			return nil
+		}
+
+		this.meta.root = root.children[0]
+		this.metaDirty = true
+		this.freeNode(root)
+	}
+}
+
+// Iterator returns an iterator over every item in key order
+// The tree must not be changed while iterating
+func (this *DiskBTree[K, T]) Iterator() godatacollections.Iterator[T] {
+	iter := &diskBTreeIterator[K, T]{bTree: this}
+	iter.seek(nil)
+
+	return iter
+}
+
+// RangeIterator returns an iterator over every item with a key >= start and < end in key order
+// The tree must not be changed while iterating
+func (this *DiskBTree[K, T]) RangeIterator(start, end K) godatacollections.Iterator[T] {
+	iter := &diskBTreeIterator[K, T]{bTree: this, end: &end}
+	iter.seek(&start)
+
+	return iter
+}
+
+// diskIterFrame is an internal node that the iterator is part way through
+// Only page numbers are held so that the nodes are free to be evicted from the cache
+type diskIterFrame struct {
+	page     uint64
+	childIdx int
+}
+
+type diskBTreeIterator[K, T any] struct {
+	bTree    *DiskBTree[K, T]
+	path     []diskIterFrame
+	leafPage uint64
+	leafIdx  int
+	// end is nil when there is no upper bound
+	end     *K
+	next    T
+	hasNext bool
+	err     error
+}
+
+// seek moves to the first item with a key >= start or to the first item when start is nil
+func (this *diskBTreeIterator[K, T]) seek(start *K) {
+	defer this.bTree.evict()
+
+	curNode, err := this.bTree.node(this.bTree.meta.root)
+
+	for err == nil && curNode.pageType == diskPageTypeInternal {
+		childIdx := 0
+		if start != nil {
+			childIdx = this.bTree.upperBound(curNode.keys, *start)
+		}
+
+		this.path = append(this.path, diskIterFrame{page: curNode.page, childIdx: childIdx})
+		curNode, err = this.bTree.node(curNode.children[childIdx])
+	}
+
+	if err != nil {
+		this.fail(err)
+		return
+	}
+
+	this.leafPage = curNode.page
+	if start != nil {
+		this.leafIdx = this.bTree.lowerBound(curNode.keys, *start)
+	}
+
+	this.settle()
+}
+
+// settle preps next to be the item at the current position, moving on to the next leaf if this one is used up
+func (this *diskBTreeIterator[K, T]) settle() {
+	for {
+		leaf, err := this.bTree.node(this.leafPage)
+
+		if err != nil {
+			this.fail(err)
+			return
+		}
+
+		if this.leafIdx < len(leaf.keys) {
+			if this.end != nil && this.bTree.kCompFunc(leaf.keys[this.leafIdx], *this.end) >= 0 {
+				this.hasNext = false
+				return
+			}
+
+			this.next = leaf.ts[this.leafIdx]
+			this.hasNext = true
+			return
+		}
+
+		// Go back up until there is a node with a child to the right that hasn't been visited
+		for len(this.path) > 0 {
+			curFrame := &this.path[len(this.path)-1]
+			curNode, err := this.bTree.node(curFrame.page)
+
+			if err != nil {
+				this.fail(err)
+				return
+			}
+
+			if curFrame.childIdx+1 < len(curNode.children) {
+				curFrame.childIdx++
+				break
+			}
+
+			this.path = this.path[:len(this.path)-1]
+		}
+
+		if len(this.path) == 0 {
+			this.hasNext = false
+			return
+		}
+
+		// Then go down the left most side of that child
+		curFrame := this.path[len(this.path)-1]
+		parent, err := this.bTree.node(curFrame.page)
+
+		if err != nil {
+			this.fail(err)
+			return
+		}
+
+		curNode, err := this.bTree.node(parent.children[curFrame.childIdx])
+
+		for err == nil && curNode.pageType == diskPageTypeInternal {
+			this.path = append(this.path, diskIterFrame{page: curNode.page, childIdx: 0})
+			curNode, err = this.bTree.node(curNode.children[0])
+		}
+
+		if err != nil {
+			this.fail(err)
+			return
+		}
+
+		this.leafPage = curNode.page
+		this.leafIdx = 0
+	}
+}
+
+// fail makes the next call to Next return err
+func (this *diskBTreeIterator[K, T]) fail(err error) {
+	this.err = err
+	this.hasNext = true
+}
+
+func (this *diskBTreeIterator[K, T]) Close() error {
+	return nil
+}
+
+func (this *diskBTreeIterator[K, T]) HasNext() bool {
+	return this.hasNext
+}
+
+func (this *diskBTreeIterator[K, T]) Next() (T, error) {
+	if !this.hasNext {
+		return this.bTree.zeroValue, errors.New("nothing left to iterate over")
+	}
+
+	if this.err != nil {
+		this.hasNext = false
+		return this.bTree.zeroValue, this.err
+	}
+
+	retNext := this.next
+
+	this.leafIdx++
+	this.settle()
+	this.bTree.evict()
+
+	return retNext, nil
+}
@@ -0,0 +1,536 @@
+package btree
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+)
+
+// Everything in here is about getting pages on and off of disk
+// The tree logic itself lives in DiskBTree.go
+
+const (
+	diskPageTypeLeaf     byte = 1
+	diskPageTypeInternal byte = 2
+	diskPageTypeFree     byte = 3
+
+	// Type byte and entry count
+	diskNodeHeaderSize = 3
+	// Every internal node has one more child than it has keys
+	diskChildSize = 8
+
+	diskMetaPage = 0
+)
+
+var (
+	diskMetaMagic      = []byte("GDCBTREE")
+	diskWALMagic       = []byte("GDCWAL01")
+	diskWALCommitMagic = []byte("COMMIT01")
+)
+
+// Size of the commit record at the end of the WAL: magic, record count and crc32
+const diskWALTrailerSize = 8 + 8 + 4
+
+// diskMeta is stored in page 0 of the file
+type diskMeta struct {
+	pageSize     uint32
+	root         uint64
+	freeListHead uint64
+	pageCount    uint64
+	itemCount    uint64
+}
+
+func (this *diskMeta) encode(pageSize int) []byte {
+	page := make([]byte, pageSize)
+
+	copy(page, diskMetaMagic)
+	offset := len(diskMetaMagic)
+	binary.LittleEndian.PutUint32(page[offset:], this.pageSize)
+	offset += 4
+
+	for _, curValue := range []uint64{this.root, this.freeListHead, this.pageCount, this.itemCount} {
+		binary.LittleEndian.PutUint64(page[offset:], curValue)
+		offset += 8
+	}
+
+	return page
+}
+
+func decodeDiskMeta(page []byte) (diskMeta, error) {
+	meta := diskMeta{}
+
+	if len(page) < len(diskMetaMagic)+4+4*8 || !bytes.Equal(page[:len(diskMetaMagic)], diskMetaMagic) {
+		return meta, errors.New("unable to open DiskBTree as file is not a DiskBTree")
+	}
+
+	offset := len(diskMetaMagic)
+	meta.pageSize = binary.LittleEndian.Uint32(page[offset:])
+	offset += 4
+
+	if meta.pageSize < minDiskPageSize || meta.pageSize > maxDiskPageSize {
+		return meta, fmt.Errorf("unable to open DiskBTree with page size %d, must be between %d and %d", meta.pageSize, minDiskPageSize, maxDiskPageSize)
+	}
+
+	for _, curValue := range []*uint64{&meta.root, &meta.freeListHead, &meta.pageCount, &meta.itemCount} {
+		*curValue = binary.LittleEndian.Uint64(page[offset:])
+		offset += 8
+	}
+
+	return meta, nil
+}
+
+// diskNode is the in memory form of a page
+type diskNode[K, T any] struct {
+	page     uint64
+	pageType byte
+	// keys are the keys of the items for a leaf and the separator keys for an internal node
+	keys []K
+	// ts are only used by leaves
+	ts []T
+	// entries are the encoded bytes of each T for a leaf and each separator key for an internal node
+	// Kept around so that we know how big the page is without encoding again
+	entries [][]byte
+	// children are only used by internal nodes
+	children []uint64
+	// nextFree is only used by free pages
+	nextFree uint64
+	dirty    bool
+	lruElem  *list.Element
+}
+
+// isEmpty returns if the node holds nothing and can be freed
+func (this *diskNode[K, T]) isEmpty() bool {
+	if this.pageType == diskPageTypeInternal {
+		return len(this.children) == 0
+	}
+
+	return len(this.keys) == 0
+}
+
+// size returns how many bytes the node takes when encoded
+func (this *diskNode[K, T]) size() int {
+	size := diskNodeHeaderSize
+
+	if this.pageType == diskPageTypeInternal {
+		size += diskChildSize * len(this.children)
+	}
+
+	for _, curEntry := range this.entries {
+		size += uvarintLen(uint64(len(curEntry))) + len(curEntry)
+	}
+
+	return size
+}
+
+func (this *diskNode[K, T]) encode(pageSize int) []byte {
+	page := make([]byte, 0, pageSize)
+
+	page = append(page, this.pageType)
+
+	if this.pageType == diskPageTypeFree {
+		page = binary.LittleEndian.AppendUint64(page, this.nextFree)
+		return page[:pageSize]
+	}
+
+	page = binary.LittleEndian.AppendUint16(page, uint16(len(this.entries)))
+
+	if this.pageType == diskPageTypeInternal {
+		page = binary.LittleEndian.AppendUint64(page, this.children[0])
+	}
+
+	for i, curEntry := range this.entries {
+		page = binary.AppendUvarint(page, uint64(len(curEntry)))
+		page = append(page, curEntry...)
+
+		if this.pageType == diskPageTypeInternal {
+			page = binary.LittleEndian.AppendUint64(page, this.children[i+1])
+		}
+	}
+
+	return page[:pageSize]
+}
+
+func uvarintLen(x uint64) int {
+	length := 1
+
+	for x >= 0x80 {
+		x >>= 7
+		length++
+	}
+
+	return length
+}
+
+// node returns the node for page, loading it from disk if it isn't in the cache
+func (this *DiskBTree[K, T]) node(page uint64) (*diskNode[K, T], error) {
+	if cached, ok := this.cache[page]; ok {
+		this.lru.MoveToFront(cached.lruElem)
+		return cached, nil
+	}
+
+	if page == diskMetaPage || page >= this.meta.pageCount {
+		return nil, fmt.Errorf("unable to load page %d as it is out of range", page)
+	}
+
+	data := make([]byte, this.pageSize)
+
+	if _, err := this.file.ReadAt(data, int64(page)*int64(this.pageSize)); err != nil {
+		return nil, err
+	}
+
+	loaded, err := this.decodeNode(page, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	this.addToCache(loaded)
+
+	return loaded, nil
+}
+
+func (this *DiskBTree[K, T]) decodeNode(page uint64, data []byte) (*diskNode[K, T], error) {
+	corruptErr := fmt.Errorf("unable to load page %d as it is corrupt", page)
+
+	if len(data) < diskNodeHeaderSize {
+		return nil, corruptErr
+	}
+
+	loaded := &diskNode[K, T]{page: page, pageType: data[0]}
+
+	if loaded.pageType == diskPageTypeFree {
+		if len(data) < 1+diskChildSize {
+			return nil, corruptErr
+		}
+
+		loaded.nextFree = binary.LittleEndian.Uint64(data[1:])
+		return loaded, nil
+	}
+
+	if loaded.pageType != diskPageTypeLeaf && loaded.pageType != diskPageTypeInternal {
+		return nil, fmt.Errorf("unable to load page %d as it has unknown type %d", page, loaded.pageType)
+	}
+
+	count := int(binary.LittleEndian.Uint16(data[1:]))
+	offset := diskNodeHeaderSize
+
+	// Every entry takes at least a byte for its length, and a child pointer as well in an internal node
+	minEntrySize := 1
+	if loaded.pageType == diskPageTypeInternal {
+		minEntrySize += diskChildSize
+	}
+
+	if count > (len(data)-diskNodeHeaderSize)/minEntrySize {
+		return nil, corruptErr
+	}
+
+	loaded.keys = make([]K, 0, count)
+	loaded.entries = make([][]byte, 0, count)
+
+	if loaded.pageType == diskPageTypeInternal {
+		if offset+diskChildSize > len(data) {
+			return nil, corruptErr
+		}
+
+		loaded.children = make([]uint64, 0, count+1)
+		loaded.children = append(loaded.children, binary.LittleEndian.Uint64(data[offset:]))
+		offset += diskChildSize
+	} else {
+		loaded.ts = make([]T, 0, count)
+	}
+
+	for i := 0; i < count; i++ {
+		entryLen, lenBytes := binary.Uvarint(data[offset:])
+
+		if lenBytes <= 0 || entryLen > uint64(len(data)-offset-lenBytes) {
+			return nil, corruptErr
+		}
+
+		offset += lenBytes
+		entry := data[offset : offset+int(entryLen)]
+		offset += int(entryLen)
+
+		loaded.entries = append(loaded.entries, entry)
+
+		if loaded.pageType == diskPageTypeInternal {
+			key, err := this.kCodec.Decode(entry)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if offset+diskChildSize > len(data) {
+				return nil, corruptErr
+			}
+
+			loaded.keys = append(loaded.keys, key)
+			loaded.children = append(loaded.children, binary.LittleEndian.Uint64(data[offset:]))
+			offset += diskChildSize
+		} else {
+			t, err := this.tCodec.Decode(entry)
+
+			if err != nil {
+				return nil, err
+			}
+
+			loaded.ts = append(loaded.ts, t)
+			loaded.keys = append(loaded.keys, this.tToKFunc(t))
+		}
+	}
+
+	return loaded, nil
+}
+
+func (this *DiskBTree[K, T]) addToCache(node *diskNode[K, T]) {
+	node.lruElem = this.lru.PushFront(node)
+	this.cache[node.page] = node
+}
+
+// evict drops the least recently used clean nodes until the cache is back down to size
+// Dirty nodes are never evicted as they can't be written to the file until Sync
+// Only called at the end of operations so that nodes an operation is holding onto stay in the cache
+func (this *DiskBTree[K, T]) evict() {
+	for curElem := this.lru.Back(); curElem != nil && len(this.cache) > this.cachePages; {
+		curNode := curElem.Value.(*diskNode[K, T])
+		prevElem := curElem.Prev()
+
+		if !curNode.dirty {
+			this.lru.Remove(curElem)
+			delete(this.cache, curNode.page)
+		}
+
+		curElem = prevElem
+	}
+}
+
+func (this *DiskBTree[K, T]) markDirty(node *diskNode[K, T]) {
+	node.dirty = true
+}
+
+// allocNode returns a new empty node, reusing a page from the free list if there is one
+func (this *DiskBTree[K, T]) allocNode(pageType byte) (*diskNode[K, T], error) {
+	var allocated *diskNode[K, T]
+
+	if this.meta.freeListHead != 0 {
+		freeNode, err := this.node(this.meta.freeListHead)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if freeNode.pageType != diskPageTypeFree {
+			return nil, fmt.Errorf("unable to allocate page %d from free list as it is not free", freeNode.page)
+		}
+
+		this.meta.freeListHead = freeNode.nextFree
+		allocated = freeNode
+	} else {
+		allocated = &diskNode[K, T]{page: this.meta.pageCount}
+		this.meta.pageCount++
+		this.addToCache(allocated)
+	}
+
+	allocated.pageType = pageType
+	allocated.nextFree = 0
+	allocated.keys = make([]K, 0)
+	allocated.entries = make([][]byte, 0)
+	allocated.ts = nil
+	allocated.children = nil
+
+	if pageType == diskPageTypeLeaf {
+		allocated.ts = make([]T, 0)
+	} else {
+		allocated.children = make([]uint64, 0)
+	}
+
+	this.markDirty(allocated)
+	this.metaDirty = true
+
+	return allocated, nil
+}
+
+// freeNode puts the node's page on the front of the free list
+func (this *DiskBTree[K, T]) freeNode(node *diskNode[K, T]) {
+	node.pageType = diskPageTypeFree
+	node.keys = nil
+	node.ts = nil
+	node.entries = nil
+	node.children = nil
+	node.nextFree = this.meta.freeListHead
+
+	this.meta.freeListHead = node.page
+	this.markDirty(node)
+	this.metaDirty = true
+}
+
+// diskWALRecord is a page image that is going to be written to the file
+type diskWALRecord struct {
+	page uint64
+	data []byte
+}
+
+// Sync commits every change since the last Sync to the file
+// Changes are first written to the write ahead log so that a crash part way through writing the file
+// is repaired on the next open. Either all of the changes since the last Sync make it or none of them do
+func (this *DiskBTree[K, T]) Sync() error {
+	records := this.dirtyRecords()
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := this.writeWAL(records); err != nil {
+		return err
+	}
+
+	if err := this.applyRecords(records, this.pageSize); err != nil {
+		return err
+	}
+
+	if err := this.clearWAL(); err != nil {
+		return err
+	}
+
+	for _, curNode := range this.cache {
+		curNode.dirty = false
+	}
+	this.metaDirty = false
+
+	this.evict()
+
+	return nil
+}
+
+// dirtyRecords encodes every dirty page in page order
+func (this *DiskBTree[K, T]) dirtyRecords() []diskWALRecord {
+	records := make([]diskWALRecord, 0)
+
+	if this.metaDirty {
+		records = append(records, diskWALRecord{page: diskMetaPage, data: this.meta.encode(this.pageSize)})
+	}
+
+	for _, curNode := range this.cache {
+		if curNode.dirty {
+			records = append(records, diskWALRecord{page: curNode.page, data: curNode.encode(this.pageSize)})
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].page < records[j].page })
+
+	return records
+}
+
+// writeWAL writes the records followed by a commit record and waits for them to be on disk
+// The WAL is laid out as a header (magic and page size), each record (page number and page),
+// and finally the commit record (magic, record count and crc32 of everything before it)
+func (this *DiskBTree[K, T]) writeWAL(records []diskWALRecord) error {
+	walData := make([]byte, 0, len(diskWALMagic)+4+len(records)*(8+this.pageSize)+diskWALTrailerSize)
+
+	walData = append(walData, diskWALMagic...)
+	walData = binary.LittleEndian.AppendUint32(walData, uint32(this.pageSize))
+
+	for _, curRecord := range records {
+		walData = binary.LittleEndian.AppendUint64(walData, curRecord.page)
+		walData = append(walData, curRecord.data...)
+	}
+
+	checksum := crc32.ChecksumIEEE(walData)
+
+	walData = append(walData, diskWALCommitMagic...)
+	walData = binary.LittleEndian.AppendUint64(walData, uint64(len(records)))
+	walData = binary.LittleEndian.AppendUint32(walData, checksum)
+
+	if err := this.wal.Truncate(0); err != nil {
+		return err
+	}
+
+	if _, err := this.wal.WriteAt(walData, 0); err != nil {
+		return err
+	}
+
+	return this.wal.Sync()
+}
+
+// applyRecords writes the page images to the file and waits for them to be on disk
+func (this *DiskBTree[K, T]) applyRecords(records []diskWALRecord, pageSize int) error {
+	for _, curRecord := range records {
+		if _, err := this.file.WriteAt(curRecord.data, int64(curRecord.page)*int64(pageSize)); err != nil {
+			return err
+		}
+	}
+
+	return this.file.Sync()
+}
+
+func (this *DiskBTree[K, T]) clearWAL() error {
+	if err := this.wal.Truncate(0); err != nil {
+		return err
+	}
+
+	return this.wal.Sync()
+}
+
+// recoverWAL replays a WAL that was left behind by a crash
+// Only a WAL with a complete and valid commit record is replayed, anything else never made it to the file so is thrown away
+func (this *DiskBTree[K, T]) recoverWAL() error {
+	walData, err := io.ReadAll(io.NewSectionReader(this.wal, 0, 1<<62))
+
+	if err != nil {
+		return err
+	}
+
+	if len(walData) == 0 {
+		return nil
+	}
+
+	// Recovery happens before the meta page is read so the page size has to come from the WAL
+	records, pageSize, ok := parseWAL(walData)
+
+	if ok {
+		if err = this.applyRecords(records, pageSize); err != nil {
+			return err
+		}
+	}
+
+	return this.clearWAL()
+}
+
+// parseWAL returns the records in a WAL, the page size they were written with and if the WAL was complete
+func parseWAL(walData []byte) ([]diskWALRecord, int, bool) {
+	headerSize := len(diskWALMagic) + 4
+
+	if len(walData) < headerSize+diskWALTrailerSize || !bytes.Equal(walData[:len(diskWALMagic)], diskWALMagic) {
+		return nil, 0, false
+	}
+
+	pageSize := int(binary.LittleEndian.Uint32(walData[len(diskWALMagic):]))
+	trailer := walData[len(walData)-diskWALTrailerSize:]
+	body := walData[:len(walData)-diskWALTrailerSize]
+
+	if !bytes.Equal(trailer[:len(diskWALCommitMagic)], diskWALCommitMagic) {
+		return nil, 0, false
+	}
+
+	count := binary.LittleEndian.Uint64(trailer[len(diskWALCommitMagic):])
+	checksum := binary.LittleEndian.Uint32(trailer[len(diskWALCommitMagic)+8:])
+
+	if pageSize <= 0 || uint64(len(body)-headerSize) != count*uint64(8+pageSize) || crc32.ChecksumIEEE(body) != checksum {
+		return nil, 0, false
+	}
+
+	records := make([]diskWALRecord, 0, count)
+
+	for offset := headerSize; offset < len(body); offset += 8 + pageSize {
+		records = append(records, diskWALRecord{
+			page: binary.LittleEndian.Uint64(body[offset:]),
+			data: body[offset+8 : offset+8+pageSize],
+		})
+	}
+
+	return records, pageSize, true
+}
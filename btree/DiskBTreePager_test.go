@@ -0,0 +1,219 @@
+package btree
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// crashAfterWAL does the first half of Sync, writing the WAL but never touching the file, then drops the tree
+func crashAfterWAL(t *testing.T, bTree *DiskBTree[int, record]) {
+	if err := bTree.writeWAL(bTree.dirtyRecords()); err != nil {
+		t.Fatal(err)
+	}
+
+	bTree.file.Close()
+	bTree.wal.Close()
+}
+
+func TestDiskBTreeRecoversCommittedWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.db")
+
+	bTree := openRecordTree(t, path, smallConfig)
+	for id := 0; id < 50; id++ {
+		bTree.Insert(record{id: id})
+	}
+	bTree.Sync()
+
+	for id := 50; id < 100; id++ {
+		bTree.Insert(record{id: id})
+	}
+	bTree.Remove(0)
+	crashAfterWAL(t, bTree)
+
+	reopened := openRecordTree(t, path, smallConfig)
+	defer reopened.Close()
+
+	expectIDs(t, iterIDs(t, reopened.Iterator()), idRange(1, 100))
+
+	if info, _ := reopened.wal.Stat(); info.Size() != 0 {
+		t.Fatal("expected WAL to be cleared after recovery")
+	}
+}
+
+func TestDiskBTreeIgnoresIncompleteWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.db")
+
+	bTree := openRecordTree(t, path, smallConfig)
+	for id := 0; id < 50; id++ {
+		bTree.Insert(record{id: id})
+	}
+	bTree.Sync()
+
+	for id := 50; id < 100; id++ {
+		bTree.Insert(record{id: id})
+	}
+	crashAfterWAL(t, bTree)
+
+	// Chop off part of the commit record as if the crash happened while writing it
+	wal := filepath.Clean(path + ".wal")
+	if err := truncateBy(wal, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := openRecordTree(t, path, smallConfig)
+	defer reopened.Close()
+
+	expectIDs(t, iterIDs(t, reopened.Iterator()), idRange(0, 50))
+}
+
+func TestDiskBTreeUnsyncedChangesAreLostOnCrash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.db")
+
+	bTree := openRecordTree(t, path, smallConfig)
+	for id := 0; id < 10; id++ {
+		bTree.Insert(record{id: id})
+	}
+	bTree.Sync()
+	bTree.Insert(record{id: 10})
+	bTree.file.Close()
+	bTree.wal.Close()
+
+	reopened := openRecordTree(t, path, smallConfig)
+	defer reopened.Close()
+
+	expectIDs(t, iterIDs(t, reopened.Iterator()), idRange(0, 10))
+}
+
+func TestParseWALRejectsCorruption(t *testing.T) {
+	bTree := openRecordTree(t, filepath.Join(t.TempDir(), "tree.db"), smallConfig)
+	defer bTree.Close()
+
+	bTree.Insert(record{id: 1})
+	records := bTree.dirtyRecords()
+
+	bTree.writeWAL(records)
+	info, _ := bTree.wal.Stat()
+	walData := make([]byte, info.Size())
+	bTree.wal.ReadAt(walData, 0)
+
+	if parsed, pageSize, ok := parseWAL(walData); !ok || len(parsed) != len(records) || pageSize != bTree.pageSize {
+		t.Fatal("expected valid WAL to parse")
+	}
+
+	flipped := append([]byte{}, walData...)
+	flipped[20] ^= 0xFF
+	if _, _, ok := parseWAL(flipped); ok {
+		t.Fatal("expected checksum failure")
+	}
+
+	if _, _, ok := parseWAL(walData[:len(walData)-1]); ok {
+		t.Fatal("expected truncated WAL to fail")
+	}
+
+	if _, _, ok := parseWAL([]byte("nope")); ok {
+		t.Fatal("expected garbage to fail")
+	}
+}
+
+func TestDiskNodeEncodeDecodeRoundTrip(t *testing.T) {
+	bTree := openRecordTree(t, filepath.Join(t.TempDir(), "tree.db"), smallConfig)
+	defer bTree.Close()
+
+	internal := &diskNode[int, record]{page: 5, pageType: diskPageTypeInternal, children: []uint64{7, 8, 9}}
+	for _, key := range []int{10, 20} {
+		entry, _ := intCodec().Encode(key)
+		internal.keys = append(internal.keys, key)
+		internal.entries = append(internal.entries, entry)
+	}
+
+	decoded, err := bTree.decodeNode(5, internal.encode(bTree.pageSize))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.keys) != 2 || decoded.keys[1] != 20 || len(decoded.children) != 3 || decoded.children[2] != 9 {
+		t.Fatalf("internal node did not round trip: %+v", decoded)
+	}
+
+	free := &diskNode[int, record]{page: 6, pageType: diskPageTypeFree, nextFree: 42}
+	decoded, err = bTree.decodeNode(6, free.encode(bTree.pageSize))
+
+	if err != nil || decoded.nextFree != 42 {
+		t.Fatal("free node did not round trip")
+	}
+}
+
+func TestDecodeDiskMetaRejectsBadPageSize(t *testing.T) {
+	for _, pageSize := range []uint32{0, minDiskPageSize - 1, maxDiskPageSize + 1, 1 << 31} {
+		meta := diskMeta{pageSize: pageSize, pageCount: 1}
+
+		if _, err := decodeDiskMeta(meta.encode(minDiskPageSize)); err == nil {
+			t.Fatalf("expected error for page size %d", pageSize)
+		}
+	}
+
+	meta := diskMeta{pageSize: defaultDiskPageSize, pageCount: 1}
+
+	if decoded, err := decodeDiskMeta(meta.encode(minDiskPageSize)); err != nil || decoded != meta {
+		t.Fatal("expected good meta to round trip")
+	}
+}
+
+func TestDiskNodeDecodeCorruptPagesReturnsError(t *testing.T) {
+	bTree := openRecordTree(t, filepath.Join(t.TempDir(), "tree.db"), smallConfig)
+	defer bTree.Close()
+
+	internal := &diskNode[int, record]{page: 5, pageType: diskPageTypeInternal, children: []uint64{7, 8, 9}}
+	for _, key := range []int{10, 20} {
+		entry, _ := intCodec().Encode(key)
+		internal.keys = append(internal.keys, key)
+		internal.entries = append(internal.entries, entry)
+	}
+
+	// Cut just before and inside the last child pointer
+	encoded := internal.encode(bTree.pageSize)
+	size := internal.size()
+
+	for _, cut := range []int{0, 1, 2, diskNodeHeaderSize + 4, size - diskChildSize, size - 1} {
+		if _, err := bTree.decodeNode(5, encoded[:cut]); err == nil {
+			t.Fatalf("expected error for internal page cut to %d bytes", cut)
+		}
+	}
+
+	free := &diskNode[int, record]{page: 6, pageType: diskPageTypeFree, nextFree: 42}
+	if _, err := bTree.decodeNode(6, free.encode(bTree.pageSize)[:5]); err == nil {
+		t.Fatal("expected error for truncated free page")
+	}
+
+	// A count far bigger than the page can hold
+	bigCount := make([]byte, bTree.pageSize)
+	bigCount[0] = diskPageTypeLeaf
+	bigCount[1], bigCount[2] = 0xFF, 0xFF
+
+	if _, err := bTree.decodeNode(7, bigCount); err == nil {
+		t.Fatal("expected error for a count the page can't hold")
+	}
+
+	// An entry length that runs off the end of the page
+	hugeEntry := make([]byte, diskNodeHeaderSize, bTree.pageSize)
+	hugeEntry[0], hugeEntry[1] = diskPageTypeLeaf, 1
+	hugeEntry = binary.AppendUvarint(hugeEntry, math.MaxUint64)
+
+	if _, err := bTree.decodeNode(8, hugeEntry); err == nil {
+		t.Fatal("expected error for an entry longer than the page")
+	}
+}
+
+func truncateBy(path string, count int64) error {
+	info, err := os.Stat(path)
+
+	if err != nil {
+		return err
+	}
+
+	return os.Truncate(path, info.Size()-count)
+}
@@ -0,0 +1,349 @@
+package btree
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+type record struct {
+	id   int
+	name string
+}
+
+func recordID(r record) int {
+	return r.id
+}
+
+func compInts(a, b int) int {
+	return a - b
+}
+
+func intCodec() godatacollections.Codec[int] {
+	return godatacollections.CodecFuncs[int]{
+		EncodeFunc: func(i int) ([]byte, error) {
+			return binary.AppendVarint(nil, int64(i)), nil
+		},
+		DecodeFunc: func(data []byte) (int, error) {
+			i, n := binary.Varint(data)
+			if n <= 0 {
+				return 0, errors.New("bad varint")
+			}
+			return int(i), nil
+		},
+	}
+}
+
+func recordCodec() godatacollections.Codec[record] {
+	return godatacollections.CodecFuncs[record]{
+		EncodeFunc: func(r record) ([]byte, error) {
+			data := binary.AppendVarint(nil, int64(r.id))
+			return append(data, r.name...), nil
+		},
+		DecodeFunc: func(data []byte) (record, error) {
+			id, n := binary.Varint(data)
+			if n <= 0 {
+				return record{}, errors.New("bad varint")
+			}
+			return record{id: int(id), name: string(data[n:])}, nil
+		},
+	}
+}
+
+func openRecordTree(t *testing.T, path string, config DiskBTreeConfig) *DiskBTree[int, record] {
+	bTree, err := OpenDiskBTree(path, compInts, recordID, intCodec(), recordCodec(), record{}, config)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return bTree
+}
+
+// smallConfig uses the smallest pages and a tiny cache so that splits, frees and evictions all happen with few items
+var smallConfig = DiskBTreeConfig{PageSize: minDiskPageSize, CachePages: 4}
+
+func iterIDs(t *testing.T, iter godatacollections.Iterator[record]) []int {
+	ids := make([]int, 0)
+
+	for iter.HasNext() {
+		r, err := iter.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, r.id)
+	}
+
+	return ids
+}
+
+func expectIDs(t *testing.T, actual []int, expected []int) {
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d ids but got %d: %v", len(expected), len(actual), actual)
+	}
+
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("expected %d at %d but got %d", expected[i], i, actual[i])
+		}
+	}
+}
+
+func idRange(start, end int) []int {
+	ids := make([]int, 0, end-start)
+	for i := start; i < end; i++ {
+		ids = append(ids, i)
+	}
+	return ids
+}
+
+func TestDiskBTreeImplementsSet(t *testing.T) {
+	var _ godatacollections.Set[int, record] = &DiskBTree[int, record]{}
+}
+
+func TestOpenDiskBTreeReturnsErrorForBadArgs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.db")
+
+	if _, err := OpenDiskBTree(path, nil, recordID, intCodec(), recordCodec(), record{}, DiskBTreeConfig{}); err == nil {
+		t.Fail()
+	}
+
+	if _, err := OpenDiskBTree[int, record](path, compInts, nil, intCodec(), recordCodec(), record{}, DiskBTreeConfig{}); err == nil {
+		t.Fail()
+	}
+
+	if _, err := OpenDiskBTree(path, compInts, recordID, nil, recordCodec(), record{}, DiskBTreeConfig{}); err == nil {
+		t.Fail()
+	}
+
+	if _, err := OpenDiskBTree(path, compInts, recordID, intCodec(), recordCodec(), record{}, DiskBTreeConfig{PageSize: 10}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestOpenDiskBTreeReturnsErrorForNonTreeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.db")
+
+	bTree := openRecordTree(t, path, DiskBTreeConfig{})
+	bTree.file.WriteAt([]byte("garbage!"), 0)
+	bTree.file.Close()
+	bTree.wal.Close()
+
+	if _, err := OpenDiskBTree(path, compInts, recordID, intCodec(), recordCodec(), record{}, DiskBTreeConfig{}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestDiskBTreeInsertContainsAndGet(t *testing.T) {
+	bTree := openRecordTree(t, filepath.Join(t.TempDir(), "tree.db"), smallConfig)
+	defer bTree.Close()
+
+	for _, id := range rand.Perm(500) {
+		if err := bTree.Insert(record{id: id, name: fmt.Sprint("name", id)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if bTree.Len() != 500 {
+		t.Fatalf("expected 500 items but got %d", bTree.Len())
+	}
+
+	for id := 0; id < 500; id++ {
+		if !bTree.Contains(id) {
+			t.Fatalf("expected tree to contain %d", id)
+		}
+
+		if bTree.GetByKey(id).name != fmt.Sprint("name", id) {
+			t.Fatalf("wrong item for %d", id)
+		}
+	}
+
+	if bTree.Contains(500) || bTree.GetByKey(-1).id != 0 {
+		t.Fail()
+	}
+
+	if bTree.Err() != nil {
+		t.Fatal(bTree.Err())
+	}
+
+	expectIDs(t, iterIDs(t, bTree.Iterator()), idRange(0, 500))
+}
+
+func TestDiskBTreeInsertDuplicateReturnsError(t *testing.T) {
+	bTree := openRecordTree(t, filepath.Join(t.TempDir(), "tree.db"), DiskBTreeConfig{})
+	defer bTree.Close()
+
+	bTree.Insert(record{id: 1})
+
+	if bTree.Insert(record{id: 1}) == nil {
+		t.Fail()
+	}
+
+	if bTree.Len() != 1 {
+		t.Fail()
+	}
+}
+
+func TestDiskBTreeInsertTooLargeReturnsError(t *testing.T) {
+	bTree := openRecordTree(t, filepath.Join(t.TempDir(), "tree.db"), smallConfig)
+	defer bTree.Close()
+
+	bigName := make([]byte, minDiskPageSize)
+
+	if bTree.Insert(record{id: 1, name: string(bigName)}) == nil {
+		t.Fail()
+	}
+}
+
+func TestDiskBTreePersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.db")
+
+	bTree := openRecordTree(t, path, smallConfig)
+	for _, id := range rand.Perm(300) {
+		bTree.Insert(record{id: id, name: "x"})
+	}
+
+	if err := bTree.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := openRecordTree(t, path, smallConfig)
+	defer reopened.Close()
+
+	if reopened.Len() != 300 {
+		t.Fatalf("expected 300 items after reopen but got %d", reopened.Len())
+	}
+
+	expectIDs(t, iterIDs(t, reopened.Iterator()), idRange(0, 300))
+}
+
+func TestDiskBTreeRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.db")
+	bTree := openRecordTree(t, path, smallConfig)
+
+	for _, id := range rand.Perm(400) {
+		bTree.Insert(record{id: id})
+	}
+
+	// Remove everything that isn't a multiple of 10
+	for _, id := range rand.Perm(400) {
+		if id%10 != 0 {
+			if err := bTree.Remove(id); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if bTree.Remove(1) == nil {
+		t.Fatal("expected error removing missing item")
+	}
+
+	expected := make([]int, 0)
+	for id := 0; id < 400; id += 10 {
+		expected = append(expected, id)
+	}
+
+	expectIDs(t, iterIDs(t, bTree.Iterator()), expected)
+	bTree.Close()
+
+	reopened := openRecordTree(t, path, smallConfig)
+	expectIDs(t, iterIDs(t, reopened.Iterator()), expected)
+
+	// Now remove the rest so that the tree collapses back to a single leaf
+	for _, id := range expected {
+		if err := reopened.Remove(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if reopened.Len() != 0 || reopened.Iterator().HasNext() {
+		t.Fatal("expected tree to be empty")
+	}
+
+	root, _ := reopened.node(reopened.meta.root)
+	if root.pageType != diskPageTypeLeaf {
+		t.Fatal("expected empty tree to have a leaf root")
+	}
+
+	// Still usable after being emptied
+	reopened.Insert(record{id: 7})
+	expectIDs(t, iterIDs(t, reopened.Iterator()), []int{7})
+	reopened.Close()
+}
+
+func TestDiskBTreeReusesFreedPages(t *testing.T) {
+	bTree := openRecordTree(t, filepath.Join(t.TempDir(), "tree.db"), smallConfig)
+	defer bTree.Close()
+
+	for id := 0; id < 300; id++ {
+		bTree.Insert(record{id: id})
+	}
+	bTree.Sync()
+
+	pageCount := bTree.meta.pageCount
+
+	for id := 0; id < 300; id++ {
+		bTree.Remove(id)
+	}
+
+	if bTree.meta.freeListHead == 0 {
+		t.Fatal("expected pages to be on the free list")
+	}
+
+	for id := 0; id < 300; id++ {
+		bTree.Insert(record{id: id})
+	}
+
+	if bTree.meta.pageCount > pageCount {
+		t.Fatalf("expected file to not grow past %d pages but it is %d", pageCount, bTree.meta.pageCount)
+	}
+}
+
+func TestDiskBTreeRangeIterator(t *testing.T) {
+	bTree := openRecordTree(t, filepath.Join(t.TempDir(), "tree.db"), smallConfig)
+	defer bTree.Close()
+
+	// Only even ids so that range ends land between items
+	for _, id := range rand.Perm(200) {
+		bTree.Insert(record{id: id * 2})
+	}
+
+	ids := iterIDs(t, bTree.RangeIterator(101, 151))
+	expected := make([]int, 0)
+	for id := 102; id < 151; id += 2 {
+		expected = append(expected, id)
+	}
+	expectIDs(t, ids, expected)
+
+	expectIDs(t, iterIDs(t, bTree.RangeIterator(-100, 4)), []int{0, 2})
+	expectIDs(t, iterIDs(t, bTree.RangeIterator(396, 10000)), []int{396, 398})
+	expectIDs(t, iterIDs(t, bTree.RangeIterator(50, 50)), []int{})
+	expectIDs(t, iterIDs(t, bTree.RangeIterator(1000, 2000)), []int{})
+}
+
+func TestDiskBTreeCacheStaysBounded(t *testing.T) {
+	bTree := openRecordTree(t, filepath.Join(t.TempDir(), "tree.db"), smallConfig)
+	defer bTree.Close()
+
+	for id := 0; id < 1000; id++ {
+		bTree.Insert(record{id: id})
+	}
+	bTree.Sync()
+
+	for id := 0; id < 1000; id++ {
+		bTree.Contains(id)
+	}
+
+	if len(bTree.cache) > smallConfig.CachePages {
+		t.Fatalf("expected at most %d cached pages but had %d", smallConfig.CachePages, len(bTree.cache))
+	}
+
+	if bTree.lru.Len() != len(bTree.cache) {
+		t.Fatal("expected lru and cache to agree")
+	}
+}
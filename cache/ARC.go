@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// ARC is a fixed capacity Adaptive Replacement Cache (Megiddo and Modha)
+// It splits the cache between items seen once recently (recent) and items seen more than once (frequent)
+// and remembers the keys of items recently evicted from each (ghosts). A Put for a ghost key shows
+// which side evicted too soon, so the split moves towards that side. This makes it hold up against
+// scans that would flush an LRU while still adapting when the working set changes
+// ARC is not safe for use by multiple go routines
+type ARC[K comparable, V any] struct {
+	capacity  int
+	vToKFunc  func(V) K
+	zeroValue V
+	onEvict   func(V)
+	// items has every key in any of the four lists. Ghost entries keep their key but not their value
+	items map[K]*listEntry[arcItem[K, V]]
+	// recent (T1) and frequent (T2) hold the cached items, ghostRecent (B1) and ghostFrequent (B2) the evicted keys
+	// All four have the most recently used at the front
+	recent        *entryList[arcItem[K, V]]
+	frequent      *entryList[arcItem[K, V]]
+	ghostRecent   *entryList[arcItem[K, V]]
+	ghostFrequent *entryList[arcItem[K, V]]
+	// recentTarget (p) is how many of the cached items the recent list is aiming to hold
+	recentTarget int
+	stats        Stats
+}
+
+type arcItem[K comparable, V any] struct {
+	key  K
+	v    V
+	list *entryList[arcItem[K, V]]
+}
+
+// NewARC creates a new empty ARC that holds up to capacity items
+// It also remembers up to capacity evicted keys
+// Arguments are the same as for NewLRU
+func NewARC[K comparable, V any](capacity int, vToKFunc func(V) K, vZeroValue V) (*ARC[K, V], error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf("unable to create ARC with capacity %d, needs to be at least 1", capacity)
+	}
+
+	if vToKFunc == nil {
+		return nil, errors.New("unable to create ARC without a function to convert V to a Key")
+	}
+
+	return &ARC[K, V]{capacity: capacity, vToKFunc: vToKFunc, zeroValue: vZeroValue, items: make(map[K]*listEntry[arcItem[K, V]], 2*capacity),
+		recent: newEntryList[arcItem[K, V]](), frequent: newEntryList[arcItem[K, V]](),
+		ghostRecent: newEntryList[arcItem[K, V]](), ghostFrequent: newEntryList[arcItem[K, V]]()}, nil
+}
+
+func (this *ARC[K, V]) OnEvict(onEvict func(V)) {
+	this.onEvict = onEvict
+}
+
+// Len returns the number of cached items, not counting ghost keys
+func (this *ARC[K, V]) Len() int {
+	return this.recent.len + this.frequent.len
+}
+
+func (this *ARC[K, V]) Cap() int {
+	return this.capacity
+}
+
+func (this *ARC[K, V]) Stats() Stats {
+	return this.stats
+}
+
+func (this *ARC[K, V]) isCached(entry *listEntry[arcItem[K, V]]) bool {
+	return entry.v.list == this.recent || entry.v.list == this.frequent
+}
+
+// moveTo moves entry to the front of list
+func (this *ARC[K, V]) moveTo(entry *listEntry[arcItem[K, V]], list *entryList[arcItem[K, V]]) {
+	entry.v.list.remove(entry)
+	entry.v.list = list
+	list.pushFront(entry)
+}
+
+// drop removes entry from its list and the cache completely
+func (this *ARC[K, V]) drop(entry *listEntry[arcItem[K, V]]) {
+	entry.v.list.remove(entry)
+	delete(this.items, entry.v.key)
+}
+
+// evictTo evicts the least recently used item from a cached list and keeps its key in ghostList
+func (this *ARC[K, V]) evictTo(from, ghostList *entryList[arcItem[K, V]]) {
+	victim := from.back()
+	evicted := victim.v.v
+
+	victim.v.v = this.zeroValue
+	this.moveTo(victim, ghostList)
+	this.notifyEvict(evicted)
+}
+
+func (this *ARC[K, V]) notifyEvict(v V) {
+	this.stats.Evictions++
+
+	if this.onEvict != nil {
+		this.onEvict(v)
+	}
+}
+
+// replace makes room for one more cached item by evicting from whichever side is over its target
+// Does nothing if there is already room, which can happen after a Remove
+// inGhostFrequent is if the key being added was just found in ghostFrequent
+func (this *ARC[K, V]) replace(inGhostFrequent bool) {
+	if this.Len() < this.capacity {
+		return
+	}
+
+	if this.recent.len > 0 && (this.frequent.len == 0 || this.recent.len > this.recentTarget || (inGhostFrequent && this.recent.len == this.recentTarget)) {
+		this.evictTo(this.recent, this.ghostRecent)
+	} else {
+		this.evictTo(this.frequent, this.ghostFrequent)
+	}
+}
+
+func (this *ARC[K, V]) Get(key K) (V, error) {
+	entry, ok := this.items[key]
+
+	if !ok || !this.isCached(entry) {
+		this.stats.Misses++
+		return this.zeroValue, godatacollections.NotFoundError()
+	}
+
+	this.stats.Hits++
+	// Any use past the first makes an item frequent
+	this.moveTo(entry, this.frequent)
+
+	return entry.v.v, nil
+}
+
+func (this *ARC[K, V]) Contains(key K) bool {
+	entry, ok := this.items[key]
+
+	return ok && this.isCached(entry)
+}
+
+func (this *ARC[K, V]) Put(v V) {
+	key := this.vToKFunc(v)
+	entry, ok := this.items[key]
+
+	if ok && this.isCached(entry) {
+		entry.v.v = v
+		this.moveTo(entry, this.frequent)
+		return
+	}
+
+	if ok {
+		// Key was evicted recently so grow the side it was evicted from, more so when that side's ghost list is small
+		if entry.v.list == this.ghostRecent {
+			this.recentTarget = min(this.capacity, this.recentTarget+max(this.ghostFrequent.len/this.ghostRecent.len, 1))
+			this.replace(false)
+		} else {
+			this.recentTarget = max(0, this.recentTarget-max(this.ghostRecent.len/this.ghostFrequent.len, 1))
+			this.replace(true)
+		}
+
+		entry.v.v = v
+		this.moveTo(entry, this.frequent)
+		return
+	}
+
+	// Brand new key. Keep recent plus its ghosts within capacity and all lists within twice capacity
+	recentSide := this.recent.len + this.ghostRecent.len
+
+	if recentSide == this.capacity {
+		if this.recent.len < this.capacity {
+			this.drop(this.ghostRecent.back())
+			this.replace(false)
+		} else {
+			// Everything is in recent so evict with no ghost to remember it by
+			victim := this.recent.back()
+			this.drop(victim)
+			this.notifyEvict(victim.v.v)
+		}
+	} else if recentSide < this.capacity {
+		total := recentSide + this.frequent.len + this.ghostFrequent.len
+
+		if total >= this.capacity {
+			if total == 2*this.capacity {
+				this.drop(this.ghostFrequent.back())
+			}
+
+			this.replace(false)
+		}
+	}
+
+	entry = &listEntry[arcItem[K, V]]{v: arcItem[K, V]{key: key, v: v, list: this.recent}}
+	this.items[key] = entry
+	this.recent.pushFront(entry)
+}
+
+// Remove removes the item for key from the cache, along with any memory of it
+func (this *ARC[K, V]) Remove(key K) error {
+	entry, ok := this.items[key]
+
+	if !ok || !this.isCached(entry) {
+		return fmt.Errorf("unable to delete item with key %v due to it not existing in cache", key)
+	}
+
+	this.drop(entry)
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func sessionARC(capacity int) *ARC[int, *session] {
+	arc, _ := NewARC(capacity, sessionID, nil)
+
+	return arc
+}
+
+// checkARC makes sure the list sizes stay within the bounds from the paper
+func checkARC(t *testing.T, arc *ARC[int, *session]) {
+	c := arc.capacity
+
+	if arc.recent.len+arc.frequent.len > c {
+		t.Fatalf("cached %d items past capacity %d", arc.recent.len+arc.frequent.len, c)
+	}
+
+	if arc.recent.len+arc.ghostRecent.len > c {
+		t.Fatalf("recent side has %d entries past capacity %d", arc.recent.len+arc.ghostRecent.len, c)
+	}
+
+	if total := arc.recent.len + arc.frequent.len + arc.ghostRecent.len + arc.ghostFrequent.len; total > 2*c || total != len(arc.items) {
+		t.Fatalf("lists hold %d entries but map has %d", total, len(arc.items))
+	}
+
+	if arc.recentTarget < 0 || arc.recentTarget > c {
+		t.Fatalf("recent target %d out of range", arc.recentTarget)
+	}
+}
+
+func TestNewARCErrors(t *testing.T) {
+	if _, err := NewARC(0, sessionID, nil); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewARC[int, *session](1, nil, nil); err == nil {
+		t.Fail()
+	}
+}
+
+func TestARCResistsScans(t *testing.T) {
+	arc := sessionARC(10)
+	lru := sessionLRU(10)
+
+	// Build up a hot set used more than once
+	for round := 0; round < 3; round++ {
+		for id := 0; id < 5; id++ {
+			for _, cache := range []Cache[int, *session]{arc, lru} {
+				if _, err := cache.Get(id); err != nil {
+					cache.Put(&session{id: id})
+				}
+			}
+		}
+	}
+
+	// A long scan of items used once
+	for id := 100; id < 200; id++ {
+		arc.Put(&session{id: id})
+		lru.Put(&session{id: id})
+	}
+
+	for id := 0; id < 5; id++ {
+		if !arc.Contains(id) {
+			t.Fatalf("ARC lost hot item %d to a scan", id)
+		}
+
+		if lru.Contains(id) {
+			t.Fatalf("expected LRU to lose hot item %d to a scan", id)
+		}
+	}
+
+	checkARC(t, arc)
+}
+
+func TestARCGhostHitGrowsTarget(t *testing.T) {
+	arc := sessionARC(4)
+
+	// 0 and 1 become frequent so that recent is over its target of 0 when 4 needs room
+	arc.Put(&session{id: 0})
+	arc.Put(&session{id: 1})
+	arc.Get(0)
+	arc.Get(1)
+	arc.Put(&session{id: 2})
+	arc.Put(&session{id: 3})
+	arc.Put(&session{id: 4})
+
+	if arc.Contains(2) || arc.items[2] == nil || arc.items[2].v.list != arc.ghostRecent {
+		t.Fatal("expected 2 to be a recent ghost")
+	}
+
+	before := arc.recentTarget
+	arc.Put(&session{id: 2})
+
+	if arc.recentTarget <= before {
+		t.Fatal("expected recent target to grow after a recent ghost hit")
+	}
+
+	if !arc.Contains(2) || arc.items[2].v.list != arc.frequent {
+		t.Fatal("expected ghost hit to be cached as frequent")
+	}
+
+	checkARC(t, arc)
+}
+
+func TestARCRandomKeepsInvariants(t *testing.T) {
+	arc := sessionARC(16)
+
+	for i := 0; i < 20000; i++ {
+		// Skewed keys so that there is a mix of hot and cold items
+		id := rand.Intn(8)
+		if rand.Intn(2) == 0 {
+			id = rand.Intn(100)
+		}
+
+		switch rand.Intn(10) {
+		case 0:
+			arc.Remove(id)
+		case 1, 2, 3, 4:
+			arc.Put(&session{id: id})
+		default:
+			if s, err := arc.Get(id); err == nil && s.id != id {
+				t.Fatalf("got %d for key %d", s.id, id)
+			}
+		}
+
+		checkARC(t, arc)
+	}
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// Cache is a fixed capacity store of items of type V that decides for itself which items to evict when it is full
+// Each policy (LRU, LFU, ARC, TTL) makes that decision differently
+type Cache[K comparable, V any] interface {
+	// Get returns the item for key and counts it as a use
+	// Returns zero value and NotFoundError if key is not in the cache
+	Get(key K) (V, error)
+
+	// Contains returns if key is in the cache without counting it as a use
+	Contains(key K) bool
+
+	// Put adds v, replacing any item with the same key and evicting an item if the cache is full
+	Put(v V)
+
+	// Remove removes the item for key from the cache. Removed items are not passed to OnEvict
+	Remove(key K) error
+
+	// OnEvict sets a function to be called with each item that the cache drops on its own
+	OnEvict(onEvict func(V))
+
+	Len() int
+	Cap() int
+	Stats() Stats
+}
+
+// Stats counts how well a cache is doing
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// HitRate returns the fraction of Gets that were hits or 0 if there have been no Gets
+func (this Stats) HitRate() float64 {
+	total := this.Hits + this.Misses
+
+	if total == 0 {
+		return 0
+	}
+
+	return float64(this.Hits) / float64(total)
+}
+
+// Clock returns the current time. Caches that care about time take one so that tests can control it
+type Clock func() time.Time
+
+type sliceIterator[V any] struct {
+	values    []V
+	zeroValue V
+}
+
+func (this *sliceIterator[V]) Close() error {
+	return nil
+}
+
+func (this *sliceIterator[V]) HasNext() bool {
+	return len(this.values) > 0
+}
+
+func (this *sliceIterator[V]) Next() (V, error) {
+	if len(this.values) == 0 {
+		return this.zeroValue, errors.New("nothing left to iterate over")
+	}
+
+	retNext := this.values[0]
+	this.values = this.values[1:]
+
+	return retNext, nil
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// Every policy needs to be usable anywhere a Cache is
+var _ Cache[int, *session] = &LRU[int, *session]{}
+var _ Cache[int, *session] = &SyncLRU[int, *session]{}
+var _ Cache[int, *session] = &LFU[int, *session]{}
+var _ Cache[int, *session] = &ARC[int, *session]{}
+var _ Cache[int, *session] = &TTL[int, *session]{}
+
+func allPolicies(t *testing.T, capacity int) map[string]Cache[int, *session] {
+	lru, _ := NewLRU(capacity, sessionID, nil)
+	syncLRU, _ := NewSyncLRU(capacity, sessionID, nil)
+	lfu, _ := NewLFU(capacity, sessionID, nil)
+	arc, _ := NewARC(capacity, sessionID, nil)
+	ttl, err := NewTTL(capacity, time.Hour, sessionID, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]Cache[int, *session]{"LRU": lru, "SyncLRU": syncLRU, "LFU": lfu, "ARC": arc, "TTL": ttl}
+}
+
+func TestStatsHitRate(t *testing.T) {
+	if (Stats{}).HitRate() != 0 {
+		t.Fail()
+	}
+
+	if (Stats{Hits: 3, Misses: 1}).HitRate() != 0.75 {
+		t.Fail()
+	}
+}
+
+func TestCachePoliciesBasicContract(t *testing.T) {
+	for name, cache := range allPolicies(t, 10) {
+		evicted := 0
+		cache.OnEvict(func(s *session) { evicted++ })
+
+		for i := 0; i < 100; i++ {
+			cache.Put(&session{id: i})
+
+			if !cache.Contains(i) {
+				t.Fatalf("%s: expected to contain %d right after putting it", name, i)
+			}
+
+			if cache.Len() > cache.Cap() {
+				t.Fatalf("%s: grew to %d past capacity", name, cache.Len())
+			}
+		}
+
+		if cache.Len() != 10 || evicted != 90 || cache.Stats().Evictions != 90 {
+			t.Fatalf("%s: expected 10 items and 90 evictions but got %d and %d", name, cache.Len(), evicted)
+		}
+
+		if s, err := cache.Get(99); err != nil || s.id != 99 {
+			t.Fatalf("%s: expected to get 99", name)
+		}
+
+		if _, err := cache.Get(-1); !godatacollections.IsNotFoundError(err) {
+			t.Fatalf("%s: expected NotFoundError", name)
+		}
+
+		if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+			t.Fatalf("%s: expected 1 hit and 1 miss but got %+v", name, stats)
+		}
+
+		if cache.Remove(99) != nil || cache.Remove(99) == nil || cache.Contains(99) {
+			t.Fatalf("%s: remove did not work", name)
+		}
+
+		// Replacing an item doesn't count as an eviction
+		cache.Put(&session{id: 98, user: "new"})
+
+		if s, _ := cache.Get(98); s.user != "new" || evicted != 90 {
+			t.Fatalf("%s: put did not replace", name)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package cache
+
+// entryList is a doubly linked list that the caches use to keep entries in use order
+// Entries are handed out so that a cache can hold on to them in its map and move or remove them in O(1)
+type entryList[T any] struct {
+	// head is a sentinel. head.next is the front and head.prev the back
+	head listEntry[T]
+	len  int
+}
+
+type listEntry[T any] struct {
+	v    T
+	prev *listEntry[T]
+	next *listEntry[T]
+}
+
+func newEntryList[T any]() *entryList[T] {
+	list := &entryList[T]{}
+	list.head.prev = &list.head
+	list.head.next = &list.head
+
+	return list
+}
+
+func (this *entryList[T]) pushFront(entry *listEntry[T]) {
+	entry.prev = &this.head
+	entry.next = this.head.next
+	this.head.next.prev = entry
+	this.head.next = entry
+	this.len++
+}
+
+func (this *entryList[T]) remove(entry *listEntry[T]) {
+	entry.prev.next = entry.next
+	entry.next.prev = entry.prev
+	entry.prev = nil
+	entry.next = nil
+	this.len--
+}
+
+// back returns the entry at the back of the list or nil if it is empty
+func (this *entryList[T]) back() *listEntry[T] {
+	if this.len == 0 {
+		return nil
+	}
+
+	return this.head.prev
+}
+
+// values returns every value from front to back
+func (this *entryList[T]) values() []T {
+	values := make([]T, 0, this.len)
+
+	for entry := this.head.next; entry != &this.head; entry = entry.next {
+		values = append(values, entry.v)
+	}
+
+	return values
+}
@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// LFU is a fixed capacity cache that evicts the least frequently used item when it is full
+// Ties between items used the same number of times go to the least recently used of them
+// Every operation is O(1) by keeping a list of items for each use count
+// LFU is not safe for use by multiple go routines
+type LFU[K comparable, V any] struct {
+	capacity  int
+	vToKFunc  func(V) K
+	zeroValue V
+	onEvict   func(V)
+	items     map[K]*listEntry[lfuItem[V]]
+	// byCount holds the items for each use count with the most recently used at the front
+	byCount map[int]*entryList[lfuItem[V]]
+	// minCount is the smallest use count of any item, valid whenever the cache is full
+	minCount int
+	stats    Stats
+}
+
+type lfuItem[V any] struct {
+	v     V
+	count int
+}
+
+// NewLFU creates a new empty LFU that holds up to capacity items
+// Arguments are the same as for NewLRU
+func NewLFU[K comparable, V any](capacity int, vToKFunc func(V) K, vZeroValue V) (*LFU[K, V], error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf("unable to create LFU with capacity %d, needs to be at least 1", capacity)
+	}
+
+	if vToKFunc == nil {
+		return nil, errors.New("unable to create LFU without a function to convert V to a Key")
+	}
+
+	return &LFU[K, V]{capacity: capacity, vToKFunc: vToKFunc, zeroValue: vZeroValue,
+		items: make(map[K]*listEntry[lfuItem[V]], capacity), byCount: make(map[int]*entryList[lfuItem[V]])}, nil
+}
+
+func (this *LFU[K, V]) OnEvict(onEvict func(V)) {
+	this.onEvict = onEvict
+}
+
+func (this *LFU[K, V]) Len() int {
+	return len(this.items)
+}
+
+func (this *LFU[K, V]) Cap() int {
+	return this.capacity
+}
+
+func (this *LFU[K, V]) Stats() Stats {
+	return this.stats
+}
+
+// unlinkCount takes entry out of the list for its use count, dropping the list if it is now empty
+// Returns if the list was dropped
+func (this *LFU[K, V]) unlinkCount(entry *listEntry[lfuItem[V]]) bool {
+	countList := this.byCount[entry.v.count]
+	countList.remove(entry)
+
+	if countList.len == 0 {
+		delete(this.byCount, entry.v.count)
+		return true
+	}
+
+	return false
+}
+
+func (this *LFU[K, V]) linkCount(entry *listEntry[lfuItem[V]]) {
+	countList, ok := this.byCount[entry.v.count]
+
+	if !ok {
+		countList = newEntryList[lfuItem[V]]()
+		this.byCount[entry.v.count] = countList
+	}
+
+	countList.pushFront(entry)
+}
+
+// use moves entry up to the next use count
+func (this *LFU[K, V]) use(entry *listEntry[lfuItem[V]]) {
+	if this.unlinkCount(entry) && this.minCount == entry.v.count {
+		this.minCount++
+	}
+
+	entry.v.count++
+	this.linkCount(entry)
+}
+
+func (this *LFU[K, V]) Get(key K) (V, error) {
+	entry, ok := this.items[key]
+
+	if !ok {
+		this.stats.Misses++
+		return this.zeroValue, godatacollections.NotFoundError()
+	}
+
+	this.stats.Hits++
+	this.use(entry)
+
+	return entry.v.v, nil
+}
+
+func (this *LFU[K, V]) Contains(key K) bool {
+	_, ok := this.items[key]
+
+	return ok
+}
+
+// Put adds v with a use count of 1, or replaces the item with the same key and counts it as a use
+func (this *LFU[K, V]) Put(v V) {
+	key := this.vToKFunc(v)
+
+	if entry, ok := this.items[key]; ok {
+		entry.v.v = v
+		this.use(entry)
+		return
+	}
+
+	if len(this.items) >= this.capacity {
+		this.evict()
+	}
+
+	entry := &listEntry[lfuItem[V]]{v: lfuItem[V]{v: v, count: 1}}
+	this.items[key] = entry
+	this.linkCount(entry)
+	this.minCount = 1
+}
+
+func (this *LFU[K, V]) evict() {
+	victim := this.byCount[this.minCount].back()
+
+	this.unlinkCount(victim)
+	delete(this.items, this.vToKFunc(victim.v.v))
+	this.stats.Evictions++
+
+	if this.onEvict != nil {
+		this.onEvict(victim.v.v)
+	}
+}
+
+func (this *LFU[K, V]) Remove(key K) error {
+	entry, ok := this.items[key]
+
+	if !ok {
+		return fmt.Errorf("unable to delete item with key %v due to it not existing in cache", key)
+	}
+
+	// minCount can go stale here but the cache is no longer full and the next new item resets it
+	this.unlinkCount(entry)
+	delete(this.items, key)
+
+	return nil
+}
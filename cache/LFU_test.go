@@ -0,0 +1,88 @@
+package cache
+
+import "testing"
+
+func sessionLFU(capacity int) *LFU[int, *session] {
+	lfu, _ := NewLFU(capacity, sessionID, nil)
+
+	return lfu
+}
+
+func TestNewLFUErrors(t *testing.T) {
+	if _, err := NewLFU(0, sessionID, nil); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewLFU[int, *session](1, nil, nil); err == nil {
+		t.Fail()
+	}
+}
+
+func TestLFUEvictsLeastFrequentlyUsed(t *testing.T) {
+	lfu := sessionLFU(3)
+	evicted := make([]int, 0)
+	lfu.OnEvict(func(s *session) { evicted = append(evicted, s.id) })
+
+	lfu.Put(&session{id: 1})
+	lfu.Put(&session{id: 2})
+	lfu.Put(&session{id: 3})
+
+	lfu.Get(1)
+	lfu.Get(1)
+	lfu.Get(3)
+
+	// 2 has only been used once
+	lfu.Put(&session{id: 4})
+
+	// 4 is now the only item used once
+	lfu.Put(&session{id: 5})
+
+	expectIDs(t, evicted, 2, 4)
+
+	if !lfu.Contains(1) || !lfu.Contains(3) || !lfu.Contains(5) {
+		t.Fail()
+	}
+}
+
+func TestLFUTiesGoToLeastRecentlyUsed(t *testing.T) {
+	lfu := sessionLFU(3)
+	evicted := make([]int, 0)
+	lfu.OnEvict(func(s *session) { evicted = append(evicted, s.id) })
+
+	lfu.Put(&session{id: 1})
+	lfu.Put(&session{id: 2})
+	lfu.Put(&session{id: 3})
+	lfu.Get(2)
+	lfu.Get(1)
+	lfu.Get(3)
+
+	// All used twice, 2 was used longest ago
+	lfu.Put(&session{id: 4})
+
+	expectIDs(t, evicted, 2)
+}
+
+func TestLFURemoveThenRefill(t *testing.T) {
+	lfu := sessionLFU(2)
+	evicted := make([]int, 0)
+	lfu.OnEvict(func(s *session) { evicted = append(evicted, s.id) })
+
+	lfu.Put(&session{id: 1})
+	lfu.Put(&session{id: 2})
+	lfu.Get(1)
+	lfu.Get(1)
+	lfu.Get(2)
+
+	if err := lfu.Remove(2); err != nil {
+		t.Fatal(err)
+	}
+
+	lfu.Put(&session{id: 3})
+	lfu.Put(&session{id: 4})
+
+	expectIDs(t, evicted, 3)
+
+	if !lfu.Contains(1) || !lfu.Contains(4) {
+		t.Fail()
+	}
+}
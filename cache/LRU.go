@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// LRU is a fixed capacity cache that evicts the least recently used item when it is full
+// Get, Put and Remove are all O(1) using a map to find items and a doubly linked list to keep them in use order
+// Like the trees each V knows its own key through vToKFunc so the key isn't stored separately from the item
+// LRU is not safe for use by multiple go routines, use SyncLRU for that
+type LRU[K comparable, V any] struct {
+	capacity  int
+	vToKFunc  func(V) K
+	zeroValue V
+	onEvict   func(V)
+	items     map[K]*listEntry[V]
+	// order has the most recently used item at the front and the least at the back
+	order *entryList[V]
+	stats Stats
+}
+
+// NewLRU creates a new empty LRU that holds up to capacity items
+// vToKFunc returns the key for an item of type V
+// vZeroValue is the value to return when there is nothing to return
+func NewLRU[K comparable, V any](capacity int, vToKFunc func(V) K, vZeroValue V) (*LRU[K, V], error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf("unable to create LRU with capacity %d, needs to be at least 1", capacity)
+	}
+
+	if vToKFunc == nil {
+		return nil, errors.New("unable to create LRU without a function to convert V to a Key")
+	}
+
+	return &LRU[K, V]{capacity: capacity, vToKFunc: vToKFunc, zeroValue: vZeroValue, items: make(map[K]*listEntry[V], capacity), order: newEntryList[V]()}, nil
+}
+
+// OnEvict sets a function to be called with each item that is evicted to make room for a new one
+// It is not called for items that are removed or replaced
+func (this *LRU[K, V]) OnEvict(onEvict func(V)) {
+	this.onEvict = onEvict
+}
+
+// Len returns the number of items in the cache
+func (this *LRU[K, V]) Len() int {
+	return len(this.items)
+}
+
+// Cap returns the most items the cache will hold
+func (this *LRU[K, V]) Cap() int {
+	return this.capacity
+}
+
+// Stats returns the hits, misses and evictions so far
+func (this *LRU[K, V]) Stats() Stats {
+	return this.stats
+}
+
+// Get returns the item for key and marks it as the most recently used
+// Returns zero value and NotFoundError if key is not in the cache
+func (this *LRU[K, V]) Get(key K) (V, error) {
+	entry, ok := this.items[key]
+
+	if !ok {
+		this.stats.Misses++
+		return this.zeroValue, godatacollections.NotFoundError()
+	}
+
+	this.stats.Hits++
+	this.order.remove(entry)
+	this.order.pushFront(entry)
+
+	return entry.v, nil
+}
+
+// Contains returns if key is in the cache without marking it as used
+func (this *LRU[K, V]) Contains(key K) bool {
+	_, ok := this.items[key]
+
+	return ok
+}
+
+// Put adds v as the most recently used item, replacing any item with the same key
+// If the cache is full the least recently used item is evicted
+func (this *LRU[K, V]) Put(v V) {
+	key := this.vToKFunc(v)
+
+	if entry, ok := this.items[key]; ok {
+		entry.v = v
+		this.order.remove(entry)
+		this.order.pushFront(entry)
+		return
+	}
+
+	if len(this.items) >= this.capacity {
+		this.evict()
+	}
+
+	entry := &listEntry[V]{v: v}
+	this.items[key] = entry
+	this.order.pushFront(entry)
+}
+
+func (this *LRU[K, V]) evict() {
+	oldest := this.order.back()
+
+	this.order.remove(oldest)
+	delete(this.items, this.vToKFunc(oldest.v))
+	this.stats.Evictions++
+
+	if this.onEvict != nil {
+		this.onEvict(oldest.v)
+	}
+}
+
+// Remove removes the item for key from the cache
+func (this *LRU[K, V]) Remove(key K) error {
+	entry, ok := this.items[key]
+
+	if !ok {
+		return fmt.Errorf("unable to delete item with key %v due to it not existing in cache", key)
+	}
+
+	this.order.remove(entry)
+	delete(this.items, key)
+
+	return nil
+}
+
+// Iterator returns an iterator over the items from most to least recently used
+// Iterating does not mark items as used. The items are copied up front so the cache is free to change while iterating
+func (this *LRU[K, V]) Iterator() godatacollections.Iterator[V] {
+	return &sliceIterator[V]{values: this.order.values(), zeroValue: this.zeroValue}
+}
@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+type session struct {
+	id   int
+	user string
+}
+
+func sessionID(s *session) int {
+	return s.id
+}
+
+func sessionLRU(capacity int) *LRU[int, *session] {
+	lru, _ := NewLRU(capacity, sessionID, nil)
+
+	return lru
+}
+
+func sessionIDs(iter godatacollections.Iterator[*session]) []int {
+	ids := make([]int, 0)
+
+	for iter.HasNext() {
+		cur, _ := iter.Next()
+		ids = append(ids, cur.id)
+	}
+
+	return ids
+}
+
+func expectIDs(t *testing.T, actual []int, expected ...int) {
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, actual)
+	}
+
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("expected %v but got %v", expected, actual)
+		}
+	}
+}
+
+func TestNewLRUErrors(t *testing.T) {
+	if _, err := NewLRU(0, sessionID, nil); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewLRU[int, *session](1, nil, nil); err == nil {
+		t.Fail()
+	}
+}
+
+func TestLRUGetMissIsNotFound(t *testing.T) {
+	lru := sessionLRU(2)
+
+	v, err := lru.Get(1)
+	if v != nil || !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	lru := sessionLRU(3)
+	evicted := make([]int, 0)
+	lru.OnEvict(func(s *session) { evicted = append(evicted, s.id) })
+
+	lru.Put(&session{id: 1})
+	lru.Put(&session{id: 2})
+	lru.Put(&session{id: 3})
+
+	// Using 1 makes 2 the least recently used
+	if s, err := lru.Get(1); err != nil || s.id != 1 {
+		t.Fatal("expected to get 1")
+	}
+
+	lru.Put(&session{id: 4})
+
+	expectIDs(t, evicted, 2)
+	expectIDs(t, sessionIDs(lru.Iterator()), 4, 1, 3)
+
+	if lru.Contains(2) || lru.Len() != 3 || lru.Cap() != 3 {
+		t.Fail()
+	}
+}
+
+func TestLRUContainsDoesNotMarkUsed(t *testing.T) {
+	lru := sessionLRU(2)
+
+	lru.Put(&session{id: 1})
+	lru.Put(&session{id: 2})
+	lru.Contains(1)
+	lru.Put(&session{id: 3})
+
+	if lru.Contains(1) {
+		t.Fatal("expected 1 to be evicted")
+	}
+}
+
+func TestLRUPutReplacesWithoutEvicting(t *testing.T) {
+	lru := sessionLRU(2)
+	evictCount := 0
+	lru.OnEvict(func(s *session) { evictCount++ })
+
+	original := &session{id: 1, user: "a"}
+	lru.Put(original)
+	lru.Put(&session{id: 2})
+	lru.Put(&session{id: 1, user: "b"})
+
+	if s, _ := lru.Get(1); s.user != "b" {
+		t.Fatal("expected replaced value")
+	}
+
+	if evictCount != 0 || lru.Len() != 2 {
+		t.Fail()
+	}
+
+	expectIDs(t, sessionIDs(lru.Iterator()), 1, 2)
+}
+
+func TestLRURemove(t *testing.T) {
+	lru := sessionLRU(2)
+	evictCount := 0
+	lru.OnEvict(func(s *session) { evictCount++ })
+
+	lru.Put(&session{id: 1})
+	lru.Put(&session{id: 2})
+
+	if err := lru.Remove(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if lru.Remove(1) == nil {
+		t.Fatal("expected error removing missing key")
+	}
+
+	lru.Put(&session{id: 3})
+
+	if evictCount != 0 {
+		t.Fatal("remove should have made room without evicting")
+	}
+
+	expectIDs(t, sessionIDs(lru.Iterator()), 3, 2)
+}
+
+func TestLRUCapacityOne(t *testing.T) {
+	lru := sessionLRU(1)
+
+	for i := 0; i < 10; i++ {
+		lru.Put(&session{id: i})
+	}
+
+	expectIDs(t, sessionIDs(lru.Iterator()), 9)
+}
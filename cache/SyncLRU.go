@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// SyncLRU is an LRU that is safe for use by multiple go routines
+// Every call takes the same lock as even Get changes the use order
+type SyncLRU[K comparable, V any] struct {
+	mu  sync.Mutex
+	lru *LRU[K, V]
+}
+
+// NewSyncLRU creates a new empty SyncLRU
+// Arguments are the same as for NewLRU
+func NewSyncLRU[K comparable, V any](capacity int, vToKFunc func(V) K, vZeroValue V) (*SyncLRU[K, V], error) {
+	lru, err := NewLRU(capacity, vToKFunc, vZeroValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SyncLRU[K, V]{lru: lru}, nil
+}
+
+// OnEvict is the same as for LRU
+// onEvict is called while the lock is held so it must not call back into the cache
+func (this *SyncLRU[K, V]) OnEvict(onEvict func(V)) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	this.lru.OnEvict(onEvict)
+}
+
+func (this *SyncLRU[K, V]) Len() int {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	return this.lru.Len()
+}
+
+func (this *SyncLRU[K, V]) Cap() int {
+	return this.lru.Cap()
+}
+
+func (this *SyncLRU[K, V]) Get(key K) (V, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	return this.lru.Get(key)
+}
+
+func (this *SyncLRU[K, V]) Contains(key K) bool {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	return this.lru.Contains(key)
+}
+
+func (this *SyncLRU[K, V]) Put(v V) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	this.lru.Put(v)
+}
+
+func (this *SyncLRU[K, V]) Remove(key K) error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	return this.lru.Remove(key)
+}
+
+// Stats is the same as for LRU
+func (this *SyncLRU[K, V]) Stats() Stats {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	return this.lru.Stats()
+}
+
+// Iterator returns an iterator over a copy of the items from most to least recently used
+// The copy is taken up front so the cache is free to change while iterating
+func (this *SyncLRU[K, V]) Iterator() godatacollections.Iterator[V] {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	return this.lru.Iterator()
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestNewSyncLRUErrors(t *testing.T) {
+	if _, err := NewSyncLRU(0, sessionID, nil); err == nil {
+		t.Fail()
+	}
+}
+
+func TestSyncLRUConcurrentUse(t *testing.T) {
+	lru, _ := NewSyncLRU(50, sessionID, nil)
+	var evictCount int
+	lru.OnEvict(func(s *session) { evictCount++ })
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			for i := 0; i < 1000; i++ {
+				id := (w*1000 + i) % 200
+				lru.Put(&session{id: id})
+				lru.Get(id)
+				lru.Contains(id + 1)
+
+				if i%10 == 0 {
+					lru.Remove(id)
+				}
+
+				if i%100 == 0 {
+					sessionIDs(lru.Iterator())
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+
+	if lru.Len() > lru.Cap() {
+		t.Fatalf("cache grew past capacity to %d", lru.Len())
+	}
+
+	if len(sessionIDs(lru.Iterator())) != lru.Len() {
+		t.Fail()
+	}
+
+	if evictCount == 0 {
+		t.Fatal("expected some evictions")
+	}
+}
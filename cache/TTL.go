@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// TTL is an LRU where every item also expires a set time after it was put
+// Expired items are dropped when they are next looked at, or all at once by RemoveExpired
+// so Len can include expired items that haven't been noticed yet
+// Expired items are passed to OnEvict the same as items evicted for space
+// TTL is not safe for use by multiple go routines
+type TTL[K comparable, V any] struct {
+	lru       *LRU[K, *ttlEntry[V]]
+	ttl       time.Duration
+	clock     Clock
+	zeroValue V
+	onEvict   func(V)
+	stats     Stats
+}
+
+type ttlEntry[V any] struct {
+	v       V
+	expires time.Time
+}
+
+// NewTTL creates a new empty TTL that holds up to capacity items which expire ttl after they are put
+// clock is used to tell the time, nil uses time.Now
+// The rest of the arguments are the same as for NewLRU
+func NewTTL[K comparable, V any](capacity int, ttl time.Duration, vToKFunc func(V) K, vZeroValue V, clock Clock) (*TTL[K, V], error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("unable to create TTL with ttl %v, needs to be more than 0", ttl)
+	}
+
+	if vToKFunc == nil {
+		return nil, errors.New("unable to create TTL without a function to convert V to a Key")
+	}
+
+	lru, err := NewLRU(capacity, func(entry *ttlEntry[V]) K { return vToKFunc(entry.v) }, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if clock == nil {
+		clock = time.Now
+	}
+
+	ttlCache := &TTL[K, V]{lru: lru, ttl: ttl, clock: clock, zeroValue: vZeroValue}
+	lru.OnEvict(func(entry *ttlEntry[V]) { ttlCache.notifyEvict(entry.v) })
+
+	return ttlCache, nil
+}
+
+func (this *TTL[K, V]) OnEvict(onEvict func(V)) {
+	this.onEvict = onEvict
+}
+
+func (this *TTL[K, V]) notifyEvict(v V) {
+	this.stats.Evictions++
+
+	if this.onEvict != nil {
+		this.onEvict(v)
+	}
+}
+
+// Len returns the number of items in the cache, which may include expired items that haven't been noticed yet
+func (this *TTL[K, V]) Len() int {
+	return this.lru.Len()
+}
+
+func (this *TTL[K, V]) Cap() int {
+	return this.lru.Cap()
+}
+
+// Stats returns the hits, misses and evictions so far
+// Getting an expired item is a miss and its expiry counts as an eviction
+func (this *TTL[K, V]) Stats() Stats {
+	return this.stats
+}
+
+func (this *TTL[K, V]) isExpired(entry *ttlEntry[V]) bool {
+	return !this.clock().Before(entry.expires)
+}
+
+// expire drops an expired entry
+func (this *TTL[K, V]) expire(key K, entry *ttlEntry[V]) {
+	// Safe to ignore error as the caller just found key
+	this.lru.Remove(key)
+	this.notifyEvict(entry.v)
+}
+
+func (this *TTL[K, V]) Get(key K) (V, error) {
+	entry, err := this.lru.Get(key)
+
+	if err != nil {
+		this.stats.Misses++
+		return this.zeroValue, err
+	}
+
+	if this.isExpired(entry) {
+		this.expire(key, entry)
+		this.stats.Misses++
+		return this.zeroValue, godatacollections.NotFoundError()
+	}
+
+	this.stats.Hits++
+
+	return entry.v, nil
+}
+
+// Contains returns if key is in the cache and not expired without marking it as used
+func (this *TTL[K, V]) Contains(key K) bool {
+	entry, ok := this.lru.items[key]
+
+	if !ok {
+		return false
+	}
+
+	if this.isExpired(entry.v) {
+		this.expire(key, entry.v)
+		return false
+	}
+
+	return true
+}
+
+// Put adds v with the ttl the cache was made with
+func (this *TTL[K, V]) Put(v V) {
+	this.put(v, this.ttl)
+}
+
+// PutWithTTL adds v with its own ttl instead of the one the cache was made with
+func (this *TTL[K, V]) PutWithTTL(v V, ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("unable to put with ttl %v, needs to be more than 0", ttl)
+	}
+
+	this.put(v, ttl)
+
+	return nil
+}
+
+func (this *TTL[K, V]) put(v V, ttl time.Duration) {
+	this.lru.Put(&ttlEntry[V]{v: v, expires: this.clock().Add(ttl)})
+}
+
+func (this *TTL[K, V]) Remove(key K) error {
+	return this.lru.Remove(key)
+}
+
+// RemoveExpired drops every expired item in O(n)
+// Returns how many were dropped
+func (this *TTL[K, V]) RemoveExpired() int {
+	now := this.clock()
+	expired := make([]*ttlEntry[V], 0)
+
+	for _, entry := range this.lru.order.values() {
+		if !now.Before(entry.expires) {
+			expired = append(expired, entry)
+		}
+	}
+
+	for _, entry := range expired {
+		this.expire(this.lru.vToKFunc(entry), entry)
+	}
+
+	return len(expired)
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// fakeClock only moves when told to so tests don't need to sleep
+type fakeClock struct {
+	now time.Time
+}
+
+func (this *fakeClock) Now() time.Time {
+	return this.now
+}
+
+func (this *fakeClock) advance(d time.Duration) {
+	this.now = this.now.Add(d)
+}
+
+func sessionTTL(capacity int, ttl time.Duration) (*TTL[int, *session], *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	ttlCache, _ := NewTTL(capacity, ttl, sessionID, nil, clock.Now)
+
+	return ttlCache, clock
+}
+
+func TestNewTTLErrors(t *testing.T) {
+	if _, err := NewTTL(1, 0, sessionID, nil, nil); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewTTL[int, *session](1, time.Second, nil, nil, nil); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewTTL(0, time.Second, sessionID, nil, nil); err == nil {
+		t.Fail()
+	}
+}
+
+func TestTTLItemsExpire(t *testing.T) {
+	ttlCache, clock := sessionTTL(10, time.Minute)
+	expired := make([]int, 0)
+	ttlCache.OnEvict(func(s *session) { expired = append(expired, s.id) })
+
+	ttlCache.Put(&session{id: 1})
+	clock.advance(30 * time.Second)
+	ttlCache.Put(&session{id: 2})
+
+	if _, err := ttlCache.Get(1); err != nil {
+		t.Fatal("expected 1 to still be live")
+	}
+
+	clock.advance(30 * time.Second)
+
+	if _, err := ttlCache.Get(1); !godatacollections.IsNotFoundError(err) {
+		t.Fatal("expected 1 to have expired at exactly its ttl")
+	}
+
+	if !ttlCache.Contains(2) {
+		t.Fatal("expected 2 to still be live")
+	}
+
+	clock.advance(30 * time.Second)
+
+	if ttlCache.Contains(2) {
+		t.Fatal("expected 2 to have expired")
+	}
+
+	expectIDs(t, expired, 1, 2)
+
+	if stats := ttlCache.Stats(); stats.Hits != 1 || stats.Misses != 1 || stats.Evictions != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestTTLPutRefreshesExpiry(t *testing.T) {
+	ttlCache, clock := sessionTTL(10, time.Minute)
+
+	ttlCache.Put(&session{id: 1})
+	clock.advance(50 * time.Second)
+	ttlCache.Put(&session{id: 1})
+	clock.advance(50 * time.Second)
+
+	if !ttlCache.Contains(1) {
+		t.Fatal("expected put to reset the expiry")
+	}
+}
+
+func TestTTLPutWithTTL(t *testing.T) {
+	ttlCache, clock := sessionTTL(10, time.Minute)
+
+	if ttlCache.PutWithTTL(&session{id: 1}, 0) == nil {
+		t.Fatal("expected error for ttl of 0")
+	}
+
+	ttlCache.PutWithTTL(&session{id: 1}, time.Hour)
+	ttlCache.Put(&session{id: 2})
+	clock.advance(2 * time.Minute)
+
+	if !ttlCache.Contains(1) || ttlCache.Contains(2) {
+		t.Fail()
+	}
+}
+
+func TestTTLRemoveExpired(t *testing.T) {
+	ttlCache, clock := sessionTTL(10, time.Minute)
+
+	for id := 0; id < 5; id++ {
+		ttlCache.Put(&session{id: id})
+	}
+
+	clock.advance(time.Minute)
+	ttlCache.PutWithTTL(&session{id: 5}, time.Hour)
+
+	if removed := ttlCache.RemoveExpired(); removed != 5 {
+		t.Fatalf("expected 5 expired but got %d", removed)
+	}
+
+	if ttlCache.Len() != 1 || !ttlCache.Contains(5) {
+		t.Fail()
+	}
+}
+
+func TestTTLEvictsLeastRecentlyUsedWhenFull(t *testing.T) {
+	ttlCache, _ := sessionTTL(2, time.Minute)
+
+	ttlCache.Put(&session{id: 1})
+	ttlCache.Put(&session{id: 2})
+	ttlCache.Get(1)
+	ttlCache.Put(&session{id: 3})
+
+	if ttlCache.Contains(2) || !ttlCache.Contains(1) || ttlCache.Stats().Evictions != 1 {
+		t.Fail()
+	}
+}
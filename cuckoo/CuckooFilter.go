@@ -0,0 +1,384 @@
+package cuckoo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/ZacharyDuve/godatacollections"
+	"github.com/ZacharyDuve/godatacollections/internal/hashmix"
+)
+
+const wordSize = 64
+
+// maxKicks is how many fingerprints Insert moves around looking for a free slot before the filter is full
+const maxKicks = 500
+
+// CuckooFilter is a set of keys that can say a key is definitely not in it or might be, like a Bloom filter
+// Each key is stored as a small fingerprint in one of two buckets so keys can be deleted as well
+// For false positive rates under about 3% it takes less space than a Bloom filter and far less than a counting one
+// The chance of a false positive is about 2 * bucketSize / 2^fingerprintBits
+// A bucketSize of 4 lets the filter fill to about 95% before Insert fails
+type CuckooFilter[K any] struct {
+	hashFunc        func(K) uint64
+	numBuckets      uint64
+	bucketSize      uint64
+	fingerprintBits uint64
+	// slots holds every fingerprint packed fingerprintBits apart, bucket i is slots i*bucketSize to (i+1)*bucketSize-1
+	// A fingerprint of 0 is an empty slot
+	slots []uint64
+	count uint64
+	// victim is a fingerprint that could not be placed after maxKicks, the filter is full while there is one
+	victim victim
+	// random picks which fingerprint gets kicked out
+	random uint64
+}
+
+type victim struct {
+	used        bool
+	index       uint64
+	fingerprint uint64
+}
+
+// NewCuckooFilter creates a new empty CuckooFilter for capacity keys in buckets of bucketSize fingerprints of 1 to 32 bits
+func NewCuckooFilter[K any](capacity, bucketSize, fingerprintBits uint64, hashFunc func(K) uint64) (*CuckooFilter[K], error) {
+	if hashFunc == nil {
+		return nil, errors.New("unable to create CuckooFilter without a function to hash Keys")
+	}
+
+	if capacity < 1 {
+		return nil, fmt.Errorf("unable to create CuckooFilter with capacity %d, needs to be at least 1", capacity)
+	}
+
+	if bucketSize < 1 || bucketSize > 64 {
+		return nil, fmt.Errorf("unable to create CuckooFilter with bucket size %d, needs to be from 1 to 64", bucketSize)
+	}
+
+	if fingerprintBits < 1 || fingerprintBits > 32 {
+		return nil, fmt.Errorf("unable to create CuckooFilter with %d fingerprint bits, needs to be from 1 to 32", fingerprintBits)
+	}
+
+	// Number of buckets is a power of 2 so that the other bucket of a fingerprint can be found with xor
+	neededBuckets := uint64(math.Ceil(float64(capacity) / float64(bucketSize) / expectedLoad(bucketSize)))
+	numBuckets := uint64(1) << bits.Len64(neededBuckets-1)
+
+	return newCuckooFilter(hashFunc, numBuckets, bucketSize, fingerprintBits), nil
+}
+
+func newCuckooFilter[K any](hashFunc func(K) uint64, numBuckets, bucketSize, fingerprintBits uint64) *CuckooFilter[K] {
+	return &CuckooFilter[K]{
+		hashFunc:        hashFunc,
+		numBuckets:      numBuckets,
+		bucketSize:      bucketSize,
+		fingerprintBits: fingerprintBits,
+		slots:           make([]uint64, slotWords(numBuckets, bucketSize, fingerprintBits)),
+		random:          1,
+	}
+}
+
+// slotWords returns how many words hold every fingerprint
+func slotWords(numBuckets, bucketSize, fingerprintBits uint64) uint64 {
+	return (numBuckets*bucketSize*fingerprintBits + wordSize - 1) / wordSize
+}
+
+// expectedLoad returns about how full a filter with bucketSize can get before Insert starts to fail
+// These are the loads measured in the original cuckoo filter paper
+func expectedLoad(bucketSize uint64) float64 {
+	switch bucketSize {
+	case 1:
+		return 0.5
+	case 2:
+		return 0.84
+	case 3:
+		return 0.9
+	case 4:
+		return 0.95
+	default:
+		return 0.98
+	}
+}
+
+// locate returns the first bucket and the fingerprint for key
+func (this *CuckooFilter[K]) locate(key K) (uint64, uint64) {
+	hash := hashmix.Mix(this.hashFunc(key))
+	fingerprint := (hash >> 32) & (1<<this.fingerprintBits - 1)
+
+	// 0 means an empty slot so it can't be a fingerprint
+	if fingerprint == 0 {
+		fingerprint = 1
+	}
+
+	return hash & (this.numBuckets - 1), fingerprint
+}
+
+// otherBucket returns the other bucket fingerprint can go in, it works from either of the two buckets
+func (this *CuckooFilter[K]) otherBucket(index, fingerprint uint64) uint64 {
+	return (index ^ hashmix.Mix(fingerprint)) & (this.numBuckets - 1)
+}
+
+func (this *CuckooFilter[K]) getSlot(slot uint64) uint64 {
+	position := slot * this.fingerprintBits
+	wordIndex, offset := position/wordSize, position%wordSize
+	value := this.slots[wordIndex] >> offset
+
+	// The fingerprint runs on into the next word
+	if offset+this.fingerprintBits > wordSize {
+		value |= this.slots[wordIndex+1] << (wordSize - offset)
+	}
+
+	return value & (1<<this.fingerprintBits - 1)
+}
+
+func (this *CuckooFilter[K]) setSlot(slot, fingerprint uint64) {
+	position := slot * this.fingerprintBits
+	wordIndex, offset := position/wordSize, position%wordSize
+	mask := uint64(1)<<this.fingerprintBits - 1
+
+	this.slots[wordIndex] = this.slots[wordIndex]&^(mask<<offset) | fingerprint<<offset
+
+	if offset+this.fingerprintBits > wordSize {
+		shift := wordSize - offset
+		this.slots[wordIndex+1] = this.slots[wordIndex+1]&^(mask>>shift) | fingerprint>>shift
+	}
+}
+
+// findInBucket returns the slot holding fingerprint in bucket index
+func (this *CuckooFilter[K]) findInBucket(index, fingerprint uint64) (uint64, bool) {
+	for slot := index * this.bucketSize; slot < (index+1)*this.bucketSize; slot++ {
+		if this.getSlot(slot) == fingerprint {
+			return slot, true
+		}
+	}
+
+	return 0, false
+}
+
+// addToBucket puts fingerprint in a free slot of bucket index if there is one
+func (this *CuckooFilter[K]) addToBucket(index, fingerprint uint64) bool {
+	slot, found := this.findInBucket(index, 0)
+
+	if found {
+		this.setSlot(slot, fingerprint)
+	}
+
+	return found
+}
+
+// nextRandom is xorshift64, good enough to stop kicks from going around in the same loop
+func (this *CuckooFilter[K]) nextRandom() uint64 {
+	this.random ^= this.random << 13
+	this.random ^= this.random >> 7
+	this.random ^= this.random << 17
+
+	return this.random
+}
+
+// Count returns the number of keys in the filter, counting a key inserted twice twice
+func (this *CuckooFilter[K]) Count() uint64 {
+	return this.count
+}
+
+// Cap returns the number of slots for fingerprints, Insert usually starts failing a little before Count reaches it
+func (this *CuckooFilter[K]) Cap() uint64 {
+	return this.numBuckets * this.bucketSize
+}
+
+// Insert adds key to the filter
+// The same key can be inserted more than once, and then needs deleting as many times, but at most 2 * bucketSize times
+// An error is returned once the filter is full. The key that fills it is still stored, later ones are not
+func (this *CuckooFilter[K]) Insert(key K) error {
+	if this.victim.used {
+		return errors.New("unable to insert into CuckooFilter as it is full")
+	}
+
+	index, fingerprint := this.locate(key)
+	other := this.otherBucket(index, fingerprint)
+
+	if this.addToBucket(index, fingerprint) || this.addToBucket(other, fingerprint) {
+		this.count++
+		return nil
+	}
+
+	// Both buckets are full so kick out a fingerprint to its other bucket, and so on until one fits
+	if this.nextRandom()%2 == 0 {
+		index = other
+	}
+
+	for kick := 0; kick < maxKicks; kick++ {
+		slot := index*this.bucketSize + this.nextRandom()%this.bucketSize
+		kicked := this.getSlot(slot)
+		this.setSlot(slot, fingerprint)
+
+		fingerprint = kicked
+		index = this.otherBucket(index, fingerprint)
+
+		if this.addToBucket(index, fingerprint) {
+			this.count++
+			return nil
+		}
+	}
+
+	// Hold on to the last kicked fingerprint rather than lose a key that was already in the filter
+	this.victim = victim{used: true, index: index, fingerprint: fingerprint}
+	this.count++
+
+	return nil
+}
+
+// Lookup returns false if key is definitely not in the filter and true if it probably is
+func (this *CuckooFilter[K]) Lookup(key K) bool {
+	index, fingerprint := this.locate(key)
+	other := this.otherBucket(index, fingerprint)
+
+	if this.victim.used && this.victim.fingerprint == fingerprint && (this.victim.index == index || this.victim.index == other) {
+		return true
+	}
+
+	_, found := this.findInBucket(index, fingerprint)
+
+	if !found {
+		_, found = this.findInBucket(other, fingerprint)
+	}
+
+	return found
+}
+
+// Delete removes one insert of key from the filter
+// Returns NotFoundError if key is definitely not in the filter
+// Only keys that were inserted should be deleted, deleting a key that is only a false positive removes some other key
+func (this *CuckooFilter[K]) Delete(key K) error {
+	index, fingerprint := this.locate(key)
+	other := this.otherBucket(index, fingerprint)
+
+	if this.victim.used && this.victim.fingerprint == fingerprint && (this.victim.index == index || this.victim.index == other) {
+		this.victim = victim{}
+		this.count--
+		return nil
+	}
+
+	for _, bucket := range []uint64{index, other} {
+		if slot, found := this.findInBucket(bucket, fingerprint); found {
+			this.setSlot(slot, 0)
+			this.count--
+			this.placeVictim()
+			return nil
+		}
+	}
+
+	return godatacollections.NotFoundError()
+}
+
+// placeVictim moves the victim into a bucket if a slot has come free
+func (this *CuckooFilter[K]) placeVictim() {
+	if !this.victim.used {
+		return
+	}
+
+	if this.addToBucket(this.victim.index, this.victim.fingerprint) || this.addToBucket(this.otherBucket(this.victim.index, this.victim.fingerprint), this.victim.fingerprint) {
+		this.victim = victim{}
+	}
+}
+
+// Clear removes every key
+func (this *CuckooFilter[K]) Clear() {
+	clear(this.slots)
+	this.count = 0
+	this.victim = victim{}
+}
+
+// MarshalBinary writes the number of buckets, bucket size, fingerprint bits and count as uvarints
+// followed by a byte that is 1 if there is a victim and then its bucket and fingerprint as uvarints
+// and finally each word of packed fingerprints as 8 little endian bytes
+// The hash function is not written so it is up to the reader to use the same one
+func (this *CuckooFilter[K]) MarshalBinary() ([]byte, error) {
+	data := binary.AppendUvarint(nil, this.numBuckets)
+	data = binary.AppendUvarint(data, this.bucketSize)
+	data = binary.AppendUvarint(data, this.fingerprintBits)
+	data = binary.AppendUvarint(data, this.count)
+
+	if this.victim.used {
+		data = append(data, 1)
+		data = binary.AppendUvarint(data, this.victim.index)
+		data = binary.AppendUvarint(data, this.victim.fingerprint)
+	} else {
+		data = append(data, 0)
+	}
+
+	for _, word := range this.slots {
+		data = binary.LittleEndian.AppendUint64(data, word)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary replaces the filter with one written by MarshalBinary, keeping its hash function
+func (this *CuckooFilter[K]) UnmarshalBinary(data []byte) error {
+	var header [4]uint64
+
+	for i := range header {
+		value, n := binary.Uvarint(data)
+
+		if n <= 0 {
+			return errors.New("unable to unmarshal CuckooFilter with bad header")
+		}
+
+		header[i] = value
+		data = data[n:]
+	}
+
+	numBuckets, bucketSize, fingerprintBits, count := header[0], header[1], header[2], header[3]
+
+	if numBuckets == 0 || numBuckets&(numBuckets-1) != 0 || bucketSize < 1 || bucketSize > 64 || fingerprintBits < 1 || fingerprintBits > 32 {
+		return fmt.Errorf("unable to unmarshal CuckooFilter with %d buckets of %d slots of %d bits", numBuckets, bucketSize, fingerprintBits)
+	}
+
+	if len(data) < 1 {
+		return errors.New("unable to unmarshal CuckooFilter with missing victim")
+	}
+
+	readVictim := victim{}
+
+	if data[0] == 1 {
+		index, n := binary.Uvarint(data[1:])
+		if n <= 0 || index >= numBuckets {
+			return errors.New("unable to unmarshal CuckooFilter with bad victim bucket")
+		}
+		data = data[1+n:]
+
+		fingerprint, n := binary.Uvarint(data)
+		if n <= 0 || fingerprint == 0 || fingerprint >= 1<<fingerprintBits {
+			return errors.New("unable to unmarshal CuckooFilter with bad victim fingerprint")
+		}
+		data = data[n:]
+
+		readVictim = victim{used: true, index: index, fingerprint: fingerprint}
+	} else if data[0] == 0 {
+		data = data[1:]
+	} else {
+		return fmt.Errorf("unable to unmarshal CuckooFilter with bad victim flag %d", data[0])
+	}
+
+	// Every bucket takes at least a bit so this also keeps the exact size below from overflowing
+	if numBuckets > uint64(len(data))*8 {
+		return fmt.Errorf("unable to unmarshal CuckooFilter with %d buckets from %d bytes", numBuckets, len(data))
+	}
+
+	// The slots must be exactly what the header says before anything is allocated for them
+	if wordCount := slotWords(numBuckets, bucketSize, fingerprintBits); uint64(len(data)) != 8*wordCount {
+		return fmt.Errorf("unable to unmarshal CuckooFilter expecting %d words but got %d bytes", wordCount, len(data))
+	}
+
+	result := newCuckooFilter(this.hashFunc, numBuckets, bucketSize, fingerprintBits)
+	result.count = count
+	result.victim = readVictim
+
+	for i := range result.slots {
+		result.slots[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+
+	*this = *result
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package cuckoo
+
+import (
+	"encoding"
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+var _ encoding.BinaryMarshaler = &CuckooFilter[int]{}
+var _ encoding.BinaryUnmarshaler = &CuckooFilter[int]{}
+
+func hashInt(i int) uint64 {
+	hash := fnv.New64a()
+	hash.Write(binary.LittleEndian.AppendUint64(nil, uint64(i)))
+
+	return hash.Sum64()
+}
+
+func intFilter(t *testing.T, capacity, bucketSize, fingerprintBits uint64) *CuckooFilter[int] {
+	filter, err := NewCuckooFilter(capacity, bucketSize, fingerprintBits, hashInt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return filter
+}
+
+func TestNewCuckooFilterErrors(t *testing.T) {
+	if _, err := NewCuckooFilter[int](100, 4, 8, nil); err == nil {
+		t.Fail()
+	}
+
+	for _, args := range [][3]uint64{{0, 4, 8}, {100, 0, 8}, {100, 65, 8}, {100, 4, 0}, {100, 4, 33}} {
+		if _, err := NewCuckooFilter(args[0], args[1], args[2], hashInt); err == nil {
+			t.Fatalf("expected error for capacity %d, bucket size %d and %d bits", args[0], args[1], args[2])
+		}
+	}
+}
+
+func TestCuckooFilterSizing(t *testing.T) {
+	filter := intFilter(t, 1000, 4, 8)
+
+	// 1000 / 4 / 0.95 is 264 buckets, rounded up to a power of 2
+	if filter.numBuckets != 512 || filter.Cap() != 2048 {
+		t.Fatalf("expected 512 buckets but got %d", filter.numBuckets)
+	}
+
+	// 2048 slots of 8 bits
+	if len(filter.slots) != 256 {
+		t.Fatalf("expected 256 words but got %d", len(filter.slots))
+	}
+}
+
+func TestCuckooFilterSlotsPackAcrossWords(t *testing.T) {
+	for _, fingerprintBits := range []uint64{1, 7, 13, 32} {
+		filter := intFilter(t, 100, 4, fingerprintBits)
+		mask := uint64(1)<<fingerprintBits - 1
+
+		for slot := uint64(0); slot < filter.Cap(); slot++ {
+			filter.setSlot(slot, (slot*2654435761+1)&mask)
+		}
+
+		for slot := uint64(0); slot < filter.Cap(); slot++ {
+			if filter.getSlot(slot) != (slot*2654435761+1)&mask {
+				t.Fatalf("slot %d with %d bits was corrupted", slot, fingerprintBits)
+			}
+		}
+	}
+}
+
+func TestCuckooFilterInsertLookupDelete(t *testing.T) {
+	filter := intFilter(t, 1000, 4, 16)
+
+	for i := 0; i < 1000; i++ {
+		if err := filter.Insert(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if filter.Count() != 1000 {
+		t.Fatalf("expected 1000 but got %d", filter.Count())
+	}
+
+	for i := 0; i < 1000; i++ {
+		if !filter.Lookup(i) {
+			t.Fatalf("false negative for %d", i)
+		}
+	}
+
+	for i := 0; i < 1000; i += 2 {
+		if err := filter.Delete(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 1; i < 1000; i += 2 {
+		if !filter.Lookup(i) {
+			t.Fatalf("false negative for %d after deleting others", i)
+		}
+	}
+
+	if filter.Count() != 500 {
+		t.Fail()
+	}
+
+	filter.Clear()
+
+	if filter.Count() != 0 || filter.Lookup(1) {
+		t.Fail()
+	}
+
+	if err := filter.Delete(1); !godatacollections.IsNotFoundError(err) {
+		t.Fatal("expected NotFoundError deleting from an empty filter")
+	}
+}
+
+func TestCuckooFilterDuplicates(t *testing.T) {
+	filter := intFilter(t, 100, 2, 16)
+
+	// Two buckets of two slots fit the same key 4 times
+	for i := 0; i < 4; i++ {
+		if err := filter.Insert(7); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		filter.Delete(7)
+
+		if !filter.Lookup(7) {
+			t.Fatal("expected key to stay until deleted as many times as it was inserted")
+		}
+	}
+
+	filter.Delete(7)
+
+	if filter.Lookup(7) || filter.Count() != 0 {
+		t.Fail()
+	}
+}
+
+func TestCuckooFilterFillsUp(t *testing.T) {
+	filter := intFilter(t, 1000, 4, 12)
+	inserted := 0
+
+	for filter.Insert(inserted) == nil {
+		inserted++
+	}
+
+	// Every key up to and including the one that filled the filter is still there
+	for i := 0; i < inserted; i++ {
+		if !filter.Lookup(i) {
+			t.Fatalf("false negative for %d in a full filter", i)
+		}
+	}
+
+	if load := float64(filter.Count()) / float64(filter.Cap()); load < 0.9 {
+		t.Fatalf("expected to fill to at least 90%% but only got to %v", load)
+	}
+
+	// Deleting frees slots until one is in a bucket the victim can go in
+	deleted := 0
+
+	for filter.victim.used {
+		if err := filter.Delete(deleted); err != nil {
+			t.Fatal(err)
+		}
+		deleted++
+	}
+
+	for i := deleted; i < inserted; i++ {
+		if !filter.Lookup(i) {
+			t.Fatalf("false negative for %d after the victim moved", i)
+		}
+	}
+
+	if err := filter.Insert(-1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCuckooFilterFalsePositiveRate(t *testing.T) {
+	for _, args := range [][2]uint64{{4, 8}, {4, 12}, {2, 8}, {8, 16}} {
+		bucketSize, fingerprintBits := args[0], args[1]
+		filter := intFilter(t, 10000, bucketSize, fingerprintBits)
+
+		for i := 0; i < 10000; i++ {
+			filter.Insert(i)
+		}
+
+		falsePositives := 0
+		trials := 200000
+
+		for i := 10000; i < 10000+trials; i++ {
+			if filter.Lookup(i) {
+				falsePositives++
+			}
+		}
+
+		actual := float64(falsePositives) / float64(trials)
+		bound := 2 * float64(bucketSize) / math.Pow(2, float64(fingerprintBits))
+
+		if actual > bound {
+			t.Fatalf("expected false positive rate under %v for bucket size %d and %d bits but got %v", bound, bucketSize, fingerprintBits, actual)
+		}
+	}
+}
+
+func TestCuckooFilterBinaryRoundTrip(t *testing.T) {
+	original := intFilter(t, 100, 4, 7)
+	inserted := 0
+
+	// Fill it so there is a victim to write out
+	for original.Insert(inserted) == nil {
+		inserted++
+	}
+
+	data, err := original.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := intFilter(t, 1, 1, 1)
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Count() != original.Count() || decoded.victim != original.victim {
+		t.Fatal("expected count and victim to be decoded")
+	}
+
+	for i := 0; i < 1000; i++ {
+		if decoded.Lookup(i) != original.Lookup(i) {
+			t.Fatalf("decoded filter disagrees on %d", i)
+		}
+	}
+
+	if decoded.UnmarshalBinary(data[:len(data)-1]) == nil {
+		t.Fatal("expected error for truncated data")
+	}
+
+	if decoded.UnmarshalBinary(nil) == nil {
+		t.Fatal("expected error for empty data")
+	}
+
+	// A header asking for far more slots than the data holds is rejected before anything is allocated
+	oversized := binary.AppendUvarint(nil, 8192)
+	oversized = binary.AppendUvarint(oversized, 64)
+	oversized = binary.AppendUvarint(oversized, 32)
+	oversized = binary.AppendUvarint(oversized, 0)
+	oversized = append(oversized, 0)
+	oversized = append(oversized, make([]byte, 1024)...)
+
+	if decoded.UnmarshalBinary(oversized) == nil {
+		t.Fatal("expected error for a header bigger than the data")
+	}
+
+	if decoded.Count() != original.Count() {
+		t.Fatal("expected failed unmarshal to leave the filter alone")
+	}
+}
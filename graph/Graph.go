@@ -0,0 +1,219 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// Weight is any number that can be used as the weight of an edge
+type Weight interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64
+}
+
+// Edge goes from From to To with a Weight
+// For an undirected graph the edge can be walked either way
+type Edge[K comparable, W Weight] struct {
+	From   K
+	To     K
+	Weight W
+}
+
+// Graph is a directed or undirected graph with weighted edges kept in adjacency lists
+// Vertices are identified by K. Use a K that looks up whatever data the vertex stands for
+// Vertices and each vertex's edges are kept in the order they were added so every walk over the graph is repeatable
+type Graph[K comparable, W Weight] struct {
+	directed bool
+	// vertices, edges and index are kept in step. index maps a vertex to its place in the other two
+	vertices []K
+	index    map[K]int
+	// edges holds each vertex's outgoing edges. An undirected edge is in both of its vertices' lists
+	edges     [][]Edge[K, W]
+	edgeCount int
+}
+
+// NewDirectedGraph creates a new empty graph where edges only go one way
+func NewDirectedGraph[K comparable, W Weight]() *Graph[K, W] {
+	return &Graph[K, W]{directed: true, index: make(map[K]int)}
+}
+
+// NewUndirectedGraph creates a new empty graph where edges go both ways
+func NewUndirectedGraph[K comparable, W Weight]() *Graph[K, W] {
+	return &Graph[K, W]{directed: false, index: make(map[K]int)}
+}
+
+// IsDirected returns if edges only go one way
+func (this *Graph[K, W]) IsDirected() bool {
+	return this.directed
+}
+
+// VertexCount returns the number of vertices
+func (this *Graph[K, W]) VertexCount() int {
+	return len(this.vertices)
+}
+
+// EdgeCount returns the number of edges. An undirected edge counts once
+func (this *Graph[K, W]) EdgeCount() int {
+	return this.edgeCount
+}
+
+// AddVertex adds a vertex with no edges
+func (this *Graph[K, W]) AddVertex(vertex K) error {
+	if _, ok := this.index[vertex]; ok {
+		return fmt.Errorf("unable to add duplicate vertex %v", vertex)
+	}
+
+	this.addVertex(vertex)
+
+	return nil
+}
+
+func (this *Graph[K, W]) addVertex(vertex K) int {
+	vertexIndex := len(this.vertices)
+
+	this.vertices = append(this.vertices, vertex)
+	this.edges = append(this.edges, nil)
+	this.index[vertex] = vertexIndex
+
+	return vertexIndex
+}
+
+// vertexIndex returns where vertex is kept, adding it if it isn't in the graph yet
+func (this *Graph[K, W]) vertexIndex(vertex K) int {
+	if vertexIndex, ok := this.index[vertex]; ok {
+		return vertexIndex
+	}
+
+	return this.addVertex(vertex)
+}
+
+// HasVertex returns if vertex is in the graph
+func (this *Graph[K, W]) HasVertex(vertex K) bool {
+	_, ok := this.index[vertex]
+
+	return ok
+}
+
+// AddEdge adds an edge from from to to, adding either vertex if it isn't in the graph yet
+// Returns an error if the edge already exists, there can only be one edge between the same vertices
+func (this *Graph[K, W]) AddEdge(from, to K, weight W) error {
+	if this.HasEdge(from, to) {
+		return fmt.Errorf("unable to add duplicate edge from %v to %v", from, to)
+	}
+
+	fromIndex := this.vertexIndex(from)
+	toIndex := this.vertexIndex(to)
+
+	this.edges[fromIndex] = append(this.edges[fromIndex], Edge[K, W]{From: from, To: to, Weight: weight})
+
+	if !this.directed && fromIndex != toIndex {
+		this.edges[toIndex] = append(this.edges[toIndex], Edge[K, W]{From: to, To: from, Weight: weight})
+	}
+
+	this.edgeCount++
+
+	return nil
+}
+
+// findEdge returns where the edge to to is in from's edges or -1
+func (this *Graph[K, W]) findEdge(fromIndex int, to K) int {
+	for i, edge := range this.edges[fromIndex] {
+		if edge.To == to {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// HasEdge returns if there is an edge from from to to
+func (this *Graph[K, W]) HasEdge(from, to K) bool {
+	_, err := this.Edge(from, to)
+
+	return err == nil
+}
+
+// Edge returns the edge from from to to
+// Returns NotFoundError if there is no such edge
+func (this *Graph[K, W]) Edge(from, to K) (Edge[K, W], error) {
+	fromIndex, ok := this.index[from]
+
+	if ok {
+		if edgeIndex := this.findEdge(fromIndex, to); edgeIndex != -1 {
+			return this.edges[fromIndex][edgeIndex], nil
+		}
+	}
+
+	return Edge[K, W]{}, godatacollections.NotFoundError()
+}
+
+// RemoveEdge removes the edge from from to to. The vertices stay in the graph
+func (this *Graph[K, W]) RemoveEdge(from, to K) error {
+	fromIndex, ok := this.index[from]
+	edgeIndex := -1
+
+	if ok {
+		edgeIndex = this.findEdge(fromIndex, to)
+	}
+
+	if edgeIndex == -1 {
+		return fmt.Errorf("unable to delete edge from %v to %v due to it not existing in graph", from, to)
+	}
+
+	this.edges[fromIndex] = append(this.edges[fromIndex][:edgeIndex], this.edges[fromIndex][edgeIndex+1:]...)
+
+	if toIndex := this.index[to]; !this.directed && toIndex != fromIndex {
+		backIndex := this.findEdge(toIndex, from)
+		this.edges[toIndex] = append(this.edges[toIndex][:backIndex], this.edges[toIndex][backIndex+1:]...)
+	}
+
+	this.edgeCount--
+
+	return nil
+}
+
+// Vertices returns an iterator over every vertex in the order they were added
+func (this *Graph[K, W]) Vertices() godatacollections.Iterator[K] {
+	var zeroValue K
+
+	return &sliceIterator[K]{values: this.vertices, zeroValue: zeroValue}
+}
+
+// Edges returns an iterator over the edges leaving vertex in the order they were added
+// For an undirected graph that is every edge touching vertex, each with From set to vertex
+// Returns NotFoundError if vertex is not in the graph
+func (this *Graph[K, W]) Edges(vertex K) (godatacollections.Iterator[Edge[K, W]], error) {
+	vertexIndex, ok := this.index[vertex]
+
+	if !ok {
+		return nil, godatacollections.NotFoundError()
+	}
+
+	return &sliceIterator[Edge[K, W]]{values: this.edges[vertexIndex]}, nil
+}
+
+// sliceIterator iterates over a slice that it does not own so the graph must not be changed while iterating
+type sliceIterator[T any] struct {
+	values    []T
+	zeroValue T
+}
+
+func (this *sliceIterator[T]) Close() error {
+	return nil
+}
+
+func (this *sliceIterator[T]) HasNext() bool {
+	return len(this.values) > 0
+}
+
+func (this *sliceIterator[T]) Next() (T, error) {
+	if len(this.values) == 0 {
+		return this.zeroValue, errors.New("nothing left to iterate over")
+	}
+
+	retNext := this.values[0]
+	this.values = this.values[1:]
+
+	return retNext, nil
+}
@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/ZacharyDuve/godatacollections"
+	"github.com/ZacharyDuve/godatacollections/queue"
+)
+
+// ShortestPaths holds the shortest path from a source vertex to every vertex reachable from it
+// It is a snapshot so later changes to the graph have no effect on it
+type ShortestPaths[K comparable, W Weight] struct {
+	source   K
+	distance map[K]W
+	// previous is the vertex before each vertex on its shortest path. The source has no entry
+	previous map[K]K
+}
+
+type dijkstraItem[K comparable, W Weight] struct {
+	vertex   K
+	distance W
+}
+
+// Dijkstra finds the shortest paths from source to every vertex reachable from it in O((V + E) log V)
+// Returns an error if source is not in the graph or any edge has a negative weight
+func (this *Graph[K, W]) Dijkstra(source K) (*ShortestPaths[K, W], error) {
+	if !this.HasVertex(source) {
+		return nil, godatacollections.NotFoundError()
+	}
+
+	for _, vertexEdges := range this.edges {
+		for _, edge := range vertexEdges {
+			if edge.Weight < 0 {
+				return nil, fmt.Errorf("unable to find shortest paths with negative weight edge from %v to %v", edge.From, edge.To)
+			}
+		}
+	}
+
+	toVisit, _ := queue.NewPQueue(func(a, b dijkstraItem[K, W]) int {
+		if a.distance < b.distance {
+			return -1
+		} else if a.distance > b.distance {
+			return 1
+		}
+		return 0
+	}, dijkstraItem[K, W]{})
+
+	var zeroDistance W
+	paths := &ShortestPaths[K, W]{source: source, distance: map[K]W{source: zeroDistance}, previous: make(map[K]K)}
+	done := make(map[K]bool)
+
+	toVisit.Enqueue(dijkstraItem[K, W]{vertex: source, distance: zeroDistance})
+
+	// A vertex is queued again each time a shorter distance is found. Older, longer entries are skipped when they come out
+	for toVisit.Len() > 0 {
+		cur, _ := toVisit.Dequeue()
+
+		if done[cur.vertex] {
+			continue
+		}
+
+		done[cur.vertex] = true
+
+		for _, edge := range this.edges[this.index[cur.vertex]] {
+			newDistance := cur.distance + edge.Weight
+			oldDistance, seen := paths.distance[edge.To]
+
+			if !seen || newDistance < oldDistance {
+				paths.distance[edge.To] = newDistance
+				paths.previous[edge.To] = cur.vertex
+				toVisit.Enqueue(dijkstraItem[K, W]{vertex: edge.To, distance: newDistance})
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+// Source returns the vertex the paths start from
+func (this *ShortestPaths[K, W]) Source() K {
+	return this.source
+}
+
+// Distance returns the total weight of the shortest path to to
+// Returns NotFoundError if to can't be reached from the source
+func (this *ShortestPaths[K, W]) Distance(to K) (W, error) {
+	distance, ok := this.distance[to]
+
+	if !ok {
+		return distance, godatacollections.NotFoundError()
+	}
+
+	return distance, nil
+}
+
+// PathTo returns the vertices on the shortest path from the source to to, including both ends
+// Returns NotFoundError if to can't be reached from the source
+func (this *ShortestPaths[K, W]) PathTo(to K) ([]K, error) {
+	if _, ok := this.distance[to]; !ok {
+		return nil, godatacollections.NotFoundError()
+	}
+
+	path := []K{to}
+
+	for cur := to; cur != this.source; {
+		cur = this.previous[cur]
+		path = append(path, cur)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, nil
+}
@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+func TestGraphDijkstra(t *testing.T) {
+	g := NewDirectedGraph[string, float64]()
+
+	g.AddEdge("s", "t", 10)
+	g.AddEdge("s", "y", 5)
+	g.AddEdge("t", "x", 1)
+	g.AddEdge("t", "y", 2)
+	g.AddEdge("y", "t", 3)
+	g.AddEdge("y", "x", 9)
+	g.AddEdge("y", "z", 2)
+	g.AddEdge("x", "z", 4)
+	g.AddEdge("z", "x", 6)
+	g.AddEdge("z", "s", 7)
+	g.AddVertex("unreachable")
+
+	paths, err := g.Dijkstra("s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDistances := map[string]float64{"s": 0, "t": 8, "x": 9, "y": 5, "z": 7}
+
+	for vertex, expected := range expectedDistances {
+		if distance, err := paths.Distance(vertex); err != nil || distance != expected {
+			t.Fatalf("expected distance %v to %v but got %v %v", expected, vertex, distance, err)
+		}
+	}
+
+	path, _ := paths.PathTo("x")
+	expectStrings(t, path, "s", "y", "t", "x")
+
+	path, _ = paths.PathTo("s")
+	expectStrings(t, path, "s")
+
+	if _, err := paths.Distance("unreachable"); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+
+	if _, err := paths.PathTo("unreachable"); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+
+	if paths.Source() != "s" {
+		t.Fail()
+	}
+}
+
+func TestGraphDijkstraErrors(t *testing.T) {
+	g := NewUndirectedGraph[string, int]()
+	g.AddEdge("a", "b", -1)
+
+	if _, err := g.Dijkstra("a"); err == nil {
+		t.Fatal("expected negative weight error")
+	}
+
+	if _, err := g.Dijkstra("x"); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+}
+
+func TestGraphDijkstraAgainstBellmanFord(t *testing.T) {
+	const n = 40
+	g := NewUndirectedGraph[int, int]()
+
+	for i := 0; i < n; i++ {
+		g.AddVertex(i)
+	}
+
+	for i := 0; i < 150; i++ {
+		g.AddEdge(rand.Intn(n), rand.Intn(n), rand.Intn(20))
+	}
+
+	paths, _ := g.Dijkstra(0)
+
+	// Bellman-Ford is slow but simple enough to trust
+	const unreached = 1 << 30
+	expected := make([]int, n)
+	for i := range expected {
+		expected[i] = unreached
+	}
+	expected[0] = 0
+
+	for round := 0; round < n; round++ {
+		for from := 0; from < n; from++ {
+			edges, _ := g.Edges(from)
+			for edges.HasNext() {
+				edge, _ := edges.Next()
+				if expected[from] != unreached && expected[from]+edge.Weight < expected[edge.To] {
+					expected[edge.To] = expected[from] + edge.Weight
+				}
+			}
+		}
+	}
+
+	for vertex := 0; vertex < n; vertex++ {
+		distance, err := paths.Distance(vertex)
+
+		if expected[vertex] == unreached {
+			if err == nil {
+				t.Fatalf("did not expect to reach %d", vertex)
+			}
+			continue
+		}
+
+		if distance != expected[vertex] {
+			t.Fatalf("expected distance %d to %d but got %d", expected[vertex], vertex, distance)
+		}
+
+		// Walking the path should add up to the distance
+		path, _ := paths.PathTo(vertex)
+		total := 0
+		for i := 1; i < len(path); i++ {
+			edge, err := g.Edge(path[i-1], path[i])
+			if err != nil {
+				t.Fatalf("path to %d uses missing edge", vertex)
+			}
+			total += edge.Weight
+		}
+
+		if total != distance {
+			t.Fatalf("path to %d adds up to %d not %d", vertex, total, distance)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/ZacharyDuve/godatacollections/queue"
+	"github.com/ZacharyDuve/godatacollections/unionfind"
+)
+
+// TopologicalSort returns every vertex ordered so that each edge goes from an earlier vertex to a later one
+// Uses Kahn's algorithm, ties go to the vertex that was added first
+// Returns an error if the graph is undirected or has a cycle, since then there is no such order
+func (this *Graph[K, W]) TopologicalSort() ([]K, error) {
+	if !this.directed {
+		return nil, errors.New("unable to topologically sort an undirected graph")
+	}
+
+	inDegree := make([]int, len(this.vertices))
+
+	for _, vertexEdges := range this.edges {
+		for _, edge := range vertexEdges {
+			inDegree[this.index[edge.To]]++
+		}
+	}
+
+	ready := queue.NewLQueue(-1)
+
+	for vertexIndex, degree := range inDegree {
+		if degree == 0 {
+			ready.Enqueue(vertexIndex)
+		}
+	}
+
+	sorted := make([]K, 0, len(this.vertices))
+
+	for {
+		vertexIndex, err := ready.Dequeue()
+		if err != nil {
+			break
+		}
+
+		sorted = append(sorted, this.vertices[vertexIndex])
+
+		for _, edge := range this.edges[vertexIndex] {
+			toIndex := this.index[edge.To]
+			inDegree[toIndex]--
+
+			if inDegree[toIndex] == 0 {
+				ready.Enqueue(toIndex)
+			}
+		}
+	}
+
+	// Vertices on a cycle never get down to 0 incoming edges so they never make it into sorted
+	if len(sorted) != len(this.vertices) {
+		return nil, errors.New("unable to topologically sort a graph that has a cycle")
+	}
+
+	return sorted, nil
+}
+
+// HasCycle returns if the graph has a cycle
+// For an undirected graph a single edge is not a cycle, but a self loop is
+func (this *Graph[K, W]) HasCycle() bool {
+	if this.directed {
+		_, err := this.TopologicalSort()
+		return err != nil
+	}
+
+	// An undirected edge joining two vertices that are already connected closes a cycle
+	components := unionfind.NewUnionFind[int]()
+
+	for vertexIndex := range this.vertices {
+		// Safe to ignore error as every index is new
+		components.MakeSet(vertexIndex)
+	}
+
+	for fromIndex, vertexEdges := range this.edges {
+		for _, edge := range vertexEdges {
+			toIndex := this.index[edge.To]
+
+			// Each undirected edge is in both lists, only look at it from the lower index
+			if toIndex < fromIndex {
+				continue
+			}
+
+			if joined, _ := components.Union(fromIndex, toIndex); !joined {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ConnectedComponents returns the vertices split into groups that are connected to each other
+// For a directed graph edge direction is ignored, giving the weakly connected components
+// Components are ordered by their first vertex and vertices within a component are in the order they were added
+func (this *Graph[K, W]) ConnectedComponents() [][]K {
+	components := unionfind.NewUnionFind[int]()
+
+	for vertexIndex := range this.vertices {
+		// Safe to ignore error as every index is new
+		components.MakeSet(vertexIndex)
+	}
+
+	for fromIndex, vertexEdges := range this.edges {
+		for _, edge := range vertexEdges {
+			// Safe to ignore error as both indexes were added above
+			components.Union(fromIndex, this.index[edge.To])
+		}
+	}
+
+	// Group by root, numbering groups in order of the first vertex seen for each
+	groupOf := make(map[int]int)
+	groups := make([][]K, 0, components.Components())
+
+	for vertexIndex, vertex := range this.vertices {
+		root, _ := components.Find(vertexIndex)
+		group, ok := groupOf[root]
+
+		if !ok {
+			group = len(groups)
+			groupOf[root] = group
+			groups = append(groups, nil)
+		}
+
+		groups[group] = append(groups[group], vertex)
+	}
+
+	return groups
+}
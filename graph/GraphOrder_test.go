@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestGraphTopologicalSort(t *testing.T) {
+	g := NewDirectedGraph[string, int]()
+
+	g.AddEdge("shirt", "tie", 1)
+	g.AddEdge("tie", "jacket", 1)
+	g.AddEdge("pants", "shoes", 1)
+	g.AddEdge("pants", "belt", 1)
+	g.AddEdge("belt", "jacket", 1)
+	g.AddEdge("shirt", "belt", 1)
+	g.AddVertex("watch")
+
+	sorted, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectStrings(t, sorted, "shirt", "pants", "watch", "tie", "shoes", "belt", "jacket")
+
+	position := make(map[string]int)
+	for i, v := range sorted {
+		position[v] = i
+	}
+
+	for _, from := range sorted {
+		edges, _ := g.Edges(from)
+		for edges.HasNext() {
+			edge, _ := edges.Next()
+			if position[edge.From] >= position[edge.To] {
+				t.Fatalf("%v comes after %v", edge.From, edge.To)
+			}
+		}
+	}
+
+	if g.HasCycle() {
+		t.Fail()
+	}
+}
+
+func TestGraphTopologicalSortDetectsCycle(t *testing.T) {
+	g := NewDirectedGraph[string, int]()
+
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "c", 1)
+	g.AddEdge("c", "a", 1)
+	g.AddEdge("c", "d", 1)
+
+	if _, err := g.TopologicalSort(); err == nil {
+		t.Fatal("expected cycle error")
+	}
+
+	if !g.HasCycle() {
+		t.Fail()
+	}
+}
+
+func TestGraphTopologicalSortUndirectedErrors(t *testing.T) {
+	g := NewUndirectedGraph[string, int]()
+	g.AddEdge("a", "b", 1)
+
+	if _, err := g.TopologicalSort(); err == nil {
+		t.Fail()
+	}
+}
+
+func TestGraphUndirectedHasCycle(t *testing.T) {
+	g := NewUndirectedGraph[string, int]()
+
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "c", 1)
+
+	if g.HasCycle() {
+		t.Fatal("a path is not a cycle")
+	}
+
+	g.AddEdge("c", "a", 1)
+
+	if !g.HasCycle() {
+		t.Fatal("expected a triangle to be a cycle")
+	}
+
+	loop := NewUndirectedGraph[string, int]()
+	loop.AddEdge("a", "a", 1)
+
+	if !loop.HasCycle() {
+		t.Fatal("expected a self loop to be a cycle")
+	}
+}
+
+func TestGraphConnectedComponents(t *testing.T) {
+	g := NewDirectedGraph[string, int]()
+
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("c", "d", 1)
+	g.AddEdge("e", "c", 1)
+	g.AddVertex("f")
+	g.AddEdge("b", "g", 1)
+
+	components := g.ConnectedComponents()
+
+	if len(components) != 3 {
+		t.Fatalf("expected 3 components but got %v", components)
+	}
+
+	expectStrings(t, components[0], "a", "b", "g")
+	expectStrings(t, components[1], "c", "d", "e")
+	expectStrings(t, components[2], "f")
+}
@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/ZacharyDuve/godatacollections"
+	"github.com/ZacharyDuve/godatacollections/queue"
+	"github.com/ZacharyDuve/godatacollections/stack"
+)
+
+// BFS returns an iterator over every vertex reachable from start in breadth first order, starting with start
+// Vertices are found as the iterator is used. The graph must not be changed while iterating
+// Returns NotFoundError if start is not in the graph
+func (this *Graph[K, W]) BFS(start K) (godatacollections.Iterator[K], error) {
+	startIndex, ok := this.index[start]
+
+	if !ok {
+		return nil, godatacollections.NotFoundError()
+	}
+
+	iter := &bfsIterator[K, W]{graph: this, toVisit: queue.NewLQueue(-1), seen: make([]bool, len(this.vertices))}
+	iter.seen[startIndex] = true
+	iter.toVisit.Enqueue(startIndex)
+	iter.remaining = 1
+
+	return iter, nil
+}
+
+type bfsIterator[K comparable, W Weight] struct {
+	graph   *Graph[K, W]
+	toVisit *queue.LQueue[int]
+	// remaining is how many vertices are in toVisit as LQueue doesn't track its length
+	remaining int
+	// seen marks vertices that have been queued so that none is queued twice
+	seen []bool
+}
+
+func (this *bfsIterator[K, W]) Close() error {
+	return nil
+}
+
+func (this *bfsIterator[K, W]) HasNext() bool {
+	return this.remaining > 0
+}
+
+func (this *bfsIterator[K, W]) Next() (K, error) {
+	vertexIndex, err := this.toVisit.Dequeue()
+
+	if err != nil {
+		var zeroValue K
+		return zeroValue, errors.New("nothing left to iterate over")
+	}
+
+	this.remaining--
+
+	for _, edge := range this.graph.edges[vertexIndex] {
+		toIndex := this.graph.index[edge.To]
+
+		if !this.seen[toIndex] {
+			this.seen[toIndex] = true
+			this.toVisit.Enqueue(toIndex)
+			this.remaining++
+		}
+	}
+
+	return this.graph.vertices[vertexIndex], nil
+}
+
+// DFS returns an iterator over every vertex reachable from start in depth first pre-order, starting with start
+// Edges are followed in the order they were added
+// Vertices are found as the iterator is used. The graph must not be changed while iterating
+// Returns NotFoundError if start is not in the graph
+func (this *Graph[K, W]) DFS(start K) (godatacollections.Iterator[K], error) {
+	startIndex, ok := this.index[start]
+
+	if !ok {
+		return nil, godatacollections.NotFoundError()
+	}
+
+	iter := &dfsIterator[K, W]{graph: this, toVisit: stack.NewLStack(-1), visited: make([]bool, len(this.vertices))}
+	iter.toVisit.Push(startIndex)
+	iter.prepNext()
+
+	return iter, nil
+}
+
+type dfsIterator[K comparable, W Weight] struct {
+	graph   *Graph[K, W]
+	toVisit *stack.LStack[int]
+	visited []bool
+	// next is -1 when there is nothing left
+	next int
+}
+
+// prepNext pops until it finds a vertex that hasn't been visited
+// A vertex can be pushed more than once before it is visited, which is what keeps the order depth first
+func (this *dfsIterator[K, W]) prepNext() {
+	for {
+		// Safe to ignore error as -1 is the zero value so we can tell when we have reached the end
+		vertexIndex, _ := this.toVisit.Pop()
+
+		if vertexIndex == -1 || !this.visited[vertexIndex] {
+			this.next = vertexIndex
+			return
+		}
+	}
+}
+
+func (this *dfsIterator[K, W]) Close() error {
+	return nil
+}
+
+func (this *dfsIterator[K, W]) HasNext() bool {
+	return this.next != -1
+}
+
+func (this *dfsIterator[K, W]) Next() (K, error) {
+	if this.next == -1 {
+		var zeroValue K
+		return zeroValue, errors.New("nothing left to iterate over")
+	}
+
+	vertexIndex := this.next
+	this.visited[vertexIndex] = true
+
+	// Push in reverse so that the first edge is the first one followed
+	vertexEdges := this.graph.edges[vertexIndex]
+	for i := len(vertexEdges) - 1; i >= 0; i-- {
+		toIndex := this.graph.index[vertexEdges[i].To]
+
+		if !this.visited[toIndex] {
+			this.toVisit.Push(toIndex)
+		}
+	}
+
+	this.prepNext()
+
+	return this.graph.vertices[vertexIndex], nil
+}
@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// treeGraph is
+//
+//	a -> b -> d
+//	a -> c -> e
+//	b -> e
+//	f (not reachable from a)
+func treeGraph() *Graph[string, int] {
+	g := NewDirectedGraph[string, int]()
+
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("a", "c", 1)
+	g.AddEdge("b", "d", 1)
+	g.AddEdge("b", "e", 1)
+	g.AddEdge("c", "e", 1)
+	g.AddVertex("f")
+
+	return g
+}
+
+func TestGraphBFS(t *testing.T) {
+	iter, err := treeGraph().BFS("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectStrings(t, iterStrings(iter), "a", "b", "c", "d", "e")
+
+	if _, err := iter.Next(); err == nil {
+		t.Fail()
+	}
+}
+
+func TestGraphDFS(t *testing.T) {
+	iter, err := treeGraph().DFS("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectStrings(t, iterStrings(iter), "a", "b", "d", "e", "c")
+
+	if _, err := iter.Next(); err == nil {
+		t.Fail()
+	}
+}
+
+func TestGraphTraversalHandlesCycles(t *testing.T) {
+	g := NewUndirectedGraph[string, int]()
+
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "c", 1)
+	g.AddEdge("c", "a", 1)
+
+	bfs, _ := g.BFS("b")
+	expectStrings(t, iterStrings(bfs), "b", "a", "c")
+
+	dfs, _ := g.DFS("b")
+	expectStrings(t, iterStrings(dfs), "b", "a", "c")
+}
+
+func TestGraphTraversalUnknownStart(t *testing.T) {
+	g := treeGraph()
+
+	if _, err := g.BFS("x"); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+
+	if _, err := g.DFS("x"); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+}
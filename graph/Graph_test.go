@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+func iterStrings(iter godatacollections.Iterator[string]) []string {
+	values := make([]string, 0)
+
+	for iter.HasNext() {
+		cur, _ := iter.Next()
+		values = append(values, cur)
+	}
+
+	return values
+}
+
+func expectStrings(t *testing.T, actual []string, expected ...string) {
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected %v but got %v", expected, actual)
+	}
+}
+
+func TestGraphAddVertexDuplicateErrors(t *testing.T) {
+	g := NewDirectedGraph[string, int]()
+
+	if g.AddVertex("a") != nil || g.AddVertex("a") == nil {
+		t.Fail()
+	}
+
+	if g.VertexCount() != 1 || !g.HasVertex("a") || g.HasVertex("b") {
+		t.Fail()
+	}
+}
+
+func TestGraphAddEdgeAddsVertices(t *testing.T) {
+	g := NewDirectedGraph[string, int]()
+
+	if err := g.AddEdge("a", "b", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.AddEdge("a", "b", 4) == nil {
+		t.Fatal("expected duplicate edge error")
+	}
+
+	expectStrings(t, iterStrings(g.Vertices()), "a", "b")
+
+	if edge, err := g.Edge("a", "b"); err != nil || edge.Weight != 3 {
+		t.Fail()
+	}
+
+	if g.HasEdge("b", "a") || g.EdgeCount() != 1 {
+		t.Fatal("directed edge should only go one way")
+	}
+}
+
+func TestGraphUndirectedEdgesGoBothWays(t *testing.T) {
+	g := NewUndirectedGraph[string, int]()
+
+	g.AddEdge("a", "b", 3)
+	g.AddEdge("c", "a", 1)
+
+	if !g.HasEdge("b", "a") || !g.HasEdge("a", "c") || g.EdgeCount() != 2 || g.IsDirected() {
+		t.Fail()
+	}
+
+	if g.AddEdge("b", "a", 5) == nil {
+		t.Fatal("expected duplicate edge error for the reverse direction")
+	}
+
+	edges, _ := g.Edges("a")
+	tos := make([]string, 0)
+
+	for edges.HasNext() {
+		edge, _ := edges.Next()
+		if edge.From != "a" {
+			t.Fatalf("expected edges from a but got one from %v", edge.From)
+		}
+		tos = append(tos, edge.To)
+	}
+
+	expectStrings(t, tos, "b", "c")
+
+	if err := g.RemoveEdge("b", "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.HasEdge("a", "b") || g.EdgeCount() != 1 {
+		t.Fatal("expected both directions to be removed")
+	}
+}
+
+func TestGraphRemoveEdge(t *testing.T) {
+	g := NewDirectedGraph[string, int]()
+
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("a", "c", 1)
+
+	if g.RemoveEdge("b", "a") == nil || g.RemoveEdge("x", "a") == nil {
+		t.Fatal("expected error removing missing edge")
+	}
+
+	if err := g.RemoveEdge("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.HasEdge("a", "b") || !g.HasEdge("a", "c") || !g.HasVertex("b") || g.EdgeCount() != 1 {
+		t.Fail()
+	}
+}
+
+func TestGraphSelfLoop(t *testing.T) {
+	g := NewUndirectedGraph[string, int]()
+
+	g.AddEdge("a", "a", 1)
+
+	edges, _ := g.Edges("a")
+	count := 0
+	for edges.HasNext() {
+		edges.Next()
+		count++
+	}
+
+	if count != 1 || g.EdgeCount() != 1 {
+		t.Fatal("expected self loop to be stored once")
+	}
+
+	if err := g.RemoveEdge("a", "a"); err != nil || g.EdgeCount() != 0 {
+		t.Fail()
+	}
+}
+
+func TestGraphEdgesUnknownVertex(t *testing.T) {
+	g := NewDirectedGraph[string, int]()
+
+	if _, err := g.Edges("a"); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+
+	if _, err := g.Edge("a", "b"); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+}
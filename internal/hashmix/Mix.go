@@ -0,0 +1,13 @@
+package hashmix
+
+// Mix scrambles a user supplied hash so that even a weak one, like returning an int as is, spreads keys over every bit
+// It is the finalizer from splitmix64, which maps every uint64 to a different one
+func Mix(hash uint64) uint64 {
+	hash ^= hash >> 30
+	hash *= 0xbf58476d1ce4e5b9
+	hash ^= hash >> 27
+	hash *= 0x94d049bb133111eb
+	hash ^= hash >> 31
+
+	return hash
+}
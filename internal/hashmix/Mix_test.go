@@ -0,0 +1,35 @@
+package hashmix
+
+import (
+	"math/bits"
+	"testing"
+)
+
+func TestMixSpreadsSequentialHashes(t *testing.T) {
+	seen := make(map[uint64]bool)
+	// How often each output bit is set over sequential inputs, which only differ in their low bits
+	bitCounts := make([]int, 64)
+
+	for hash := uint64(0); hash < 10000; hash++ {
+		mixed := Mix(hash)
+
+		if seen[mixed] {
+			t.Fatalf("%d mixed to a value already seen", hash)
+		}
+		seen[mixed] = true
+
+		for bit := 0; bit < 64; bit++ {
+			bitCounts[bit] += int(mixed >> bit & 1)
+		}
+	}
+
+	for bit, count := range bitCounts {
+		if count < 4500 || count > 5500 {
+			t.Fatalf("bit %d was set %d times out of 10000", bit, count)
+		}
+	}
+
+	if bits.OnesCount64(Mix(1)^Mix(2)) < 16 {
+		t.Fatal("expected neighbouring hashes to differ in many bits")
+	}
+}
@@ -1,6 +1,9 @@
 package queue
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+)
 
 type LQueue[T any] struct {
 	tZeroValue T
@@ -38,5 +41,39 @@ func (this *LQueue[T]) Dequeue() (T, error) {
 
 	this.head = this.head.next
 
+	if this.head == nil {
+		// Queue is now empty so the tail can't point at the removed node anymore
+		this.tail = nil
+	}
+
 	return retT, nil
 }
+
+// MarshalJSON writes the queue as a JSON array with the front of the queue first
+func (this *LQueue[T]) MarshalJSON() ([]byte, error) {
+	ts := make([]T, 0)
+
+	for curNode := this.head; curNode != nil; curNode = curNode.next {
+		ts = append(ts, curNode.t)
+	}
+
+	return json.Marshal(ts)
+}
+
+// UnmarshalJSON replaces the contents of the queue with a JSON array that has the front of the queue first
+func (this *LQueue[T]) UnmarshalJSON(data []byte) error {
+	var ts []T
+
+	if err := json.Unmarshal(data, &ts); err != nil {
+		return err
+	}
+
+	this.head = nil
+	this.tail = nil
+
+	for _, curT := range ts {
+		this.Enqueue(curT)
+	}
+
+	return nil
+}
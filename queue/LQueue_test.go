@@ -1,6 +1,7 @@
 package queue
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/ZacharyDuve/godatacollections"
@@ -61,6 +62,26 @@ func TestThatDequeueRemovesItemFromQueue(t *testing.T) {
 	}
 }
 
+func TestThatDequeueOfLastItemClearsTail(t *testing.T) {
+	q := NewLQueue(0)
+
+	q.Enqueue(1)
+	q.Enqueue(2)
+	q.Dequeue()
+	q.Dequeue()
+
+	// A tail left behind would keep the last item from being garbage collected
+	if q.head != nil || q.tail != nil {
+		t.Fatal("expected empty queue to let go of its nodes")
+	}
+
+	q.Enqueue(3)
+
+	if q.head != q.tail || q.head.t != 3 {
+		t.Fatal("expected queue to work again after being emptied")
+	}
+}
+
 func TestThatDequeueWithQueueNotEmptyReturnsNoError(t *testing.T) {
 	q := NewLQueue(0)
 
@@ -104,3 +125,52 @@ func TestQueueMaintainsFiFoOrderingForItems(t *testing.T) {
 		i++
 	}
 }
+
+func TestLQueueJSONRoundTripKeepsFIFOOrder(t *testing.T) {
+	q := NewLQueue(-1)
+	q.Enqueue(1)
+	q.Enqueue(2)
+	q.Enqueue(3)
+
+	data, err := json.Marshal(q)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "[1,2,3]" {
+		t.Fatalf("expected front of queue first but got %s", data)
+	}
+
+	decoded := NewLQueue(-1)
+	// Existing items should be replaced
+	decoded.Enqueue(100)
+
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []int{1, 2, 3} {
+		if v, _ := decoded.Dequeue(); v != expected {
+			t.Fatalf("expected %d but got %d", expected, v)
+		}
+	}
+
+	if _, err := decoded.Dequeue(); err == nil {
+		t.Fail()
+	}
+
+	// Should still work as a queue after unmarshal
+	decoded.Enqueue(4)
+	if v, _ := decoded.Dequeue(); v != 4 {
+		t.Fail()
+	}
+}
+
+func TestLQueueUnmarshalInvalidJSONReturnsError(t *testing.T) {
+	q := NewLQueue(0)
+
+	if err := json.Unmarshal([]byte(`"nope"`), q); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,103 @@
+package queue
+
+import (
+	"errors"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// PQueue is a priority queue backed by a binary heap
+// Dequeue always returns the smallest item according to tCompFunc
+// Items that compare equal come out in no particular order
+type PQueue[T any] struct {
+	tCompFunc  func(T, T) int
+	tZeroValue T
+	heap       []T
+}
+
+// NewPQueue creates a new empty PQueue
+// tCompFunc compares two T the same way kCompFunc does for tree.NewBST
+// To get the largest item first flip the sign of tCompFunc
+func NewPQueue[T any](tCompFunc func(T, T) int, tZeroValue T) (*PQueue[T], error) {
+	if tCompFunc == nil {
+		return nil, errors.New("unable to create PQueue without a function to compare T")
+	}
+
+	return &PQueue[T]{tCompFunc: tCompFunc, tZeroValue: tZeroValue}, nil
+}
+
+// Len returns the number of items in the queue
+func (this *PQueue[T]) Len() int {
+	return len(this.heap)
+}
+
+// Enqueue adds t in O(log n)
+func (this *PQueue[T]) Enqueue(t T) {
+	this.heap = append(this.heap, t)
+
+	// Sift the new item up until its parent is no bigger
+	child := len(this.heap) - 1
+
+	for child > 0 {
+		parent := (child - 1) / 2
+
+		if this.tCompFunc(this.heap[child], this.heap[parent]) >= 0 {
+			break
+		}
+
+		this.heap[child], this.heap[parent] = this.heap[parent], this.heap[child]
+		child = parent
+	}
+}
+
+// Peek returns the smallest item without removing it
+// Returns zero value and EmptyError if the queue is empty
+func (this *PQueue[T]) Peek() (T, error) {
+	if len(this.heap) == 0 {
+		return this.tZeroValue, godatacollections.EmptyError()
+	}
+
+	return this.heap[0], nil
+}
+
+// Dequeue removes and returns the smallest item in O(log n)
+// Returns zero value and EmptyError if the queue is empty
+func (this *PQueue[T]) Dequeue() (T, error) {
+	if len(this.heap) == 0 {
+		return this.tZeroValue, godatacollections.EmptyError()
+	}
+
+	retT := this.heap[0]
+	last := len(this.heap) - 1
+
+	this.heap[0] = this.heap[last]
+	// Clear the old slot so the heap doesn't hold on to what it pointed at
+	this.heap[last] = this.tZeroValue
+	this.heap = this.heap[:last]
+
+	// Sift the moved item down until both children are no smaller
+	parent := 0
+
+	for {
+		smallest := parent
+		left := 2*parent + 1
+		right := left + 1
+
+		if left < len(this.heap) && this.tCompFunc(this.heap[left], this.heap[smallest]) < 0 {
+			smallest = left
+		}
+
+		if right < len(this.heap) && this.tCompFunc(this.heap[right], this.heap[smallest]) < 0 {
+			smallest = right
+		}
+
+		if smallest == parent {
+			break
+		}
+
+		this.heap[parent], this.heap[smallest] = this.heap[smallest], this.heap[parent]
+		parent = smallest
+	}
+
+	return retT, nil
+}
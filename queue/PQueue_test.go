@@ -0,0 +1,87 @@
+package queue
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+func compInts(a, b int) int {
+	return a - b
+}
+
+func TestPQueueImplementsQueue(t *testing.T) {
+	var _ godatacollections.Queue[int] = &PQueue[int]{}
+}
+
+func TestNewPQueueWithNilFuncErrors(t *testing.T) {
+	if _, err := NewPQueue[int](nil, 0); err == nil {
+		t.Fail()
+	}
+}
+
+func TestPQueueEmptyErrors(t *testing.T) {
+	q, _ := NewPQueue(compInts, -1)
+
+	if v, err := q.Dequeue(); v != -1 || !godatacollections.IsEmptyError(err) {
+		t.Fail()
+	}
+
+	if v, err := q.Peek(); v != -1 || !godatacollections.IsEmptyError(err) {
+		t.Fail()
+	}
+}
+
+func TestPQueueDequeuesInOrder(t *testing.T) {
+	q, _ := NewPQueue(compInts, -1)
+	values := make([]int, 0)
+
+	for i := 0; i < 500; i++ {
+		v := rand.Intn(100)
+		values = append(values, v)
+		q.Enqueue(v)
+	}
+
+	sort.Ints(values)
+
+	if q.Len() != len(values) {
+		t.Fatalf("expected %d items but got %d", len(values), q.Len())
+	}
+
+	for _, expected := range values {
+		if peeked, _ := q.Peek(); peeked != expected {
+			t.Fatalf("expected to peek %d but got %d", expected, peeked)
+		}
+
+		if v, _ := q.Dequeue(); v != expected {
+			t.Fatalf("expected %d but got %d", expected, v)
+		}
+	}
+
+	if q.Len() != 0 {
+		t.Fail()
+	}
+}
+
+func TestPQueueInterleavedEnqueueDequeue(t *testing.T) {
+	q, _ := NewPQueue(func(a, b int) int { return b - a }, -1)
+
+	q.Enqueue(3)
+	q.Enqueue(9)
+	q.Enqueue(1)
+
+	if v, _ := q.Dequeue(); v != 9 {
+		t.Fatalf("expected largest first but got %d", v)
+	}
+
+	q.Enqueue(5)
+	q.Enqueue(2)
+
+	for _, expected := range []int{5, 3, 2, 1} {
+		if v, _ := q.Dequeue(); v != expected {
+			t.Fatalf("expected %d but got %d", expected, v)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package rangequery
+
+import "errors"
+
+// FenwickTree (binary indexed tree) answers prefix combinations in O(log n) with O(log n) point updates
+// It uses less memory than a SegmentTree but can only add to values, not replace them
+// combine must be associative and commutative with identity the same as for NewSegmentTree
+// If an inverse is given then any range can be queried and values can be set, otherwise only prefixes
+type FenwickTree[T any] struct {
+	combine  func(T, T) T
+	inverse  func(T) T
+	identity T
+	// nodes is 1 indexed. nodes[i] holds the combination of the i&-i values ending at i-1
+	nodes []T
+}
+
+// NewFenwickTree creates a FenwickTree over values in O(n) that can only answer prefix queries
+func NewFenwickTree[T any](values []T, combine func(T, T) T, identity T) (*FenwickTree[T], error) {
+	if combine == nil {
+		return nil, errors.New("unable to create FenwickTree without a function to combine values")
+	}
+
+	nodes := make([]T, len(values)+1)
+	nodes[0] = identity
+	copy(nodes[1:], values)
+
+	for i := 1; i < len(nodes); i++ {
+		if parent := i + (i & -i); parent < len(nodes) {
+			nodes[parent] = combine(nodes[parent], nodes[i])
+		}
+	}
+
+	return &FenwickTree[T]{combine: combine, identity: identity, nodes: nodes}, nil
+}
+
+// NewInvertibleFenwickTree creates a FenwickTree over values in O(n) that can answer any range query
+// inverse must undo combine so that combine(t, inverse(t)) == identity. For sum that is negation
+func NewInvertibleFenwickTree[T any](values []T, combine func(T, T) T, inverse func(T) T, identity T) (*FenwickTree[T], error) {
+	if inverse == nil {
+		return nil, errors.New("unable to create invertible FenwickTree without a function to invert values")
+	}
+
+	tree, err := NewFenwickTree(values, combine, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	tree.inverse = inverse
+
+	return tree, nil
+}
+
+// Len returns the number of values
+func (this *FenwickTree[T]) Len() int {
+	return len(this.nodes) - 1
+}
+
+// Add combines delta into the value at index
+func (this *FenwickTree[T]) Add(index int, delta T) error {
+	if err := checkIndex(index, this.Len()); err != nil {
+		return err
+	}
+
+	for i := index + 1; i < len(this.nodes); i += i & -i {
+		this.nodes[i] = this.combine(this.nodes[i], delta)
+	}
+
+	return nil
+}
+
+// Prefix returns the values in [0, right) combined
+func (this *FenwickTree[T]) Prefix(right int) (T, error) {
+	if err := checkRange(0, right, this.Len()); err != nil {
+		return this.identity, err
+	}
+
+	result := this.identity
+
+	for i := right; i > 0; i -= i & -i {
+		result = this.combine(result, this.nodes[i])
+	}
+
+	return result, nil
+}
+
+// Query returns the values in [left, right) combined
+// Returns an error if left is not 0 and the tree was made without an inverse
+func (this *FenwickTree[T]) Query(left, right int) (T, error) {
+	if err := checkRange(left, right, this.Len()); err != nil {
+		return this.identity, err
+	}
+
+	if left == 0 {
+		return this.Prefix(right)
+	}
+
+	if this.inverse == nil {
+		return this.identity, errors.New("unable to query range not starting at 0 without an inverse function")
+	}
+
+	// Safe to ignore errors as the range has already been checked
+	rightPrefix, _ := this.Prefix(right)
+	leftPrefix, _ := this.Prefix(left)
+
+	return this.combine(rightPrefix, this.inverse(leftPrefix)), nil
+}
+
+// Get returns the value at index
+// Returns an error if index is not 0 and the tree was made without an inverse
+func (this *FenwickTree[T]) Get(index int) (T, error) {
+	if err := checkIndex(index, this.Len()); err != nil {
+		return this.identity, err
+	}
+
+	return this.Query(index, index+1)
+}
+
+// Set replaces the value at index
+// Returns an error if the tree was made without an inverse
+func (this *FenwickTree[T]) Set(index int, value T) error {
+	if this.inverse == nil {
+		return errors.New("unable to set value without an inverse function")
+	}
+
+	current, err := this.Get(index)
+	if err != nil {
+		return err
+	}
+
+	return this.Add(index, this.combine(value, this.inverse(current)))
+}
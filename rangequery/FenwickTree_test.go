@@ -0,0 +1,110 @@
+package rangequery
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func negateInt(a int) int {
+	return -a
+}
+
+func maxInts(a, b int) int {
+	return max(a, b)
+}
+
+func TestNewFenwickTreeWithNilFuncsErrors(t *testing.T) {
+	if _, err := NewFenwickTree[int](nil, nil, 0); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewInvertibleFenwickTree[int](nil, addInts, nil, 0); err == nil {
+		t.Fail()
+	}
+}
+
+func TestFenwickTreeSumAgainstBruteForce(t *testing.T) {
+	for _, n := range []int{1, 6, 16, 29} {
+		values := randomInts(n)
+		tree, _ := NewInvertibleFenwickTree(values, addInts, negateInt, 0)
+
+		for i := 0; i < 500; i++ {
+			left := rand.Intn(n + 1)
+			right := left + rand.Intn(n-left+1)
+
+			switch rand.Intn(3) {
+			case 0:
+				if left < n {
+					delta := rand.Intn(20) - 10
+					tree.Add(left, delta)
+					values[left] += delta
+				}
+			case 1:
+				if left < n {
+					newValue := rand.Intn(100)
+					if err := tree.Set(left, newValue); err != nil {
+						t.Fatal(err)
+					}
+					values[left] = newValue
+				}
+			default:
+				if sum, _ := tree.Query(left, right); sum != bruteForce(values, left, right, addInts, 0) {
+					t.Fatalf("wrong sum for [%d, %d) of %v", left, right, values)
+				}
+			}
+		}
+
+		for i := range values {
+			if got, _ := tree.Get(i); got != values[i] {
+				t.Fatalf("expected %d at %d but got %d", values[i], i, got)
+			}
+		}
+	}
+}
+
+func TestFenwickTreeWithoutInverseOnlyPrefixes(t *testing.T) {
+	values := []int{3, 1, 4, 1, 5, 9, 2, 6}
+	tree, _ := NewFenwickTree(values, maxInts, math.MinInt)
+
+	tree.Add(3, 7)
+	values[3] = max(values[3], 7)
+
+	for right := 0; right <= len(values); right++ {
+		if got, _ := tree.Prefix(right); got != bruteForce(values, 0, right, maxInts, math.MinInt) {
+			t.Fatalf("wrong max for prefix %d", right)
+		}
+	}
+
+	if _, err := tree.Query(0, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tree.Query(1, 4); err == nil {
+		t.Fatal("expected error querying range without inverse")
+	}
+
+	if tree.Set(0, 1) == nil {
+		t.Fatal("expected error setting without inverse")
+	}
+}
+
+func TestFenwickTreeOutOfRangeErrors(t *testing.T) {
+	tree, _ := NewInvertibleFenwickTree([]int{1, 2, 3}, addInts, negateInt, 0)
+
+	if tree.Add(3, 1) == nil {
+		t.Fail()
+	}
+
+	if _, err := tree.Prefix(4); err == nil {
+		t.Fail()
+	}
+
+	if _, err := tree.Query(2, 1); err == nil {
+		t.Fail()
+	}
+
+	if tree.Set(-1, 1) == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,205 @@
+package rangequery
+
+import "errors"
+
+// LazySegmentTree is a SegmentTree that can also apply an update to every value in a range in O(log n)
+// Updates are pushed down the tree only when a query or later update needs to look below them
+//
+// U is the type of update, such as an amount to add or a value to assign
+// apply returns t after applying u to each of the length values that t is the combination of
+//
+//	For sum with add that is t + u*length, for min or max with add it is t + u
+//
+// compose returns the single update that has the same effect as applying older and then newer
+//
+//	For add that is older + newer, for assign it is newer
+type LazySegmentTree[T, U any] struct {
+	combine  func(T, T) T
+	identity T
+	apply    func(t T, u U, length int) T
+	compose  func(older, newer U) U
+	n        int
+	// nodes is a recursive layout with the root at 1 and children of i at 2i and 2i+1
+	nodes []T
+	// pending holds updates that have been applied to nodes[i] but not yet to its children
+	pending    []U
+	hasPending []bool
+}
+
+// NewLazySegmentTree creates a LazySegmentTree over values in O(n)
+// combine and identity are the same as for NewSegmentTree
+func NewLazySegmentTree[T, U any](values []T, combine func(T, T) T, identity T, apply func(t T, u U, length int) T, compose func(older, newer U) U) (*LazySegmentTree[T, U], error) {
+	if combine == nil {
+		return nil, errors.New("unable to create LazySegmentTree without a function to combine values")
+	}
+
+	if apply == nil {
+		return nil, errors.New("unable to create LazySegmentTree without a function to apply updates")
+	}
+
+	if compose == nil {
+		return nil, errors.New("unable to create LazySegmentTree without a function to compose updates")
+	}
+
+	n := len(values)
+	tree := &LazySegmentTree[T, U]{combine: combine, identity: identity, apply: apply, compose: compose, n: n,
+		nodes: make([]T, 4*n), pending: make([]U, 4*n), hasPending: make([]bool, 4*n)}
+
+	if n > 0 {
+		tree.build(values, 1, 0, n)
+	}
+
+	return tree, nil
+}
+
+func (this *LazySegmentTree[T, U]) build(values []T, node, left, right int) {
+	if right-left == 1 {
+		this.nodes[node] = values[left]
+		return
+	}
+
+	mid := (left + right) / 2
+	this.build(values, 2*node, left, mid)
+	this.build(values, 2*node+1, mid, right)
+	this.nodes[node] = this.combine(this.nodes[2*node], this.nodes[2*node+1])
+}
+
+// Len returns the number of values
+func (this *LazySegmentTree[T, U]) Len() int {
+	return this.n
+}
+
+// applyTo applies u to node which covers length values and remembers it for the node's children
+func (this *LazySegmentTree[T, U]) applyTo(node, length int, u U) {
+	this.nodes[node] = this.apply(this.nodes[node], u, length)
+
+	if length > 1 {
+		if this.hasPending[node] {
+			this.pending[node] = this.compose(this.pending[node], u)
+		} else {
+			this.pending[node] = u
+			this.hasPending[node] = true
+		}
+	}
+}
+
+// pushDown hands node's pending update to its children
+func (this *LazySegmentTree[T, U]) pushDown(node, left, right int) {
+	if !this.hasPending[node] {
+		return
+	}
+
+	mid := (left + right) / 2
+	this.applyTo(2*node, mid-left, this.pending[node])
+	this.applyTo(2*node+1, right-mid, this.pending[node])
+
+	var zeroU U
+	this.pending[node] = zeroU
+	this.hasPending[node] = false
+}
+
+// Get returns the value at index
+func (this *LazySegmentTree[T, U]) Get(index int) (T, error) {
+	if err := checkIndex(index, this.n); err != nil {
+		return this.identity, err
+	}
+
+	return this.Query(index, index+1)
+}
+
+// Set replaces the value at index
+func (this *LazySegmentTree[T, U]) Set(index int, value T) error {
+	if err := checkIndex(index, this.n); err != nil {
+		return err
+	}
+
+	this.set(1, 0, this.n, index, value)
+
+	return nil
+}
+
+func (this *LazySegmentTree[T, U]) set(node, left, right, index int, value T) {
+	if right-left == 1 {
+		this.nodes[node] = value
+		return
+	}
+
+	this.pushDown(node, left, right)
+
+	mid := (left + right) / 2
+	if index < mid {
+		this.set(2*node, left, mid, index, value)
+	} else {
+		this.set(2*node+1, mid, right, index, value)
+	}
+
+	this.nodes[node] = this.combine(this.nodes[2*node], this.nodes[2*node+1])
+}
+
+// Update applies u to every value in [left, right)
+func (this *LazySegmentTree[T, U]) Update(left, right int, u U) error {
+	if err := checkRange(left, right, this.n); err != nil {
+		return err
+	}
+
+	if left < right {
+		this.update(1, 0, this.n, left, right, u)
+	}
+
+	return nil
+}
+
+func (this *LazySegmentTree[T, U]) update(node, nodeLeft, nodeRight, left, right int, u U) {
+	if left <= nodeLeft && nodeRight <= right {
+		this.applyTo(node, nodeRight-nodeLeft, u)
+		return
+	}
+
+	this.pushDown(node, nodeLeft, nodeRight)
+
+	mid := (nodeLeft + nodeRight) / 2
+	if left < mid {
+		this.update(2*node, nodeLeft, mid, left, right, u)
+	}
+
+	if right > mid {
+		this.update(2*node+1, mid, nodeRight, left, right, u)
+	}
+
+	this.nodes[node] = this.combine(this.nodes[2*node], this.nodes[2*node+1])
+}
+
+// Query returns all values in [left, right) combined in order
+// An empty range returns identity
+func (this *LazySegmentTree[T, U]) Query(left, right int) (T, error) {
+	if err := checkRange(left, right, this.n); err != nil {
+		return this.identity, err
+	}
+
+	if left == right {
+		return this.identity, nil
+	}
+
+	return this.query(1, 0, this.n, left, right), nil
+}
+
+func (this *LazySegmentTree[T, U]) query(node, nodeLeft, nodeRight, left, right int) T {
+	if left <= nodeLeft && nodeRight <= right {
+		return this.nodes[node]
+	}
+
+	this.pushDown(node, nodeLeft, nodeRight)
+
+	mid := (nodeLeft + nodeRight) / 2
+	result := this.identity
+
+	if left < mid {
+		result = this.combine(result, this.query(2*node, nodeLeft, mid, left, right))
+	}
+
+	if right > mid {
+		result = this.combine(result, this.query(2*node+1, mid, nodeRight, left, right))
+	}
+
+	return result
+}
@@ -0,0 +1,119 @@
+package rangequery
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func addSum(t, u, length int) int {
+	return t + u*length
+}
+
+func addMin(t, u, length int) int {
+	return t + u
+}
+
+func assignSum(t int, u *int, length int) int {
+	return *u * length
+}
+
+func TestNewLazySegmentTreeWithNilFuncsErrors(t *testing.T) {
+	if _, err := NewLazySegmentTree[int, int](nil, nil, 0, addSum, addInts); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewLazySegmentTree[int, int](nil, addInts, 0, nil, addInts); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewLazySegmentTree[int, int](nil, addInts, 0, addSum, nil); err == nil {
+		t.Fail()
+	}
+}
+
+func TestLazySegmentTreeRangeAddAgainstBruteForce(t *testing.T) {
+	for _, n := range []int{1, 5, 16, 37} {
+		values := randomInts(n)
+		sumTree, _ := NewLazySegmentTree(values, addInts, 0, addSum, addInts)
+		minTree, _ := NewLazySegmentTree(values, minInts, math.MaxInt, addMin, addInts)
+
+		for i := 0; i < 500; i++ {
+			left := rand.Intn(n + 1)
+			right := left + rand.Intn(n-left+1)
+
+			switch rand.Intn(3) {
+			case 0:
+				delta := rand.Intn(20) - 10
+				sumTree.Update(left, right, delta)
+				minTree.Update(left, right, delta)
+
+				for j := left; j < right; j++ {
+					values[j] += delta
+				}
+			case 1:
+				if left < n {
+					newValue := rand.Intn(100)
+					sumTree.Set(left, newValue)
+					minTree.Set(left, newValue)
+					values[left] = newValue
+				}
+			default:
+				if sum, _ := sumTree.Query(left, right); sum != bruteForce(values, left, right, addInts, 0) {
+					t.Fatalf("wrong sum for [%d, %d) of %v", left, right, values)
+				}
+
+				if minVal, _ := minTree.Query(left, right); minVal != bruteForce(values, left, right, minInts, math.MaxInt) {
+					t.Fatalf("wrong min for [%d, %d) of %v", left, right, values)
+				}
+			}
+		}
+
+		for i := range values {
+			if got, _ := sumTree.Get(i); got != values[i] {
+				t.Fatalf("expected %d at %d but got %d", values[i], i, got)
+			}
+		}
+	}
+}
+
+func TestLazySegmentTreeRangeAssign(t *testing.T) {
+	values := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	tree, _ := NewLazySegmentTree(values, addInts, 0, assignSum, func(older, newer *int) *int { return newer })
+
+	five, two := 5, 2
+	tree.Update(0, 6, &five)
+	tree.Update(2, 4, &two)
+
+	expected := []int{5, 5, 2, 2, 5, 5, 7, 8}
+
+	for i := range expected {
+		if got, _ := tree.Get(i); got != expected[i] {
+			t.Fatalf("expected %v but got %d at %d", expected, got, i)
+		}
+	}
+
+	if sum, _ := tree.Query(0, 8); sum != 39 {
+		t.Fatalf("expected 39 but got %d", sum)
+	}
+}
+
+func TestLazySegmentTreeOutOfRangeErrors(t *testing.T) {
+	tree, _ := NewLazySegmentTree([]int{1, 2, 3}, addInts, 0, addSum, addInts)
+
+	if tree.Update(1, 4, 1) == nil {
+		t.Fail()
+	}
+
+	if _, err := tree.Query(3, 2); err == nil {
+		t.Fail()
+	}
+
+	if _, err := tree.Get(-1); err == nil {
+		t.Fail()
+	}
+
+	if tree.Set(3, 0) == nil {
+		t.Fail()
+	}
+}
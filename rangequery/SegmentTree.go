@@ -0,0 +1,108 @@
+package rangequery
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SegmentTree answers combine(values[l], ..., values[r-1]) for any range in O(log n) with O(log n) point updates
+// combine must be associative and identity must satisfy combine(identity, t) == combine(t, identity) == t
+// For example sum with 0, min with the largest value, or max with the smallest value
+// combine does not need to be commutative, values are always combined left to right
+type SegmentTree[T any] struct {
+	combine  func(T, T) T
+	identity T
+	n        int
+	// nodes holds the leaves at [n, 2n) and each parent i at combine(nodes[2i], nodes[2i+1])
+	nodes []T
+}
+
+// NewSegmentTree creates a SegmentTree over a copy of values in O(n)
+func NewSegmentTree[T any](values []T, combine func(T, T) T, identity T) (*SegmentTree[T], error) {
+	if combine == nil {
+		return nil, errors.New("unable to create SegmentTree without a function to combine values")
+	}
+
+	n := len(values)
+	nodes := make([]T, 2*n)
+	copy(nodes[n:], values)
+
+	for i := n - 1; i > 0; i-- {
+		nodes[i] = combine(nodes[2*i], nodes[2*i+1])
+	}
+
+	return &SegmentTree[T]{combine: combine, identity: identity, n: n, nodes: nodes}, nil
+}
+
+// Len returns the number of values
+func (this *SegmentTree[T]) Len() int {
+	return this.n
+}
+
+// Get returns the value at index
+func (this *SegmentTree[T]) Get(index int) (T, error) {
+	if err := checkIndex(index, this.n); err != nil {
+		return this.identity, err
+	}
+
+	return this.nodes[this.n+index], nil
+}
+
+// Set replaces the value at index
+func (this *SegmentTree[T]) Set(index int, value T) error {
+	if err := checkIndex(index, this.n); err != nil {
+		return err
+	}
+
+	i := this.n + index
+	this.nodes[i] = value
+
+	for i > 1 {
+		i /= 2
+		this.nodes[i] = this.combine(this.nodes[2*i], this.nodes[2*i+1])
+	}
+
+	return nil
+}
+
+// Query returns all values in [left, right) combined in order
+// An empty range returns identity
+func (this *SegmentTree[T]) Query(left, right int) (T, error) {
+	if err := checkRange(left, right, this.n); err != nil {
+		return this.identity, err
+	}
+
+	// Values from the left and right edges have to be kept apart so they can be combined in order
+	leftResult := this.identity
+	rightResult := this.identity
+
+	for l, r := left+this.n, right+this.n; l < r; l, r = l/2, r/2 {
+		if l%2 == 1 {
+			leftResult = this.combine(leftResult, this.nodes[l])
+			l++
+		}
+
+		if r%2 == 1 {
+			r--
+			rightResult = this.combine(this.nodes[r], rightResult)
+		}
+	}
+
+	return this.combine(leftResult, rightResult), nil
+}
+
+func checkIndex(index, n int) error {
+	if index < 0 || index >= n {
+		return fmt.Errorf("index %d out of range for length %d", index, n)
+	}
+
+	return nil
+}
+
+func checkRange(left, right, n int) error {
+	if left < 0 || right > n || left > right {
+		return fmt.Errorf("range [%d, %d) out of range for length %d", left, right, n)
+	}
+
+	return nil
+}
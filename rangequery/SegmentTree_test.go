@@ -0,0 +1,120 @@
+package rangequery
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func addInts(a, b int) int {
+	return a + b
+}
+
+func minInts(a, b int) int {
+	return min(a, b)
+}
+
+func randomInts(n int) []int {
+	values := make([]int, n)
+
+	for i := range values {
+		values[i] = rand.Intn(200) - 100
+	}
+
+	return values
+}
+
+func bruteForce[T any](values []T, left, right int, combine func(T, T) T, identity T) T {
+	result := identity
+
+	for i := left; i < right; i++ {
+		result = combine(result, values[i])
+	}
+
+	return result
+}
+
+func TestNewSegmentTreeWithNilCombineErrors(t *testing.T) {
+	if _, err := NewSegmentTree[int](nil, nil, 0); err == nil {
+		t.Fail()
+	}
+}
+
+func TestSegmentTreeSumAndMin(t *testing.T) {
+	for _, n := range []int{1, 2, 7, 16, 33} {
+		values := randomInts(n)
+		sumTree, _ := NewSegmentTree(values, addInts, 0)
+		minTree, _ := NewSegmentTree(values, minInts, math.MaxInt)
+
+		for left := 0; left <= n; left++ {
+			for right := left; right <= n; right++ {
+				if sum, _ := sumTree.Query(left, right); sum != bruteForce(values, left, right, addInts, 0) {
+					t.Fatalf("wrong sum for [%d, %d) of %v", left, right, values)
+				}
+
+				if minVal, _ := minTree.Query(left, right); minVal != bruteForce(values, left, right, minInts, math.MaxInt) {
+					t.Fatalf("wrong min for [%d, %d) of %v", left, right, values)
+				}
+			}
+		}
+	}
+}
+
+func TestSegmentTreeSetKeepsCombineOrder(t *testing.T) {
+	// String concatenation isn't commutative so any mix up in order shows
+	concat := func(a, b string) string { return a + b }
+	values := []string{"a", "b", "c", "d", "e", "f", "g"}
+	tree, _ := NewSegmentTree(values, concat, "")
+
+	for i := 0; i < 50; i++ {
+		index := rand.Intn(len(values))
+		values[index] = string(rune('a' + rand.Intn(26)))
+
+		if err := tree.Set(index, values[index]); err != nil {
+			t.Fatal(err)
+		}
+
+		left := rand.Intn(len(values) + 1)
+		right := left + rand.Intn(len(values)-left+1)
+
+		if result, _ := tree.Query(left, right); result != bruteForce(values, left, right, concat, "") {
+			t.Fatalf("expected %q but got %q", bruteForce(values, left, right, concat, ""), result)
+		}
+
+		if got, _ := tree.Get(index); got != values[index] {
+			t.Fatalf("expected %q at %d but got %q", values[index], index, got)
+		}
+	}
+}
+
+func TestSegmentTreeOutOfRangeErrors(t *testing.T) {
+	tree, _ := NewSegmentTree([]int{1, 2, 3}, addInts, 0)
+
+	if _, err := tree.Query(-1, 2); err == nil {
+		t.Fail()
+	}
+
+	if _, err := tree.Query(0, 4); err == nil {
+		t.Fail()
+	}
+
+	if _, err := tree.Query(2, 1); err == nil {
+		t.Fail()
+	}
+
+	if _, err := tree.Get(3); err == nil {
+		t.Fail()
+	}
+
+	if tree.Set(-1, 0) == nil {
+		t.Fail()
+	}
+}
+
+func TestSegmentTreeEmpty(t *testing.T) {
+	tree, _ := NewSegmentTree([]int{}, addInts, 0)
+
+	if result, err := tree.Query(0, 0); err != nil || result != 0 || tree.Len() != 0 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,321 @@
+package setops
+
+import (
+	"errors"
+
+	"github.com/ZacharyDuve/godatacollections"
+	"github.com/ZacharyDuve/godatacollections/tree"
+)
+
+// SetOps preforms set algebra (Union, Intersection, ...) on two godatacollections.Set values
+// Since a Set has no way of turning a T into a K or making a new Set, SetOps holds onto those functions
+type SetOps[K, T any] struct {
+	tToKFunc   func(T) K
+	newSetFunc func() (godatacollections.Set[K, T], error)
+	// kCompFunc is only set for ops made with NewOrderedSetOps
+	kCompFunc  func(K, K) int
+	tZeroValue T
+}
+
+// NewSetOps creates SetOps that work on any two Sets
+// tToKFunc returns a value of type K from type T
+// newSetFunc creates a new empty Set that the results are inserted into
+func NewSetOps[K, T any](tToKFunc func(T) K, newSetFunc func() (godatacollections.Set[K, T], error)) (*SetOps[K, T], error) {
+	if tToKFunc == nil {
+		return nil, errors.New("unable to create SetOps without a function to convert T to a Key")
+	}
+
+	if newSetFunc == nil {
+		return nil, errors.New("unable to create SetOps without a function to create new Sets")
+	}
+
+	return &SetOps[K, T]{tToKFunc: tToKFunc, newSetFunc: newSetFunc}, nil
+}
+
+// NewOrderedSetOps creates SetOps whose results are balanced tree.BSTs ordered by kCompFunc
+// When both Sets passed in are tree.BSTs the ops walk both trees in order at the same time,
+// which is O(n + m) instead of a Contains lookup for every item
+//
+//	Both trees MUST be ordered by the same kCompFunc that is passed in here
+//
+// Sets that are not tree.BSTs are still supported but use the slower lookup path
+func NewOrderedSetOps[K, T any](kCompFunc func(K, K) int, tToKFunc func(T) K, tZeroValue T) (*SetOps[K, T], error) {
+	if kCompFunc == nil {
+		return nil, errors.New("unable to create SetOps without a function to compare Keys")
+	}
+
+	newSetFunc := func() (godatacollections.Set[K, T], error) {
+		return tree.NewBST(kCompFunc, tToKFunc, tZeroValue)
+	}
+
+	ops, err := NewSetOps(tToKFunc, newSetFunc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ops.kCompFunc = kCompFunc
+	ops.tZeroValue = tZeroValue
+
+	return ops, nil
+}
+
+// Union returns a new Set with every item that is in a or b
+// When both Sets have an item for the same key the item from a is kept
+func (this *SetOps[K, T]) Union(a, b godatacollections.Set[K, T]) (godatacollections.Set[K, T], error) {
+	if this.canMerge(a, b) {
+		return this.mergeInto(a, b, func(inA, inB bool) bool { return true })
+	}
+
+	result, err := this.newSetFunc()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = this.insertWhere(result, a, func(K) bool { return true }); err != nil {
+		return nil, err
+	}
+
+	if err = this.insertWhere(result, b, func(k K) bool { return !a.Contains(k) }); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Intersection returns a new Set with every item from a that has a key that is also in b
+func (this *SetOps[K, T]) Intersection(a, b godatacollections.Set[K, T]) (godatacollections.Set[K, T], error) {
+	if this.canMerge(a, b) {
+		return this.mergeInto(a, b, func(inA, inB bool) bool { return inA && inB })
+	}
+
+	result, err := this.newSetFunc()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = this.insertWhere(result, a, b.Contains); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Difference returns a new Set with every item from a that has a key that is not in b
+func (this *SetOps[K, T]) Difference(a, b godatacollections.Set[K, T]) (godatacollections.Set[K, T], error) {
+	if this.canMerge(a, b) {
+		return this.mergeInto(a, b, func(inA, inB bool) bool { return inA && !inB })
+	}
+
+	result, err := this.newSetFunc()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = this.insertWhere(result, a, func(k K) bool { return !b.Contains(k) }); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SymmetricDifference returns a new Set with every item that is only in one of a or b
+func (this *SetOps[K, T]) SymmetricDifference(a, b godatacollections.Set[K, T]) (godatacollections.Set[K, T], error) {
+	if this.canMerge(a, b) {
+		return this.mergeInto(a, b, func(inA, inB bool) bool { return inA != inB })
+	}
+
+	result, err := this.newSetFunc()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = this.insertWhere(result, a, func(k K) bool { return !b.Contains(k) }); err != nil {
+		return nil, err
+	}
+
+	if err = this.insertWhere(result, b, func(k K) bool { return !a.Contains(k) }); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// IsSubset returns if every key in a is also in b
+func (this *SetOps[K, T]) IsSubset(a, b godatacollections.Set[K, T]) bool {
+	if this.canMerge(a, b) {
+		isSubset := true
+		this.mergeWalk(a, b, func(aT, bT T, inA, inB bool) bool {
+			if inA && !inB {
+				isSubset = false
+			}
+			// Can stop as soon as we know the answer
+			return isSubset
+		})
+		return isSubset
+	}
+
+	return this.allIn(a, b)
+}
+
+// IsSuperset returns if every key in b is also in a
+func (this *SetOps[K, T]) IsSuperset(a, b godatacollections.Set[K, T]) bool {
+	return this.IsSubset(b, a)
+}
+
+// Equal returns if a and b have exactly the same keys
+// Only the keys are compared, the items themselves are not
+func (this *SetOps[K, T]) Equal(a, b godatacollections.Set[K, T]) bool {
+	if this.canMerge(a, b) {
+		isEqual := true
+		this.mergeWalk(a, b, func(aT, bT T, inA, inB bool) bool {
+			if inA != inB {
+				isEqual = false
+			}
+			return isEqual
+		})
+		return isEqual
+	}
+
+	return this.allIn(a, b) && this.allIn(b, a)
+}
+
+// allIn returns if every key in from is also in in
+func (this *SetOps[K, T]) allIn(from, in godatacollections.Set[K, T]) bool {
+	iter := from.Iterator()
+	defer iter.Close()
+
+	for iter.HasNext() {
+		curT, err := iter.Next()
+
+		if err != nil || !in.Contains(this.tToKFunc(curT)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// insertWhere inserts every item from into result that has a key that shouldInsert returns true for
+func (this *SetOps[K, T]) insertWhere(result, from godatacollections.Set[K, T], shouldInsert func(K) bool) error {
+	iter := from.Iterator()
+	defer iter.Close()
+
+	for iter.HasNext() {
+		curT, err := iter.Next()
+
+		if err != nil {
+			return err
+		}
+
+		if shouldInsert(this.tToKFunc(curT)) {
+			if err = result.Insert(curT); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// canMerge returns if we are able to walk both sets in order at the same time
+func (this *SetOps[K, T]) canMerge(a, b godatacollections.Set[K, T]) bool {
+	if this.kCompFunc == nil {
+		return false
+	}
+
+	_, aIsBST := a.(*tree.BST[K, T])
+	_, bIsBST := b.(*tree.BST[K, T])
+
+	return aIsBST && bIsBST
+}
+
+// mergeInto walks both sets in order and builds a balanced BST of every item that keep returns true for
+// If an item is in both sets then the item from a is the one that is used
+func (this *SetOps[K, T]) mergeInto(a, b godatacollections.Set[K, T], keep func(inA, inB bool) bool) (godatacollections.Set[K, T], error) {
+	sortedTs := make([]T, 0)
+
+	err := this.mergeWalk(a, b, func(aT, bT T, inA, inB bool) bool {
+		if keep(inA, inB) {
+			if inA {
+				sortedTs = append(sortedTs, aT)
+			} else {
+				sortedTs = append(sortedTs, bT)
+			}
+		}
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tree.NewBSTFromSorted(this.kCompFunc, this.tToKFunc, this.tZeroValue, sortedTs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// mergeWalk walks both sets in key order at the same time calling visit once per key
+// inA and inB tell which sets have an item for the key, aT and bT are only valid when in that set
+// Walk stops early if visit returns false
+func (this *SetOps[K, T]) mergeWalk(a, b godatacollections.Set[K, T], visit func(aT, bT T, inA, inB bool) bool) error {
+	aIter := a.Iterator()
+	defer aIter.Close()
+	bIter := b.Iterator()
+	defer bIter.Close()
+
+	var aT, bT T
+	var err error
+	hasA, hasB := aIter.HasNext(), bIter.HasNext()
+
+	if hasA {
+		if aT, err = aIter.Next(); err != nil {
+			return err
+		}
+	}
+	if hasB {
+		if bT, err = bIter.Next(); err != nil {
+			return err
+		}
+	}
+
+	for hasA || hasB {
+		inA, inB := hasA, hasB
+
+		if hasA && hasB {
+			curComp := this.kCompFunc(this.tToKFunc(aT), this.tToKFunc(bT))
+			// Only the smaller key gets visited this round. Equal keys are visited together
+			inA = curComp <= 0
+			inB = curComp >= 0
+		}
+
+		if !visit(aT, bT, inA, inB) {
+			return nil
+		}
+
+		if inA {
+			if hasA = aIter.HasNext(); hasA {
+				if aT, err = aIter.Next(); err != nil {
+					return err
+				}
+			}
+		}
+		if inB {
+			if hasB = bIter.HasNext(); hasB {
+				if bT, err = bIter.Next(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
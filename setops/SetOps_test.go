@@ -0,0 +1,273 @@
+package setops
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+	"github.com/ZacharyDuve/godatacollections/tree"
+)
+
+type permission struct {
+	id   int
+	name string
+}
+
+func permissionID(p permission) int {
+	return p.id
+}
+
+func compInts(a, b int) int {
+	return a - b
+}
+
+// mapSet is a Set that isn't a tree.BST so that the non merge paths get tested
+type mapSet struct {
+	items map[int]permission
+}
+
+func newMapSet() (godatacollections.Set[int, permission], error) {
+	return &mapSet{items: make(map[int]permission)}, nil
+}
+
+func (this *mapSet) Insert(p permission) error {
+	if _, ok := this.items[p.id]; ok {
+		return errors.New("duplicate")
+	}
+	this.items[p.id] = p
+	return nil
+}
+
+func (this *mapSet) Contains(id int) bool {
+	_, ok := this.items[id]
+	return ok
+}
+
+func (this *mapSet) GetByKey(id int) permission {
+	return this.items[id]
+}
+
+func (this *mapSet) Remove(id int) error {
+	if _, ok := this.items[id]; !ok {
+		return errors.New("missing")
+	}
+	delete(this.items, id)
+	return nil
+}
+
+func (this *mapSet) Iterator() godatacollections.Iterator[permission] {
+	ps := make([]permission, 0, len(this.items))
+	for _, p := range this.items {
+		ps = append(ps, p)
+	}
+	return &sliceIterator{ps: ps}
+}
+
+type sliceIterator struct {
+	ps []permission
+}
+
+func (this *sliceIterator) Close() error {
+	return nil
+}
+
+func (this *sliceIterator) HasNext() bool {
+	return len(this.ps) > 0
+}
+
+func (this *sliceIterator) Next() (permission, error) {
+	if len(this.ps) == 0 {
+		return permission{}, errors.New("nothing left to iterate over")
+	}
+	p := this.ps[0]
+	this.ps = this.ps[1:]
+	return p, nil
+}
+
+func bstOf(ids ...int) godatacollections.Set[int, permission] {
+	bst, _ := tree.NewBST(compInts, permissionID, permission{})
+	for _, id := range ids {
+		bst.Insert(permission{id: id, name: "bst"})
+	}
+	return bst
+}
+
+func mapSetOf(ids ...int) godatacollections.Set[int, permission] {
+	set, _ := newMapSet()
+	for _, id := range ids {
+		set.Insert(permission{id: id, name: "map"})
+	}
+	return set
+}
+
+func idsOf(t *testing.T, set godatacollections.Set[int, permission]) []int {
+	ids := make([]int, 0)
+	iter := set.Iterator()
+	for iter.HasNext() {
+		p, err := iter.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, p.id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func expectIDs(t *testing.T, set godatacollections.Set[int, permission], expected ...int) {
+	ids := idsOf(t, set)
+	if len(ids) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, ids)
+	}
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Fatalf("expected %v but got %v", expected, ids)
+		}
+	}
+}
+
+// allOps returns ops and set makers for each of the paths so each test covers all of them
+func allOps(t *testing.T) map[string]struct {
+	ops   *SetOps[int, permission]
+	setOf func(...int) godatacollections.Set[int, permission]
+} {
+	ordered, err := NewOrderedSetOps(compInts, permissionID, permission{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unordered, err := NewSetOps(permissionID, newMapSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]struct {
+		ops   *SetOps[int, permission]
+		setOf func(...int) godatacollections.Set[int, permission]
+	}{
+		"merge":   {ops: ordered, setOf: bstOf},
+		"ordered": {ops: ordered, setOf: mapSetOf},
+		"generic": {ops: unordered, setOf: mapSetOf},
+	}
+}
+
+func TestNewSetOpsReturnsErrorIfMissingFuncs(t *testing.T) {
+	if _, err := NewSetOps[int, permission](nil, newMapSet); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewSetOps(permissionID, nil); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewOrderedSetOps(nil, permissionID, permission{}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestUnion(t *testing.T) {
+	for name, cur := range allOps(t) {
+		result, err := cur.ops.Union(cur.setOf(1, 3, 5, 7), cur.setOf(2, 3, 4, 7, 9))
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		expectIDs(t, result, 1, 2, 3, 4, 5, 7, 9)
+	}
+}
+
+func TestUnionKeepsItemFromFirstSet(t *testing.T) {
+	ops, _ := NewOrderedSetOps(compInts, permissionID, permission{})
+
+	result, _ := ops.Union(bstOf(1), mapSetOf(1))
+	if result.GetByKey(1).name != "bst" {
+		t.Fail()
+	}
+
+	result, _ = ops.Union(bstOf(1), bstOf(1))
+	if result.GetByKey(1).name != "bst" {
+		t.Fail()
+	}
+}
+
+func TestIntersection(t *testing.T) {
+	for name, cur := range allOps(t) {
+		result, err := cur.ops.Intersection(cur.setOf(1, 3, 5, 7), cur.setOf(2, 3, 4, 7, 9))
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		expectIDs(t, result, 3, 7)
+	}
+}
+
+func TestDifference(t *testing.T) {
+	for name, cur := range allOps(t) {
+		result, err := cur.ops.Difference(cur.setOf(1, 3, 5, 7), cur.setOf(2, 3, 4, 7, 9))
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		expectIDs(t, result, 1, 5)
+	}
+}
+
+func TestSymmetricDifference(t *testing.T) {
+	for name, cur := range allOps(t) {
+		result, err := cur.ops.SymmetricDifference(cur.setOf(1, 3, 5, 7), cur.setOf(2, 3, 4, 7, 9))
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		expectIDs(t, result, 1, 2, 4, 5, 9)
+	}
+}
+
+func TestOpsOnEmptySets(t *testing.T) {
+	for name, cur := range allOps(t) {
+		result, err := cur.ops.Union(cur.setOf(), cur.setOf())
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		expectIDs(t, result)
+
+		result, err = cur.ops.Difference(cur.setOf(1, 2), cur.setOf())
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		expectIDs(t, result, 1, 2)
+	}
+}
+
+func TestIsSubsetAndIsSuperset(t *testing.T) {
+	for name, cur := range allOps(t) {
+		small := cur.setOf(2, 4)
+		big := cur.setOf(1, 2, 3, 4)
+
+		if !cur.ops.IsSubset(small, big) {
+			t.Fatal(name, "expected small to be a subset of big")
+		}
+		if cur.ops.IsSubset(big, small) {
+			t.Fatal(name, "expected big to not be a subset of small")
+		}
+		if !cur.ops.IsSuperset(big, small) {
+			t.Fatal(name, "expected big to be a superset of small")
+		}
+		if cur.ops.IsSuperset(small, big) {
+			t.Fatal(name, "expected small to not be a superset of big")
+		}
+		if !cur.ops.IsSubset(cur.setOf(), small) {
+			t.Fatal(name, "expected empty set to be a subset")
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	for name, cur := range allOps(t) {
+		if !cur.ops.Equal(cur.setOf(1, 2, 3), cur.setOf(3, 2, 1)) {
+			t.Fatal(name, "expected sets with same keys to be equal")
+		}
+		if cur.ops.Equal(cur.setOf(1, 2, 3), cur.setOf(1, 2)) {
+			t.Fatal(name, "expected sets with different keys to not be equal")
+		}
+		if cur.ops.Equal(cur.setOf(1, 2), cur.setOf(1, 2, 3)) {
+			t.Fatal(name, "expected sets with different keys to not be equal")
+		}
+	}
+}
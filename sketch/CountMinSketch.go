@@ -0,0 +1,122 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ZacharyDuve/godatacollections/internal/hashmix"
+)
+
+// CountMinSketch estimates how many times each key has been added in a fixed width * depth counts no matter how many keys there are
+// Estimates are never too low and are too high by at most epsilon * Total with probability 1 - delta
+// Good for finding heavy hitters, keys seen rarely get estimates swamped by collisions with common ones
+type CountMinSketch[K any] struct {
+	hashFunc func(K) uint64
+	width    uint64
+	depth    uint64
+	// counts has depth rows of width counts one after another
+	counts []uint64
+	total  uint64
+}
+
+// NewCountMinSketch creates a new empty CountMinSketch sized from epsilon and delta, both between 0 and 1
+func NewCountMinSketch[K any](epsilon, delta float64, hashFunc func(K) uint64) (*CountMinSketch[K], error) {
+	if !(epsilon > 0 && epsilon < 1) {
+		return nil, fmt.Errorf("unable to create CountMinSketch with epsilon %v, needs to be between 0 and 1", epsilon)
+	}
+
+	if !(delta > 0 && delta < 1) {
+		return nil, fmt.Errorf("unable to create CountMinSketch with delta %v, needs to be between 0 and 1", delta)
+	}
+
+	width := uint64(math.Ceil(math.E / epsilon))
+	depth := uint64(math.Ceil(math.Log(1 / delta)))
+
+	return NewCountMinSketchWithSize(width, depth, hashFunc)
+}
+
+// NewCountMinSketchWithSize creates a new empty CountMinSketch with depth rows of width counts
+func NewCountMinSketchWithSize[K any](width, depth uint64, hashFunc func(K) uint64) (*CountMinSketch[K], error) {
+	if hashFunc == nil {
+		return nil, errors.New("unable to create CountMinSketch without a function to hash Keys")
+	}
+
+	if width < 1 || depth < 1 {
+		return nil, fmt.Errorf("unable to create CountMinSketch of width %d and depth %d, both need to be at least 1", width, depth)
+	}
+
+	return &CountMinSketch[K]{hashFunc: hashFunc, width: width, depth: depth, counts: make([]uint64, width*depth)}, nil
+}
+
+// Width returns the number of counts in each row
+func (this *CountMinSketch[K]) Width() uint64 {
+	return this.width
+}
+
+// Depth returns the number of rows
+func (this *CountMinSketch[K]) Depth() uint64 {
+	return this.depth
+}
+
+// Total returns the sum of every count added
+func (this *CountMinSketch[K]) Total() uint64 {
+	return this.total
+}
+
+// columns calls f with the index into counts for key in each row
+// The column for each row comes from double hashing so only the one hash of the key is needed
+func (this *CountMinSketch[K]) columns(key K, f func(index uint64)) {
+	first := hashmix.Mix(this.hashFunc(key))
+	second := hashmix.Mix(first) | 1
+
+	for row := uint64(0); row < this.depth; row++ {
+		f(row*this.width + (first+row*second)%this.width)
+	}
+}
+
+// Increment adds 1 to the count for key
+func (this *CountMinSketch[K]) Increment(key K) {
+	this.Add(key, 1)
+}
+
+// Add adds count to the count for key
+func (this *CountMinSketch[K]) Add(key K, count uint64) {
+	this.columns(key, func(index uint64) {
+		this.counts[index] += count
+	})
+	this.total += count
+}
+
+// Estimate returns about how many times key has been added, it is never less than the true count
+func (this *CountMinSketch[K]) Estimate(key K) uint64 {
+	estimate := uint64(math.MaxUint64)
+
+	this.columns(key, func(index uint64) {
+		estimate = min(estimate, this.counts[index])
+	})
+
+	return estimate
+}
+
+// Merge adds every count from other to this sketch, afterwards Estimate is about the count added to either
+// Both sketches need the same width and depth and should use the same hash function
+func (this *CountMinSketch[K]) Merge(other *CountMinSketch[K]) error {
+	if this.width != other.width || this.depth != other.depth {
+		return fmt.Errorf("unable to merge CountMinSketch of width %d and depth %d with one of width %d and depth %d", this.width, this.depth, other.width, other.depth)
+	}
+
+	for i, count := range other.counts {
+		this.counts[i] += count
+	}
+
+	this.total += other.total
+
+	return nil
+}
+
+// Clear sets every count back to 0
+func (this *CountMinSketch[K]) Clear() {
+	clear(this.counts)
+	this.total = 0
+}
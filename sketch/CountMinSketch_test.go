@@ -0,0 +1,128 @@
+package sketch
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNewCountMinSketchErrors(t *testing.T) {
+	if _, err := NewCountMinSketch[int](0.01, 0.01, nil); err == nil {
+		t.Fail()
+	}
+
+	for _, args := range [][2]float64{{0, 0.01}, {1, 0.01}, {0.01, 0}, {0.01, 1}} {
+		if _, err := NewCountMinSketch(args[0], args[1], hashInt); err == nil {
+			t.Fatalf("expected error for epsilon %v and delta %v", args[0], args[1])
+		}
+	}
+
+	if _, err := NewCountMinSketchWithSize(0, 4, hashInt); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewCountMinSketchWithSize(100, 0, hashInt); err == nil {
+		t.Fail()
+	}
+}
+
+func TestCountMinSketchSizing(t *testing.T) {
+	sketch, _ := NewCountMinSketch(0.001, 0.01, hashInt)
+
+	// e / 0.001 and ln(1 / 0.01)
+	if sketch.Width() != 2719 || sketch.Depth() != 5 {
+		t.Fatalf("expected width 2719 and depth 5 but got %d and %d", sketch.Width(), sketch.Depth())
+	}
+}
+
+func TestCountMinSketchExactWithoutCollisions(t *testing.T) {
+	sketch, _ := NewCountMinSketch(0.001, 0.001, hashVisitor)
+
+	sketch.Increment(visitor{id: 1})
+	sketch.Increment(visitor{id: 1, pages: []string{"cart"}})
+	sketch.Add(visitor{id: 2}, 40)
+
+	if sketch.Estimate(visitor{id: 1}) != 2 || sketch.Estimate(visitor{id: 2}) != 40 || sketch.Estimate(visitor{id: 3}) != 0 {
+		t.Fail()
+	}
+
+	if sketch.Total() != 42 {
+		t.Fail()
+	}
+
+	sketch.Clear()
+
+	if sketch.Estimate(visitor{id: 2}) != 0 || sketch.Total() != 0 {
+		t.Fail()
+	}
+}
+
+// zipfCounts adds a skewed stream to sketch and returns the true counts
+func zipfCounts(sketch *CountMinSketch[int], events int, seed int64) map[int]uint64 {
+	zipf := rand.NewZipf(rand.New(rand.NewSource(seed)), 1.2, 1, 100000)
+	counts := make(map[int]uint64)
+
+	for i := 0; i < events; i++ {
+		key := int(zipf.Uint64())
+		sketch.Increment(key)
+		counts[key]++
+	}
+
+	return counts
+}
+
+func TestCountMinSketchErrorBound(t *testing.T) {
+	epsilon := 0.001
+	sketch, _ := NewCountMinSketch(epsilon, 0.01, hashInt)
+	counts := zipfCounts(sketch, 200000, 1)
+	bound := uint64(epsilon * float64(sketch.Total()))
+	overBound := 0
+
+	for key, count := range counts {
+		estimate := sketch.Estimate(key)
+
+		if estimate < count {
+			t.Fatalf("estimate %d for %d is below the true count %d", estimate, key, count)
+		}
+
+		if estimate-count > bound {
+			overBound++
+		}
+	}
+
+	// delta is 1% so allow a little more than that for chance
+	if float64(overBound) > 0.02*float64(len(counts)) {
+		t.Fatalf("%d of %d estimates were over the error bound", overBound, len(counts))
+	}
+
+	// The heaviest hitter, 0 for Zipf, is found exactly or very close
+	if estimate := sketch.Estimate(0); estimate-counts[0] > bound {
+		t.Fatalf("expected heavy hitter count near %d but got %d", counts[0], estimate)
+	}
+}
+
+func TestCountMinSketchMerge(t *testing.T) {
+	a, _ := NewCountMinSketch(0.001, 0.01, hashInt)
+	b, _ := NewCountMinSketch(0.001, 0.01, hashInt)
+	aCounts := zipfCounts(a, 50000, 1)
+	bCounts := zipfCounts(b, 50000, 2)
+
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Total() != 100000 || b.Total() != 50000 {
+		t.Fatal("expected totals to be summed into the receiver only")
+	}
+
+	for key := range aCounts {
+		if a.Estimate(key) < aCounts[key]+bCounts[key] {
+			t.Fatalf("merged estimate for %d is below the true count", key)
+		}
+	}
+
+	other, _ := NewCountMinSketchWithSize(10, 5, hashInt)
+
+	if a.Merge(other) == nil {
+		t.Fatal("expected error merging different sizes")
+	}
+}
@@ -0,0 +1,107 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/ZacharyDuve/godatacollections/internal/hashmix"
+)
+
+// HyperLogLog estimates how many distinct keys it has seen using a fixed 2^precision bytes no matter how many there are
+// The standard error of the estimate is about 1.04 / sqrt(2^precision), so 1.6% at the default precision of 12
+// Sketches with the same precision and hash function can be merged, such as one per queue consumer
+type HyperLogLog[K any] struct {
+	hashFunc  func(K) uint64
+	precision uint8
+	// registers hold the most leading zeros plus one seen in the hashes that land in each
+	registers []uint8
+}
+
+// NewHyperLogLog creates a new empty HyperLogLog with 2^precision registers, precision needs to be from 4 to 18
+func NewHyperLogLog[K any](precision uint8, hashFunc func(K) uint64) (*HyperLogLog[K], error) {
+	if hashFunc == nil {
+		return nil, errors.New("unable to create HyperLogLog without a function to hash Keys")
+	}
+
+	if precision < 4 || precision > 18 {
+		return nil, fmt.Errorf("unable to create HyperLogLog with precision %d, needs to be from 4 to 18", precision)
+	}
+
+	return &HyperLogLog[K]{hashFunc: hashFunc, precision: precision, registers: make([]uint8, 1<<precision)}, nil
+}
+
+// Precision returns the number of bits of each hash used to pick a register
+func (this *HyperLogLog[K]) Precision() uint8 {
+	return this.precision
+}
+
+// Add records that key was seen
+func (this *HyperLogLog[K]) Add(key K) {
+	hash := hashmix.Mix(this.hashFunc(key))
+	// The top bits pick the register and the rest are counted for leading zeros
+	register := hash >> (64 - this.precision)
+	// The set bit stops the count at the end of the bits that are left
+	rest := hash<<this.precision | 1<<(this.precision-1)
+	rank := uint8(bits.LeadingZeros64(rest)) + 1
+
+	this.registers[register] = max(this.registers[register], rank)
+}
+
+// Estimate returns about how many distinct keys have been added
+func (this *HyperLogLog[K]) Estimate() uint64 {
+	m := float64(len(this.registers))
+	sum := 0.0
+	zeros := 0
+
+	for _, register := range this.registers {
+		sum += math.Ldexp(1, -int(register))
+
+		if register == 0 {
+			zeros++
+		}
+	}
+
+	estimate := alpha(len(this.registers)) * m * m / sum
+
+	// Small counts are better estimated from how many registers are still empty
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(math.Round(estimate))
+}
+
+// alpha corrects the bias of the raw estimate for numRegisters
+func alpha(numRegisters int) float64 {
+	switch numRegisters {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(numRegisters))
+	}
+}
+
+// Merge adds every key seen by other to this sketch, afterwards Estimate is about the distinct keys seen by either
+// Both sketches need the same precision and should use the same hash function
+func (this *HyperLogLog[K]) Merge(other *HyperLogLog[K]) error {
+	if this.precision != other.precision {
+		return fmt.Errorf("unable to merge HyperLogLog of precision %d with one of precision %d", this.precision, other.precision)
+	}
+
+	for i, register := range other.registers {
+		this.registers[i] = max(this.registers[i], register)
+	}
+
+	return nil
+}
+
+// Clear forgets every key
+func (this *HyperLogLog[K]) Clear() {
+	clear(this.registers)
+}
@@ -0,0 +1,107 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"testing"
+)
+
+type visitor struct {
+	id    int
+	pages []string
+}
+
+// hashVisitor hashes only the id, visitors hold a slice so aren't comparable
+func hashVisitor(v visitor) uint64 {
+	return hashInt(v.id)
+}
+
+func hashInt(i int) uint64 {
+	hash := fnv.New64a()
+	hash.Write(binary.LittleEndian.AppendUint64(nil, uint64(i)))
+
+	return hash.Sum64()
+}
+
+func expectWithin(t *testing.T, actual uint64, expected int, relativeError float64) {
+	if math.Abs(float64(actual)-float64(expected)) > relativeError*float64(expected) {
+		t.Fatalf("expected %d within %v but got %d", expected, relativeError, actual)
+	}
+}
+
+func TestNewHyperLogLogErrors(t *testing.T) {
+	if _, err := NewHyperLogLog[int](12, nil); err == nil {
+		t.Fail()
+	}
+
+	for _, precision := range []uint8{0, 3, 19} {
+		if _, err := NewHyperLogLog(precision, hashInt); err == nil {
+			t.Fatalf("expected error for precision %d", precision)
+		}
+	}
+}
+
+func TestHyperLogLogEstimate(t *testing.T) {
+	hll, _ := NewHyperLogLog(12, hashVisitor)
+
+	if hll.Estimate() != 0 {
+		t.Fail()
+	}
+
+	for i := 0; i < 10; i++ {
+		hll.Add(visitor{id: 5, pages: []string{"home"}})
+	}
+
+	if hll.Estimate() != 1 {
+		t.Fatalf("expected repeats of one visitor to count once but got %d", hll.Estimate())
+	}
+
+	// 4 standard errors at precision 12 is about 6.5%
+	for _, distinct := range []int{100, 1000, 10000, 100000, 1000000} {
+		hll.Clear()
+
+		for i := 0; i < distinct; i++ {
+			hll.Add(visitor{id: i})
+			hll.Add(visitor{id: i})
+		}
+
+		expectWithin(t, hll.Estimate(), distinct, 0.065)
+	}
+}
+
+func TestHyperLogLogWeakHash(t *testing.T) {
+	hll, _ := NewHyperLogLog(10, func(i int) uint64 { return uint64(i) })
+
+	for i := 0; i < 50000; i++ {
+		hll.Add(i)
+	}
+
+	// 4 standard errors at precision 10 is about 13%
+	expectWithin(t, hll.Estimate(), 50000, 0.13)
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	a, _ := NewHyperLogLog(12, hashInt)
+	b, _ := NewHyperLogLog(12, hashInt)
+
+	// Overlap in 20000 to 29999
+	for i := 0; i < 30000; i++ {
+		a.Add(i)
+		b.Add(i + 20000)
+	}
+
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+
+	expectWithin(t, a.Estimate(), 50000, 0.065)
+	// other is left alone
+	expectWithin(t, b.Estimate(), 30000, 0.065)
+
+	other, _ := NewHyperLogLog(10, hashInt)
+
+	if a.Merge(other) == nil {
+		t.Fatal("expected error merging different precisions")
+	}
+}
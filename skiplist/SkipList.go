@@ -0,0 +1,430 @@
+package skiplist
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// maxLevel is the most levels a node can be on. With a 1/2 chance of going up a level it is
+// enough for far more items than will ever fit in memory
+const maxLevel = 32
+
+// SkipList is an ordered set that is safe for use by many go routines at once
+// It is the lazy skip list from Herlihy, Lev, Luchangco and Shavit:
+//
+//	Reads (Contains, GetByKey, iterators) never lock
+//	Insert and Remove only lock the nodes right around the item being changed so changes to different parts of the list don't block each other
+//
+// Iterators are weakly consistent. They never return an item twice or out of order
+// but may or may not see changes made after they were created
+type SkipList[K, T any] struct {
+	kCompFunc func(K, K) int
+	tToKFunc  func(T) K
+	zeroValue T
+	// head is a sentinel that is before every node. Its key is never compared
+	// There is no tail sentinel, a nil next marks the end of a level
+	head *skipListNode[K, T]
+	size atomic.Int64
+}
+
+type skipListNode[K, T any] struct {
+	key K
+	t   T
+	// next has one entry per level that this node is on
+	next []atomic.Pointer[skipListNode[K, T]]
+	mu   sync.Mutex
+	// marked is set once the node is logically removed, before it is unlinked
+	marked atomic.Bool
+	// fullyLinked is set once the node has been linked in on every level
+	fullyLinked atomic.Bool
+}
+
+func (this *skipListNode[K, T]) topLevel() int {
+	return len(this.next)
+}
+
+// isLive returns if the node is fully in the list and not being removed
+func (this *skipListNode[K, T]) isLive() bool {
+	return this.fullyLinked.Load() && !this.marked.Load()
+}
+
+// NewSkipList creates a new empty SkipList
+// kCompFunc, tToKFunc and tZeroValue are the same as for tree.NewBST
+func NewSkipList[K, T any](kCompFunc func(K, K) int, tToKFunc func(T) K, tZeroValue T) (*SkipList[K, T], error) {
+	if kCompFunc == nil {
+		return nil, errors.New("unable to create SkipList without a function to compare Keys")
+	}
+
+	if tToKFunc == nil {
+		return nil, errors.New("unable to create SkipList without a function to convert T to a Key")
+	}
+
+	head := &skipListNode[K, T]{next: make([]atomic.Pointer[skipListNode[K, T]], maxLevel)}
+
+	return &SkipList[K, T]{kCompFunc: kCompFunc, tToKFunc: tToKFunc, zeroValue: tZeroValue, head: head}, nil
+}
+
+// Len returns the number of items in the list
+func (this *SkipList[K, T]) Len() int {
+	return int(this.size.Load())
+}
+
+// randomLevel picks how many levels a new node is on with each extra level half as likely as the one before
+func randomLevel() int {
+	return min(bits.TrailingZeros64(rand.Uint64())+1, maxLevel)
+}
+
+// find fills in the nodes just before (preds) and at or just after (succs) key on every level
+// Returns the highest level that a node with key was found on or -1 if there is none
+func (this *SkipList[K, T]) find(key K, preds, succs *[maxLevel]*skipListNode[K, T]) int {
+	levelFound := -1
+	pred := this.head
+
+	for level := maxLevel - 1; level >= 0; level-- {
+		curr := pred.next[level].Load()
+
+		for curr != nil && this.kCompFunc(key, curr.key) > 0 {
+			pred = curr
+			curr = pred.next[level].Load()
+		}
+
+		if levelFound == -1 && curr != nil && this.kCompFunc(key, curr.key) == 0 {
+			levelFound = level
+		}
+
+		preds[level] = pred
+		succs[level] = curr
+	}
+
+	return levelFound
+}
+
+// lockPreds locks each distinct pred from level 0 up to topLevel while checking that they are still valid
+// Returns the highest level locked and if everything was valid. Locks must be released with unlockPreds either way
+func lockPreds[K, T any](preds *[maxLevel]*skipListNode[K, T], topLevel int, isValid func(level int) bool) (int, bool) {
+	highestLocked := -1
+	var prevPred *skipListNode[K, T]
+
+	for level := 0; level < topLevel; level++ {
+		pred := preds[level]
+
+		// The same pred is often used on several levels in a row and can only be locked once
+		if pred != prevPred {
+			pred.mu.Lock()
+			prevPred = pred
+		}
+		highestLocked = level
+
+		if !isValid(level) {
+			return highestLocked, false
+		}
+	}
+
+	return highestLocked, true
+}
+
+func unlockPreds[K, T any](preds *[maxLevel]*skipListNode[K, T], highestLocked int) {
+	var prevPred *skipListNode[K, T]
+
+	for level := 0; level <= highestLocked; level++ {
+		if preds[level] != prevPred {
+			preds[level].mu.Unlock()
+			prevPred = preds[level]
+		}
+	}
+}
+
+func (this *SkipList[K, T]) Insert(newT T) error {
+	newKey := this.tToKFunc(newT)
+	topLevel := randomLevel()
+
+	var preds, succs [maxLevel]*skipListNode[K, T]
+
+	for {
+		levelFound := this.find(newKey, &preds, &succs)
+
+		if levelFound != -1 {
+			found := succs[levelFound]
+
+			if !found.marked.Load() {
+				// Wait for the other insert to finish so that Contains agrees with us once we return
+				for !found.fullyLinked.Load() {
+					runtime.Gosched()
+				}
+				return fmt.Errorf("unable to insert duplicate T for key %v", newKey)
+			}
+
+			// Found node is being removed so try again once it is gone
+			continue
+		}
+
+		highestLocked, valid := lockPreds(&preds, topLevel, func(level int) bool {
+			succ := succs[level]
+			return !preds[level].marked.Load() && (succ == nil || !succ.marked.Load()) && preds[level].next[level].Load() == succ
+		})
+
+		if !valid {
+			// Something changed around us between find and locking so start over
+			unlockPreds(&preds, highestLocked)
+			continue
+		}
+
+		newNode := &skipListNode[K, T]{key: newKey, t: newT, next: make([]atomic.Pointer[skipListNode[K, T]], topLevel)}
+
+		for level := 0; level < topLevel; level++ {
+			newNode.next[level].Store(succs[level])
+		}
+
+		for level := 0; level < topLevel; level++ {
+			preds[level].next[level].Store(newNode)
+		}
+
+		newNode.fullyLinked.Store(true)
+		unlockPreds(&preds, highestLocked)
+		this.size.Add(1)
+
+		return nil
+	}
+}
+
+func (this *SkipList[K, T]) Remove(key K) error {
+	var victim *skipListNode[K, T]
+	isMarked := false
+
+	var preds, succs [maxLevel]*skipListNode[K, T]
+
+	for {
+		levelFound := this.find(key, &preds, &succs)
+
+		if !isMarked {
+			if levelFound == -1 {
+				return fmt.Errorf("unable to delete item with key %v due to it not existing in list", key)
+			}
+
+			victim = succs[levelFound]
+
+			// Only remove nodes that are fully linked and were found on their top level, otherwise
+			// the node is either still being inserted or we raced with a change and need another look
+			if !victim.fullyLinked.Load() || victim.topLevel()-1 != levelFound || victim.marked.Load() {
+				if victim.marked.Load() {
+					return fmt.Errorf("unable to delete item with key %v due to it not existing in list", key)
+				}
+				runtime.Gosched()
+				continue
+			}
+
+			victim.mu.Lock()
+
+			if victim.marked.Load() {
+				// Someone else got to it first
+				victim.mu.Unlock()
+				return fmt.Errorf("unable to delete item with key %v due to it not existing in list", key)
+			}
+
+			// From here on the node is logically gone. Readers will skip it
+			victim.marked.Store(true)
+			isMarked = true
+		}
+
+		highestLocked, valid := lockPreds(&preds, victim.topLevel(), func(level int) bool {
+			return !preds[level].marked.Load() && preds[level].next[level].Load() == victim
+		})
+
+		if !valid {
+			unlockPreds(&preds, highestLocked)
+			continue
+		}
+
+		for level := victim.topLevel() - 1; level >= 0; level-- {
+			preds[level].next[level].Store(victim.next[level].Load())
+		}
+
+		victim.mu.Unlock()
+		unlockPreds(&preds, highestLocked)
+		this.size.Add(-1)
+
+		return nil
+	}
+}
+
+func (this *SkipList[K, T]) Contains(key K) bool {
+	found := this.get(key)
+
+	return found != nil
+}
+
+func (this *SkipList[K, T]) GetByKey(key K) T {
+	found := this.get(key)
+
+	if found == nil {
+		return this.zeroValue
+	}
+
+	return found.t
+}
+
+// get returns the live node for key or nil. Never locks
+func (this *SkipList[K, T]) get(key K) *skipListNode[K, T] {
+	curr := this.ceilingNode(key)
+
+	if curr != nil && this.kCompFunc(key, curr.key) == 0 && curr.isLive() {
+		return curr
+	}
+
+	return nil
+}
+
+// ceilingNode returns the first node on level 0 with a key >= key, live or not
+func (this *SkipList[K, T]) ceilingNode(key K) *skipListNode[K, T] {
+	pred := this.head
+	var curr *skipListNode[K, T]
+
+	for level := maxLevel - 1; level >= 0; level-- {
+		curr = pred.next[level].Load()
+
+		for curr != nil && this.kCompFunc(key, curr.key) > 0 {
+			pred = curr
+			curr = pred.next[level].Load()
+		}
+	}
+
+	return curr
+}
+
+// nextLive returns node if it is live or the first live node after it on level 0
+func nextLive[K, T any](node *skipListNode[K, T]) *skipListNode[K, T] {
+	for node != nil && !node.isLive() {
+		node = node.next[0].Load()
+	}
+
+	return node
+}
+
+// Min returns the item with the smallest key
+// Returns zero value and EmptyError if the list is empty
+func (this *SkipList[K, T]) Min() (T, error) {
+	first := nextLive(this.head.next[0].Load())
+
+	if first == nil {
+		return this.zeroValue, godatacollections.EmptyError()
+	}
+
+	return first.t, nil
+}
+
+// Max returns the item with the largest key
+// Returns zero value and EmptyError if the list is empty
+func (this *SkipList[K, T]) Max() (T, error) {
+	for {
+		pred := this.head
+
+		for level := maxLevel - 1; level >= 0; level-- {
+			for curr := pred.next[level].Load(); curr != nil; curr = pred.next[level].Load() {
+				pred = curr
+			}
+		}
+
+		if pred == this.head {
+			return this.zeroValue, godatacollections.EmptyError()
+		}
+
+		if pred.isLive() {
+			return pred.t, nil
+		}
+
+		// Last node is part way through being added or removed, which won't take long
+		runtime.Gosched()
+	}
+}
+
+// Floor returns the item with the largest key that is <= key
+// Returns zero value and NotFoundError if every key is greater
+func (this *SkipList[K, T]) Floor(key K) (T, error) {
+	var preds, succs [maxLevel]*skipListNode[K, T]
+
+	for {
+		levelFound := this.find(key, &preds, &succs)
+
+		if levelFound != -1 && succs[levelFound].isLive() {
+			return succs[levelFound].t, nil
+		}
+
+		if preds[0] == this.head {
+			return this.zeroValue, godatacollections.NotFoundError()
+		}
+
+		if preds[0].isLive() {
+			return preds[0].t, nil
+		}
+
+		// Node before key is part way through being added or removed, which won't take long
+		runtime.Gosched()
+	}
+}
+
+// Ceiling returns the item with the smallest key that is >= key
+// Returns zero value and NotFoundError if every key is less
+func (this *SkipList[K, T]) Ceiling(key K) (T, error) {
+	found := nextLive(this.ceilingNode(key))
+
+	if found == nil {
+		return this.zeroValue, godatacollections.NotFoundError()
+	}
+
+	return found.t, nil
+}
+
+// Iterator returns an iterator over every item in key order
+func (this *SkipList[K, T]) Iterator() godatacollections.Iterator[T] {
+	return &skipListIterator[K, T]{list: this, next: nextLive(this.head.next[0].Load())}
+}
+
+// RangeIterator returns an iterator over the items with keys >= start and < end in key order
+func (this *SkipList[K, T]) RangeIterator(start, end K) godatacollections.Iterator[T] {
+	iter := &skipListIterator[K, T]{list: this, end: &end}
+	iter.setNext(nextLive(this.ceilingNode(start)))
+
+	return iter
+}
+
+type skipListIterator[K, T any] struct {
+	list *SkipList[K, T]
+	next *skipListNode[K, T]
+	// end is nil when there is no upper bound
+	end *K
+}
+
+func (this *skipListIterator[K, T]) setNext(node *skipListNode[K, T]) {
+	if node != nil && this.end != nil && this.list.kCompFunc(node.key, *this.end) >= 0 {
+		node = nil
+	}
+
+	this.next = node
+}
+
+func (this *skipListIterator[K, T]) Close() error {
+	return nil
+}
+
+func (this *skipListIterator[K, T]) HasNext() bool {
+	return this.next != nil
+}
+
+func (this *skipListIterator[K, T]) Next() (T, error) {
+	if this.next == nil {
+		return this.list.zeroValue, errors.New("nothing left to iterate over")
+	}
+
+	retNode := this.next
+	// Removed nodes keep their next pointers so it is always safe to keep walking from one
+	this.setNext(nextLive(retNode.next[0].Load()))
+
+	return retNode.t, nil
+}
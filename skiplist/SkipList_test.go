@@ -0,0 +1,338 @@
+package skiplist
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// SkipList needs to be usable anywhere an OrderedSet is
+var _ godatacollections.OrderedSet[int, int] = &SkipList[int, int]{}
+
+func compInts(a, b int) int {
+	return a - b
+}
+
+func intSkipList() *SkipList[int, int] {
+	list, _ := NewSkipList(compInts, func(i int) int { return i }, -1)
+
+	return list
+}
+
+func iterValues(iter godatacollections.Iterator[int]) []int {
+	values := make([]int, 0)
+
+	for iter.HasNext() {
+		curVal, _ := iter.Next()
+		values = append(values, curVal)
+	}
+
+	return values
+}
+
+func expectValues(t *testing.T, actual []int, expected ...int) {
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, actual)
+	}
+
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("expected %v but got %v", expected, actual)
+		}
+	}
+}
+
+func TestNewSkipListWithNilFuncsErrors(t *testing.T) {
+	_, err := NewSkipList[int, int](nil, func(i int) int { return i }, 0)
+	if err == nil {
+		t.Fail()
+	}
+
+	_, err = NewSkipList[int, int](compInts, nil, 0)
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestSkipListInsertContainsGetByKey(t *testing.T) {
+	list := intSkipList()
+
+	for _, v := range rand.Perm(500) {
+		if err := list.Insert(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if list.Len() != 500 {
+		t.Fatalf("expected 500 items but got %d", list.Len())
+	}
+
+	for i := 0; i < 500; i++ {
+		if !list.Contains(i) || list.GetByKey(i) != i {
+			t.Fatalf("expected to find %d", i)
+		}
+	}
+
+	if list.Contains(500) || list.GetByKey(-5) != -1 {
+		t.Fail()
+	}
+}
+
+func TestSkipListInsertDuplicateErrors(t *testing.T) {
+	list := intSkipList()
+	list.Insert(1)
+
+	if list.Insert(1) == nil {
+		t.Fail()
+	}
+
+	if list.Len() != 1 {
+		t.Fail()
+	}
+}
+
+func TestSkipListRemove(t *testing.T) {
+	list := intSkipList()
+
+	for i := 0; i < 100; i++ {
+		list.Insert(i)
+	}
+
+	for i := 0; i < 100; i += 2 {
+		if err := list.Remove(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if list.Remove(0) == nil || list.Remove(1000) == nil {
+		t.Fatal("expected error removing missing key")
+	}
+
+	if list.Len() != 50 {
+		t.Fatalf("expected 50 items but got %d", list.Len())
+	}
+
+	for i := 0; i < 100; i++ {
+		if list.Contains(i) != (i%2 == 1) {
+			t.Fatalf("wrong contains result for %d", i)
+		}
+	}
+}
+
+func TestSkipListIteratorIsOrdered(t *testing.T) {
+	list := intSkipList()
+
+	for _, v := range []int{5, 3, 9, 1, 7} {
+		list.Insert(v)
+	}
+
+	expectValues(t, iterValues(list.Iterator()), 1, 3, 5, 7, 9)
+
+	iter := intSkipList().Iterator()
+	if iter.HasNext() {
+		t.Fail()
+	}
+
+	if _, err := iter.Next(); err == nil {
+		t.Fail()
+	}
+}
+
+func TestSkipListRangeIterator(t *testing.T) {
+	list := intSkipList()
+
+	for i := 0; i < 20; i += 2 {
+		list.Insert(i)
+	}
+
+	expectValues(t, iterValues(list.RangeIterator(3, 11)), 4, 6, 8, 10)
+	expectValues(t, iterValues(list.RangeIterator(4, 10)), 4, 6, 8)
+	expectValues(t, iterValues(list.RangeIterator(-10, 3)), 0, 2)
+	expectValues(t, iterValues(list.RangeIterator(7, 7)))
+	expectValues(t, iterValues(list.RangeIterator(30, 40)))
+}
+
+func TestSkipListMinMaxEmptyErrors(t *testing.T) {
+	list := intSkipList()
+
+	if _, err := list.Min(); !godatacollections.IsEmptyError(err) {
+		t.Fail()
+	}
+
+	if _, err := list.Max(); !godatacollections.IsEmptyError(err) {
+		t.Fail()
+	}
+}
+
+func TestSkipListMinMax(t *testing.T) {
+	list := intSkipList()
+
+	for _, v := range rand.Perm(100) {
+		list.Insert(v)
+	}
+
+	if minVal, _ := list.Min(); minVal != 0 {
+		t.Fatalf("expected min 0 but got %d", minVal)
+	}
+
+	if maxVal, _ := list.Max(); maxVal != 99 {
+		t.Fatalf("expected max 99 but got %d", maxVal)
+	}
+}
+
+func TestSkipListFloorCeiling(t *testing.T) {
+	list := intSkipList()
+
+	for i := 10; i <= 50; i += 10 {
+		list.Insert(i)
+	}
+
+	if v, err := list.Floor(25); err != nil || v != 20 {
+		t.Fatalf("expected floor 20 but got %d %v", v, err)
+	}
+
+	if v, err := list.Floor(30); err != nil || v != 30 {
+		t.Fatalf("expected floor 30 but got %d %v", v, err)
+	}
+
+	if _, err := list.Floor(5); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+
+	if v, err := list.Ceiling(25); err != nil || v != 30 {
+		t.Fatalf("expected ceiling 30 but got %d %v", v, err)
+	}
+
+	if v, err := list.Ceiling(10); err != nil || v != 10 {
+		t.Fatalf("expected ceiling 10 but got %d %v", v, err)
+	}
+
+	if _, err := list.Ceiling(55); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+}
+
+func TestSkipListConcurrentInsertRemove(t *testing.T) {
+	list := intSkipList()
+	const workers = 8
+	const perWorker = 500
+
+	var wg sync.WaitGroup
+
+	// Each worker owns its own keys so every insert and remove must succeed
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			for i := 0; i < perWorker; i++ {
+				if err := list.Insert(i*workers + w); err != nil {
+					t.Error(err)
+				}
+			}
+
+			for i := 0; i < perWorker; i += 2 {
+				if err := list.Remove(i*workers + w); err != nil {
+					t.Error(err)
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+
+	if list.Len() != workers*perWorker/2 {
+		t.Fatalf("expected %d items but got %d", workers*perWorker/2, list.Len())
+	}
+
+	values := iterValues(list.Iterator())
+	if len(values) != list.Len() {
+		t.Fatalf("iterator returned %d items but list has %d", len(values), list.Len())
+	}
+
+	for _, v := range values {
+		if (v/workers)%2 == 0 {
+			t.Fatalf("found removed value %d", v)
+		}
+	}
+}
+
+func TestSkipListConcurrentContendedKeys(t *testing.T) {
+	list := intSkipList()
+	const keyRange = 64
+
+	var wg sync.WaitGroup
+	var inserted, removed [keyRange]int64
+	var mu sync.Mutex
+
+	// Everyone fights over the same small set of keys while readers walk the list
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+
+			for i := 0; i < 2000; i++ {
+				key := r.Intn(keyRange)
+
+				if r.Intn(2) == 0 {
+					if list.Insert(key) == nil {
+						mu.Lock()
+						inserted[key]++
+						mu.Unlock()
+					}
+				} else if list.Remove(key) == nil {
+					mu.Lock()
+					removed[key]++
+					mu.Unlock()
+				}
+			}
+		}(int64(w))
+	}
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 200; i++ {
+				prev := -1
+				for _, v := range iterValues(list.Iterator()) {
+					if v <= prev {
+						t.Errorf("iterator out of order %d after %d", v, prev)
+					}
+					prev = v
+				}
+
+				list.Contains(i % keyRange)
+				list.Min()
+				list.Max()
+				list.Floor(i % keyRange)
+				list.Ceiling(i % keyRange)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Successful inserts and removes of a key must alternate so they differ by at most one
+	expectedLen := 0
+	for key := 0; key < keyRange; key++ {
+		diff := inserted[key] - removed[key]
+		if diff != 0 && diff != 1 {
+			t.Fatalf("key %d inserted %d times but removed %d times", key, inserted[key], removed[key])
+		}
+
+		if list.Contains(key) != (diff == 1) {
+			t.Fatalf("contains for key %d doesn't match inserts and removes", key)
+		}
+
+		expectedLen += int(diff)
+	}
+
+	if list.Len() != expectedLen {
+		t.Fatalf("expected %d items but got %d", expectedLen, list.Len())
+	}
+}
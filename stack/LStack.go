@@ -1,6 +1,10 @@
 package stack
 
-import "github.com/ZacharyDuve/godatacollections"
+import (
+	"encoding/json"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
 
 // Implementation of Stack via a linked list
 type LStack[T any] struct {
@@ -36,3 +40,32 @@ func (this *LStack[T]) Pop() (T, error) {
 
 	return retVal, nil
 }
+
+// MarshalJSON writes the stack as a JSON array with the top of the stack first
+func (this *LStack[T]) MarshalJSON() ([]byte, error) {
+	ts := make([]T, 0)
+
+	for curNode := this.head; curNode != nil; curNode = curNode.next {
+		ts = append(ts, curNode.t)
+	}
+
+	return json.Marshal(ts)
+}
+
+// UnmarshalJSON replaces the contents of the stack with a JSON array that has the top of the stack first
+func (this *LStack[T]) UnmarshalJSON(data []byte) error {
+	var ts []T
+
+	if err := json.Unmarshal(data, &ts); err != nil {
+		return err
+	}
+
+	this.head = nil
+
+	// Push from the bottom up so that the first item ends up on top
+	for i := len(ts) - 1; i >= 0; i-- {
+		this.Push(ts[i])
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package stack
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/ZacharyDuve/godatacollections"
@@ -58,3 +59,50 @@ func TestDLStackMaintainsLIFOOrder(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestLStackJSONRoundTripKeepsLIFOOrder(t *testing.T) {
+	s := NewLStack(-1)
+	s.Push(1)
+	s.Push(2)
+	s.Push(3)
+
+	data, err := json.Marshal(s)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "[3,2,1]" {
+		t.Fatalf("expected top of stack first but got %s", data)
+	}
+
+	decoded := NewLStack(-1)
+	// Existing items should be replaced
+	decoded.Push(100)
+
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []int{3, 2, 1, -1} {
+		if pV, _ := decoded.Pop(); pV != expected {
+			t.Fatalf("expected %d but got %d", expected, pV)
+		}
+	}
+}
+
+func TestLStackEmptyMarshalsToEmptyArray(t *testing.T) {
+	data, _ := json.Marshal(NewLStack(0))
+
+	if string(data) != "[]" {
+		t.Fail()
+	}
+}
+
+func TestLStackUnmarshalInvalidJSONReturnsError(t *testing.T) {
+	s := NewLStack(0)
+
+	if err := json.Unmarshal([]byte(`{"a":1}`), s); err == nil {
+		t.Fail()
+	}
+}
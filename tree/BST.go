@@ -14,6 +14,14 @@ type BST[K, T any] struct {
 	tToKFunc  func(T) K
 	zeroValue T
 	root      *bstNode[K, T]
+	// owner marks which nodes this tree is allowed to change in place
+	// Nodes with a different owner are shared with a snapshot or clone and must be copied before changing
+	owner *bstOwner
+}
+
+// bstOwner is only ever compared by pointer. Needs to have a size so that each new one has a unique address
+type bstOwner struct {
+	_ byte
 }
 
 // NewBST creates a new Binary Search Tree
@@ -42,7 +50,12 @@ func NewBST[K, T any](kCompFunc func(K, K) int, tToKFunc func(T) K, tZeroValue T
 		return nil, errors.New("unable to create BST without a function to convert T to a Key")
 	}
 
-	return &BST[K, T]{kCompFunc: kCompFunc, tToKFunc: tToKFunc, zeroValue: tZeroValue}, nil
+	return &BST[K, T]{kCompFunc: kCompFunc, tToKFunc: tToKFunc, zeroValue: tZeroValue, owner: &bstOwner{}}, nil
+}
+
+// newEmptyLike creates a new empty tree with the same functions as this one
+func (this *BST[K, T]) newEmptyLike() *BST[K, T] {
+	return &BST[K, T]{kCompFunc: this.kCompFunc, tToKFunc: this.tToKFunc, zeroValue: this.zeroValue, owner: &bstOwner{}}
 }
 
 type bstNode[K, T any] struct {
@@ -50,6 +63,25 @@ type bstNode[K, T any] struct {
 	t     T
 	left  *bstNode[K, T]
 	right *bstNode[K, T]
+	owner *bstOwner
+}
+
+func (this *BST[K, T]) newNode(key K, t T) *bstNode[K, T] {
+	return &bstNode[K, T]{key: key, t: t, owner: this.owner}
+}
+
+// writable returns a node that is safe for this tree to change
+// If node is shared with a snapshot or clone then a copy owned by this tree is returned
+// It is up to the caller to point the parent at the returned node
+func (this *BST[K, T]) writable(node *bstNode[K, T]) *bstNode[K, T] {
+	if node == nil || node.owner == this.owner {
+		return node
+	}
+
+	nodeCopy := *node
+	nodeCopy.owner = this.owner
+
+	return &nodeCopy
 }
 
 func (this *BST[K, T]) Insert(newT T) error {
@@ -57,13 +89,15 @@ func (this *BST[K, T]) Insert(newT T) error {
 
 	if this.root == nil {
 		// If we have no root then it is super easy as we just insert
-		this.root = &bstNode[K, T]{key: newKey, t: newT}
+		this.root = this.newNode(newKey, newT)
 		return nil
 	}
 
 	// So not as simple as we are going to need to compare
 	// Lets calcualte the the key once as we might have to search more than once
 
+	// Every node on the way down could be changed so they all need to be writable
+	this.root = this.writable(this.root)
 	curNode := this.root
 
 	// Going to delay creation of the node until we need it in case we have duplicate
@@ -74,18 +108,20 @@ func (this *BST[K, T]) Insert(newT T) error {
 			return fmt.Errorf("unable to insert duplicate T for key %v", newKey)
 		} else if curComp < 0 {
 			if curNode.left == nil {
-				curNode.left = &bstNode[K, T]{key: newKey, t: newT}
+				curNode.left = this.newNode(newKey, newT)
 				// We have completed insert so lets break
 				break
 			} else {
+				curNode.left = this.writable(curNode.left)
 				curNode = curNode.left
 			}
 		} else {
 			if curNode.right == nil {
-				curNode.right = &bstNode[K, T]{key: newKey, t: newT}
+				curNode.right = this.newNode(newKey, newT)
 				// We have completed insert so lets break
 				break
 			} else {
+				curNode.right = this.writable(curNode.right)
 				curNode = curNode.right
 			}
 		}
@@ -131,6 +167,8 @@ func (this *BST[K, T]) Remove(key K) error {
 	// Looking at preforming delete of the root first as that is an edge case
 	// First find the node to be deleted
 	var curNodeParent *bstNode[K, T] = nil
+	// Every node on the way down could be changed so they all need to be writable
+	this.root = this.writable(this.root)
 	curNode := this.root
 	for curNode != nil {
 		curComp := this.kCompFunc(key, curNode.key)
@@ -138,10 +176,12 @@ func (this *BST[K, T]) Remove(key K) error {
 		if curComp < 0 {
 			// Need to go left
 			curNodeParent = curNode
+			curNode.left = this.writable(curNode.left)
 			curNode = curNode.left
 		} else if curComp > 0 {
 			// Need to go right
 			curNodeParent = curNode
+			curNode.right = this.writable(curNode.right)
 			curNode = curNode.right
 
 		} else {
@@ -242,6 +282,8 @@ func (this *BST[K, T]) succeedDeletedNode2Children(nodeToDelete, nodeToBeDeleted
 }
 
 func (this *BST[K, T]) findSuccessor(nodeToBeDeleted *bstNode[K, T]) (successor, successorParent *bstNode[K, T]) {
+	// The successor and its parent are going to be changed so the path to them needs to be writable
+	nodeToBeDeleted.right = this.writable(nodeToBeDeleted.right)
 	successor = nodeToBeDeleted.right
 	successorParent = nodeToBeDeleted
 
@@ -249,6 +291,7 @@ func (this *BST[K, T]) findSuccessor(nodeToBeDeleted *bstNode[K, T]) (successor,
 	// Continue until we can't go left anymore. That last node is our
 	for successor != nil && successor.left != nil {
 		successorParent = successor
+		successor.left = this.writable(successor.left)
 		successor = successor.left
 	}
 
@@ -256,7 +299,17 @@ func (this *BST[K, T]) findSuccessor(nodeToBeDeleted *bstNode[K, T]) (successor,
 }
 
 func (this *BST[K, T]) Iterator() godatacollections.Iterator[T] {
-	iter := &bstIterator[K, T]{nodeStack: stack.NewLStack[*bstNode[K, T]](nil), zeroValue: this.zeroValue}
+	return this.newIterator()
+}
+
+// MutableIterator returns an iterator that walks the tree in sorted order and allows for
+// removing the item that was last returned by Next without breaking the iteration
+func (this *BST[K, T]) MutableIterator() godatacollections.MutableIterator[T] {
+	return this.newIterator()
+}
+
+func (this *BST[K, T]) newIterator() *bstIterator[K, T] {
+	iter := &bstIterator[K, T]{bst: this, nodeStack: stack.NewLStack[*bstNode[K, T]](nil), zeroValue: this.zeroValue}
 
 	next := this.root
 
@@ -271,8 +324,13 @@ func (this *BST[K, T]) Iterator() godatacollections.Iterator[T] {
 }
 
 type bstIterator[K, T any] struct {
+	bst       *BST[K, T]
 	nodeStack *stack.LStack[*bstNode[K, T]]
 	next      *bstNode[K, T]
+	// last is the node that was last returned by Next. Used for Remove
+	last *bstNode[K, T]
+	// end is the key that iteration stops at for range iterators. nil means no end
+	end       *K
 	zeroValue T
 }
 
@@ -288,6 +346,11 @@ func (this *bstIterator[K, T]) prepNext() {
 
 	// Save to ignore error as we are using a nil value for zero so we can tell when we have reached the end
 	this.next, _ = this.nodeStack.Pop()
+
+	if this.next != nil && this.end != nil && this.bst.kCompFunc(this.next.key, *this.end) >= 0 {
+		// Past the end of the range so there is nothing left
+		this.next = nil
+	}
 }
 
 func (this *bstIterator[K, T]) Next() (T, error) {
@@ -306,9 +369,43 @@ func (this *bstIterator[K, T]) Next() (T, error) {
 	}
 
 	retNext := this.next
+	this.last = retNext
 
 	// Need to prepare the next value
 	this.prepNext()
 
 	return retNext.t, nil
 }
+
+func (this *bstIterator[K, T]) Remove() error {
+	if this.last == nil {
+		return errors.New("unable to remove as there is no current item to remove")
+	}
+
+	removedKey := this.last.key
+	this.last = nil
+
+	if err := this.bst.Remove(removedKey); err != nil {
+		return err
+	}
+
+	// Removing a node can move nodes around (successor takes the deleted node's place) so the
+	// nodes in our stack can no longer be trusted. Rebuild the stack so that it holds the path
+	// to the first node with a key greater than the one we just removed
+	this.nodeStack = stack.NewLStack[*bstNode[K, T]](nil)
+
+	curNode := this.bst.root
+	for curNode != nil {
+		if this.bst.kCompFunc(removedKey, curNode.key) < 0 {
+			// Everything in this node and right of it comes after the removed key so it still needs visiting
+			this.nodeStack.Push(curNode)
+			curNode = curNode.left
+		} else {
+			curNode = curNode.right
+		}
+	}
+
+	this.prepNext()
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package tree
+
+import (
+	"math/bits"
+)
+
+// BSTBalance is a report of how balanced a BST is
+// Use it to decide when it is worth calling Rebalance
+type BSTBalance struct {
+	// Size is the number of items in the tree
+	Size int
+	// Height is the number of nodes on the longest path from the root to a leaf
+	Height int
+	// OptimalHeight is the smallest Height that a tree with Size items can have
+	OptimalHeight int
+	// RootBalanceFactor is the height of the root's left subtree minus the height of its right subtree
+	RootBalanceFactor int
+	// MaxBalanceFactor is the largest absolute balance factor of any node in the tree
+	MaxBalanceFactor int
+}
+
+// Height returns the number of nodes on the longest path from the root to a leaf
+// An empty tree has a height of 0
+func (this *BST[K, T]) Height() int {
+	return this.Balance().Height
+}
+
+// Balance walks the whole tree and reports on how balanced it is
+func (this *BST[K, T]) Balance() BSTBalance {
+	report := BSTBalance{}
+
+	if this.root == nil {
+		return report
+	}
+
+	// Walking post order with our own stack so that a degenerate tree doesn't blow up the call stack
+	type heightFrame struct {
+		node        *bstNode[K, T]
+		leftHeight  int
+		visitedLeft bool
+	}
+
+	frames := []*heightFrame{{node: this.root}}
+	// Height of the subtree that was last finished so that the parent frame can pick it up
+	finishedHeight := 0
+	// Set when the next frame to look at is returning from its right subtree
+	returningFromRight := false
+
+	for len(frames) > 0 {
+		curFrame := frames[len(frames)-1]
+
+		if !curFrame.visitedLeft {
+			curFrame.visitedLeft = true
+			if curFrame.node.left != nil {
+				frames = append(frames, &heightFrame{node: curFrame.node.left})
+				continue
+			}
+			finishedHeight = 0
+		}
+
+		if !returningFromRight {
+			// finishedHeight currently holds the height of the left subtree
+			curFrame.leftHeight = finishedHeight
+			finishedHeight = 0
+
+			if curFrame.node.right != nil {
+				frames = append(frames, &heightFrame{node: curFrame.node.right})
+				continue
+			}
+		}
+
+		leftHeight, rightHeight := curFrame.leftHeight, finishedHeight
+
+		balanceFactor := leftHeight - rightHeight
+		if balanceFactor < 0 {
+			balanceFactor = -balanceFactor
+		}
+		report.MaxBalanceFactor = max(report.MaxBalanceFactor, balanceFactor)
+		report.Size++
+
+		if curFrame.node == this.root {
+			report.RootBalanceFactor = leftHeight - rightHeight
+		}
+
+		finishedHeight = 1 + max(leftHeight, rightHeight)
+		frames = frames[:len(frames)-1]
+
+		// If we just finished the right child of the new top frame then it is ready to be finished too
+		returningFromRight = len(frames) > 0 && frames[len(frames)-1].node.right == curFrame.node
+	}
+
+	report.Height = finishedHeight
+	report.OptimalHeight = bits.Len(uint(report.Size))
+
+	return report
+}
+
+// Rebalance restructures the tree so that it is as balanced as possible
+// Uses the Day-Stout-Warren algorithm which is O(n) time and O(1) extra space
+func (this *BST[K, T]) Rebalance() {
+	// Pseudo root makes it so that rotations at the real root don't need special cases
+	pseudoRoot := &bstNode[K, T]{right: this.root}
+
+	size := this.treeToVine(pseudoRoot)
+	vineToTree(pseudoRoot, size)
+
+	this.root = pseudoRoot.right
+}
+
+// treeToVine rotates the tree into a linked list that only goes right (the vine) and returns the number of nodes in it
+// Every node passes through the vine so this is also where shared nodes get swapped for writable copies
+func (this *BST[K, T]) treeToVine(pseudoRoot *bstNode[K, T]) int {
+	tail := pseudoRoot
+	tail.right = this.writable(tail.right)
+	rest := tail.right
+	size := 0
+
+	for rest != nil {
+		if rest.left == nil {
+			// Nothing on the left so this node is in its final place in the vine
+			tail = rest
+			rest.right = this.writable(rest.right)
+			rest = rest.right
+			size++
+		} else {
+			// Rotate right so that the left child moves up into the vine
+			rest.left = this.writable(rest.left)
+			leftChild := rest.left
+			rest.left = leftChild.right
+			leftChild.right = rest
+			rest = leftChild
+			tail.right = leftChild
+		}
+	}
+
+	return size
+}
+
+// vineToTree turns the vine back into a balanced tree through rounds of left rotations
+func vineToTree[K, T any](pseudoRoot *bstNode[K, T], size int) {
+	// Number of nodes that won't fit into a perfect tree. They end up as the bottom level
+	leaves := size + 1 - (1 << (bits.Len(uint(size+1)) - 1))
+	compressVine(pseudoRoot, leaves)
+
+	size -= leaves
+	for size > 1 {
+		size /= 2
+		compressVine(pseudoRoot, size)
+	}
+}
+
+// compressVine does count left rotations on every other node down the vine
+func compressVine[K, T any](pseudoRoot *bstNode[K, T], count int) {
+	scanner := pseudoRoot
+
+	for i := 0; i < count; i++ {
+		child := scanner.right
+		scanner.right = child.right
+		scanner = scanner.right
+		child.right = scanner.left
+		scanner.left = child
+	}
+}
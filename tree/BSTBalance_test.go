@@ -0,0 +1,124 @@
+package tree
+
+import (
+	"testing"
+)
+
+func TestHeightOfEmptyTreeIsZero(t *testing.T) {
+	bst := intBST(-1)
+
+	if bst.Height() != 0 {
+		t.Fail()
+	}
+}
+
+func TestHeightOfDegenerateTreeIsSize(t *testing.T) {
+	bst := intBST(-1)
+
+	for i := 0; i < 100; i++ {
+		bst.Insert(i)
+	}
+
+	if h := bst.Height(); h != 100 {
+		t.Fatalf("expected height of 100 but got %d", h)
+	}
+}
+
+func TestBalanceReportsOnMixedTree(t *testing.T) {
+	bst := intBST(-1)
+
+	//       5
+	//     3   8
+	//    1      9
+	//            10
+	for _, curVal := range []int{5, 3, 8, 1, 9, 10} {
+		bst.Insert(curVal)
+	}
+
+	report := bst.Balance()
+
+	if report.Size != 6 {
+		t.Fatalf("expected size of 6 but got %d", report.Size)
+	}
+
+	if report.Height != 4 {
+		t.Fatalf("expected height of 4 but got %d", report.Height)
+	}
+
+	if report.OptimalHeight != 3 {
+		t.Fatalf("expected optimal height of 3 but got %d", report.OptimalHeight)
+	}
+
+	if report.RootBalanceFactor != -1 {
+		t.Fatalf("expected root balance factor of -1 but got %d", report.RootBalanceFactor)
+	}
+
+	if report.MaxBalanceFactor != 2 {
+		t.Fatalf("expected max balance factor of 2 but got %d", report.MaxBalanceFactor)
+	}
+}
+
+func TestRebalanceOfDegenerateTreeReachesOptimalHeight(t *testing.T) {
+	for _, size := range []int{1, 2, 3, 7, 8, 100, 1000} {
+		bst := intBST(-1)
+
+		for i := 0; i < size; i++ {
+			bst.Insert(i)
+		}
+
+		bst.Rebalance()
+
+		report := bst.Balance()
+		if report.Height != report.OptimalHeight {
+			t.Fatalf("expected size %d to have height %d but got %d", size, report.OptimalHeight, report.Height)
+		}
+
+		if report.MaxBalanceFactor > 1 {
+			t.Fatalf("expected size %d to have max balance factor of 1 but got %d", size, report.MaxBalanceFactor)
+		}
+
+		i := 0
+		for iter := bst.Iterator(); iter.HasNext(); i++ {
+			curVal, _ := iter.Next()
+			if curVal != i {
+				t.Fatalf("expected %d but got %d", i, curVal)
+			}
+		}
+
+		if i != size {
+			t.Fatalf("expected to iterate over %d items but got %d", size, i)
+		}
+	}
+}
+
+func TestRebalanceOfEmptyTreeStaysEmpty(t *testing.T) {
+	bst := intBST(-1)
+
+	bst.Rebalance()
+
+	if bst.root != nil {
+		t.Fail()
+	}
+}
+
+func TestTreeStillWorksAfterRebalance(t *testing.T) {
+	bst := intBST(-1)
+
+	for i := 100; i > 0; i-- {
+		bst.Insert(i)
+	}
+
+	bst.Rebalance()
+
+	if bst.Insert(50) == nil {
+		t.Fatal("expected duplicate insert to fail after rebalance")
+	}
+
+	if err := bst.Remove(50); err != nil {
+		t.Fatal(err)
+	}
+
+	if bst.Contains(50) || !bst.Contains(49) || !bst.Contains(51) {
+		t.Fail()
+	}
+}
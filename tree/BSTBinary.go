@@ -0,0 +1,223 @@
+package tree
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// bstBinaryMagic is written at the start of every binary encoded tree so that garbage input is caught early
+var bstBinaryMagic = []byte("GDCBST1")
+
+// GobEncode encodes the items of the tree in sorted order using encoding/gob
+// T must be something that gob is able to encode
+func (this *BST[K, T]) GobEncode() ([]byte, error) {
+	ts := make([]T, 0)
+
+	for iter := this.Iterator(); iter.HasNext(); {
+		curT, err := iter.Next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		ts = append(ts, curT)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := gob.NewEncoder(buf).Encode(ts); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode replaces the contents of the tree with items encoded by GobEncode
+// Just like UnmarshalJSON the tree must have been made with NewBST as it needs kCompFunc and tToKFunc
+func (this *BST[K, T]) GobDecode(data []byte) error {
+	if this.kCompFunc == nil || this.tToKFunc == nil {
+		return errors.New("unable to gob decode into BST that was not made with NewBST")
+	}
+
+	var ts []T
+
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&ts); err != nil {
+		return err
+	}
+
+	decoded, err := NewBSTFromSorted(this.kCompFunc, this.tToKFunc, this.zeroValue, ts)
+
+	if err != nil {
+		return err
+	}
+
+	this.root = decoded.root
+	this.owner = decoded.owner
+
+	return nil
+}
+
+// BSTEncoder writes trees to a stream in a compact binary format
+// The format is a magic header followed by each item in sorted order as a uvarint length prefix and the bytes from the codec
+// The items are ended with a zero byte, which is why each length prefix is the length plus one
+type BSTEncoder[K, T any] struct {
+	w     *bufio.Writer
+	codec godatacollections.Codec[T]
+}
+
+// NewBSTEncoder creates a new encoder that writes to w using codec to turn each item into bytes
+func NewBSTEncoder[K, T any](w io.Writer, codec godatacollections.Codec[T]) (*BSTEncoder[K, T], error) {
+	if w == nil {
+		return nil, errors.New("unable to create BSTEncoder without a writer")
+	}
+
+	if codec == nil {
+		return nil, errors.New("unable to create BSTEncoder without a codec")
+	}
+
+	return &BSTEncoder[K, T]{w: bufio.NewWriter(w), codec: codec}, nil
+}
+
+// Encode writes every item of sorted to the stream
+// sorted must iterate in sorted order which BST, BSTSnapshot and PBST all do
+// Encoding a BSTSnapshot allows for writers to keep changing the tree while it is being encoded
+func (this *BSTEncoder[K, T]) Encode(sorted godatacollections.ReadOnlySet[K, T]) error {
+	if _, err := this.w.Write(bstBinaryMagic); err != nil {
+		return err
+	}
+
+	lenBuf := make([]byte, binary.MaxVarintLen64)
+	iter := sorted.Iterator()
+	defer iter.Close()
+
+	for iter.HasNext() {
+		curT, err := iter.Next()
+
+		if err != nil {
+			return err
+		}
+
+		data, err := this.codec.Encode(curT)
+
+		if err != nil {
+			return err
+		}
+
+		lenBytes := binary.PutUvarint(lenBuf, uint64(len(data))+1)
+
+		if _, err = this.w.Write(lenBuf[:lenBytes]); err != nil {
+			return err
+		}
+
+		if _, err = this.w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	// End of items marker
+	if err := this.w.WriteByte(0); err != nil {
+		return err
+	}
+
+	return this.w.Flush()
+}
+
+// BSTDecoder reads trees written by BSTEncoder from a stream
+type BSTDecoder[K, T any] struct {
+	r          *bufio.Reader
+	codec      godatacollections.Codec[T]
+	kCompFunc  func(K, K) int
+	tToKFunc   func(T) K
+	tZeroValue T
+}
+
+// NewBSTDecoder creates a new decoder that reads from r using codec to turn bytes back into items
+// kCompFunc, tToKFunc and tZeroValue are the same as for NewBST
+func NewBSTDecoder[K, T any](r io.Reader, codec godatacollections.Codec[T], kCompFunc func(K, K) int, tToKFunc func(T) K, tZeroValue T) (*BSTDecoder[K, T], error) {
+	if r == nil {
+		return nil, errors.New("unable to create BSTDecoder without a reader")
+	}
+
+	if codec == nil {
+		return nil, errors.New("unable to create BSTDecoder without a codec")
+	}
+
+	if kCompFunc == nil {
+		return nil, errors.New("unable to create BSTDecoder without a function to compare Keys")
+	}
+
+	if tToKFunc == nil {
+		return nil, errors.New("unable to create BSTDecoder without a function to convert T to a Key")
+	}
+
+	return &BSTDecoder[K, T]{r: bufio.NewReader(r), codec: codec, kCompFunc: kCompFunc, tToKFunc: tToKFunc, tZeroValue: tZeroValue}, nil
+}
+
+// Decode reads the next tree from the stream
+// Since items are written in sorted order the tree is built balanced in O(n)
+// An error is returned if the items are not in order
+func (this *BSTDecoder[K, T]) Decode() (*BST[K, T], error) {
+	magic := make([]byte, len(bstBinaryMagic))
+
+	if _, err := io.ReadFull(this.r, magic); err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(magic, bstBinaryMagic) {
+		return nil, errors.New("unable to decode BST as stream is not a binary encoded BST")
+	}
+
+	ts := make([]T, 0)
+	// data only grows as bytes actually arrive so a corrupt length can't force a huge allocation up front
+	var data bytes.Buffer
+
+	for {
+		lenPlusOne, err := binary.ReadUvarint(this.r)
+
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		if lenPlusOne == 0 {
+			break
+		}
+
+		dataLen := lenPlusOne - 1
+		if dataLen > math.MaxInt64 {
+			return nil, fmt.Errorf("unable to decode item %d with length %d", len(ts), dataLen)
+		}
+
+		data.Reset()
+
+		if _, err = io.CopyN(&data, this.r, int64(dataLen)); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		curT, err := this.codec.Decode(data.Bytes())
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode item %d: %w", len(ts), err)
+		}
+
+		ts = append(ts, curT)
+	}
+
+	return NewBSTFromSorted(this.kCompFunc, this.tToKFunc, this.tZeroValue, ts)
+}
+
+// unexpectedEOF turns a plain EOF into ErrUnexpectedEOF as running out of stream in the middle of a tree is not a clean end
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
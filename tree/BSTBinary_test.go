@@ -0,0 +1,188 @@
+package tree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+func intCodec() godatacollections.Codec[int] {
+	return godatacollections.CodecFuncs[int]{
+		EncodeFunc: func(i int) ([]byte, error) {
+			return binary.AppendVarint(nil, int64(i)), nil
+		},
+		DecodeFunc: func(data []byte) (int, error) {
+			i, n := binary.Varint(data)
+			if n <= 0 {
+				return 0, errors.New("bad varint")
+			}
+			return int(i), nil
+		},
+	}
+}
+
+func intBSTDecoder(r io.Reader) *BSTDecoder[int, int] {
+	dec, _ := NewBSTDecoder(r, intCodec(), func(a, b int) int { return a - b }, func(a int) int { return a }, -1)
+
+	return dec
+}
+
+func TestBSTGobRoundTrip(t *testing.T) {
+	bst := intBST(-1)
+
+	for _, curVal := range []int{5, 3, 8, 1, 4} {
+		bst.Insert(curVal)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := gob.NewEncoder(buf).Encode(bst); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := intBST(-1)
+	decoded.Insert(100)
+
+	if err := gob.NewDecoder(buf).Decode(decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	expectValues(t, bstValues(decoded), 1, 3, 4, 5, 8)
+}
+
+func TestBSTGobDecodeWithoutFuncsReturnsError(t *testing.T) {
+	data, _ := intBST(-1).GobEncode()
+
+	if err := (&BST[int, int]{}).GobDecode(data); err == nil {
+		t.Fail()
+	}
+}
+
+func TestBSTBinaryRoundTrip(t *testing.T) {
+	source, _ := NewBSTFromSorted(func(a, b int) int { return a - b }, func(a int) int { return a }, -1, sortedInts(1000))
+
+	buf := &bytes.Buffer{}
+	enc, err := NewBSTEncoder[int](buf, intCodec())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = enc.Encode(source); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := intBSTDecoder(buf).Decode()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectValues(t, bstValues(decoded), sortedInts(1000)...)
+
+	if decoded.Height() != decoded.Balance().OptimalHeight {
+		t.Fatal("expected decoded tree to be balanced")
+	}
+}
+
+func TestBSTBinaryStreamsMultipleTreesAndSnapshots(t *testing.T) {
+	first := intBST(-1)
+	first.Insert(1)
+	first.Insert(2)
+
+	snapshot := first.Snapshot()
+	first.Insert(3)
+
+	buf := &bytes.Buffer{}
+	enc, _ := NewBSTEncoder[int](buf, intCodec())
+
+	enc.Encode(snapshot)
+	enc.Encode(intBST(-1))
+	enc.Encode(first)
+
+	dec := intBSTDecoder(buf)
+
+	decoded, err := dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectValues(t, bstValues(decoded), 1, 2)
+
+	decoded, err = dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectValues(t, bstValues(decoded))
+
+	decoded, err = dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectValues(t, bstValues(decoded), 1, 2, 3)
+
+	if _, err = dec.Decode(); err != io.EOF {
+		t.Fatalf("expected EOF at end of stream but got %v", err)
+	}
+}
+
+func TestBSTBinaryDecodeErrors(t *testing.T) {
+	if _, err := intBSTDecoder(bytes.NewReader([]byte("notatree"))).Decode(); err == nil {
+		t.Fatal("expected bad magic error")
+	}
+
+	buf := &bytes.Buffer{}
+	enc, _ := NewBSTEncoder[int](buf, intCodec())
+	enc.Encode(pbstOf(1, 2, 3))
+
+	truncated := buf.Bytes()[:buf.Len()-2]
+	if _, err := intBSTDecoder(bytes.NewReader(truncated)).Decode(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected unexpected EOF but got %v", err)
+	}
+
+	// Hand build a stream that is out of order
+	outOfOrder := append([]byte{}, bstBinaryMagic...)
+	for _, curVal := range []int{2, 1} {
+		data, _ := intCodec().Encode(curVal)
+		outOfOrder = binary.AppendUvarint(outOfOrder, uint64(len(data))+1)
+		outOfOrder = append(outOfOrder, data...)
+	}
+	outOfOrder = append(outOfOrder, 0)
+
+	if _, err := intBSTDecoder(bytes.NewReader(outOfOrder)).Decode(); err == nil {
+		t.Fatal("expected out of order error")
+	}
+
+	// A huge length with only a few bytes behind it fails on the missing bytes rather than allocating the length
+	for _, lenPlusOne := range []uint64{1 << 40, math.MaxUint64} {
+		hugeLen := binary.AppendUvarint(append([]byte{}, bstBinaryMagic...), lenPlusOne)
+		hugeLen = append(hugeLen, 1, 2, 3)
+
+		if _, err := intBSTDecoder(bytes.NewReader(hugeLen)).Decode(); err == nil {
+			t.Fatalf("expected error for item length %d", lenPlusOne-1)
+		}
+	}
+}
+
+func TestNewBSTEncoderAndDecoderReturnErrorIfMissingArgs(t *testing.T) {
+	if _, err := NewBSTEncoder[int, int](nil, intCodec()); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewBSTEncoder[int, int](&bytes.Buffer{}, nil); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewBSTDecoder(&bytes.Buffer{}, intCodec(), nil, func(a int) int { return a }, -1); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewBSTDecoder[int, int](&bytes.Buffer{}, nil, func(a, b int) int { return a - b }, func(a int) int { return a }, -1); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,77 @@
+package tree
+
+import (
+	"fmt"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// NewBSTFromSorted creates a new Binary Search Tree that is perfectly balanced from items that are already sorted
+// Building this way is O(n) and avoids the degenerate linear tree that comes from calling Insert with sorted items
+// kCompFunc, tToKFunc and tZeroValue are the same as for NewBST
+// sortedTs must be in strictly ascending order by key
+//
+//	An error is returned if an item is out of order or if two items resolve to the same key
+func NewBSTFromSorted[K, T any](kCompFunc func(K, K) int, tToKFunc func(T) K, tZeroValue T, sortedTs []T) (*BST[K, T], error) {
+	bst, err := NewBST(kCompFunc, tToKFunc, tZeroValue)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]K, len(sortedTs))
+
+	for i, curT := range sortedTs {
+		keys[i] = tToKFunc(curT)
+
+		if i == 0 {
+			continue
+		}
+
+		curComp := kCompFunc(keys[i-1], keys[i])
+		if curComp == 0 {
+			return nil, fmt.Errorf("unable to build BST with duplicate T for key %v", keys[i])
+		} else if curComp > 0 {
+			return nil, fmt.Errorf("unable to build BST as key %v is out of order after key %v", keys[i], keys[i-1])
+		}
+	}
+
+	bst.root = buildBalancedBSTNodes(keys, sortedTs, bst.owner)
+
+	return bst, nil
+}
+
+// NewBSTFromSortedIterator is the same as NewBSTFromSorted but pulls the sorted items from an Iterator
+// The iterator is read until it has no next item but it is up to the caller to Close it
+func NewBSTFromSortedIterator[K, T any](kCompFunc func(K, K) int, tToKFunc func(T) K, tZeroValue T, sortedIter godatacollections.Iterator[T]) (*BST[K, T], error) {
+	sortedTs := make([]T, 0)
+
+	for sortedIter.HasNext() {
+		curT, err := sortedIter.Next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		sortedTs = append(sortedTs, curT)
+	}
+
+	return NewBSTFromSorted(kCompFunc, tToKFunc, tZeroValue, sortedTs)
+}
+
+// buildBalancedBSTNodes takes the middle item as the root and builds each side from the halves around it
+func buildBalancedBSTNodes[K, T any](keys []K, ts []T, owner *bstOwner) *bstNode[K, T] {
+	if len(ts) == 0 {
+		return nil
+	}
+
+	mid := len(ts) / 2
+
+	return &bstNode[K, T]{
+		key:   keys[mid],
+		t:     ts[mid],
+		left:  buildBalancedBSTNodes(keys[:mid], ts[:mid], owner),
+		right: buildBalancedBSTNodes(keys[mid+1:], ts[mid+1:], owner),
+		owner: owner,
+	}
+}
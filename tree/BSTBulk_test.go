@@ -0,0 +1,123 @@
+package tree
+
+import (
+	"testing"
+)
+
+func sortedInts(n int) []int {
+	values := make([]int, n)
+
+	for i := range values {
+		values[i] = i
+	}
+
+	return values
+}
+
+func bstNodeHeight[K, T any](node *bstNode[K, T]) int {
+	if node == nil {
+		return 0
+	}
+
+	return 1 + max(bstNodeHeight(node.left), bstNodeHeight(node.right))
+}
+
+func TestNewBSTFromSortedContainsAllItemsInOrder(t *testing.T) {
+	values := sortedInts(1000)
+
+	bst, err := NewBSTFromSorted(func(a, b int) int { return a - b }, func(a int) int { return a }, -1, values)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i := 0
+	for iter := bst.Iterator(); iter.HasNext(); i++ {
+		curVal, _ := iter.Next()
+
+		if curVal != values[i] {
+			t.Fatalf("expected %d but got %d", values[i], curVal)
+		}
+	}
+
+	if i != len(values) {
+		t.Fail()
+	}
+}
+
+func TestNewBSTFromSortedIsBalanced(t *testing.T) {
+	// 1023 items fills a perfect tree of height 10
+	bst, _ := NewBSTFromSorted(func(a, b int) int { return a - b }, func(a int) int { return a }, -1, sortedInts(1023))
+
+	if h := bstNodeHeight(bst.root); h != 10 {
+		t.Fatalf("expected height of 10 but got %d", h)
+	}
+}
+
+func TestNewBSTFromSortedWithNoItemsIsEmpty(t *testing.T) {
+	bst, err := NewBSTFromSorted(func(a, b int) int { return a - b }, func(a int) int { return a }, -1, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bst.root != nil {
+		t.Fail()
+	}
+}
+
+func TestNewBSTFromSortedReturnsErrorForOutOfOrder(t *testing.T) {
+	bst, err := NewBSTFromSorted(func(a, b int) int { return a - b }, func(a int) int { return a }, -1, []int{1, 3, 2})
+
+	if err == nil {
+		t.Fail()
+	}
+
+	if bst != nil {
+		t.Fail()
+	}
+}
+
+func TestNewBSTFromSortedReturnsErrorForDuplicates(t *testing.T) {
+	bst, err := NewBSTFromSorted(func(a, b int) int { return a - b }, func(a int) int { return a }, -1, []int{1, 2, 2, 3})
+
+	if err == nil {
+		t.Fail()
+	}
+
+	if bst != nil {
+		t.Fail()
+	}
+}
+
+func TestNewBSTFromSortedReturnsErrorIfMissingFuncs(t *testing.T) {
+	_, err := NewBSTFromSorted[int, int](nil, func(a int) int { return a }, -1, []int{1})
+
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestNewBSTFromSortedIteratorBuildsFromOtherTree(t *testing.T) {
+	source := intBST(-1)
+
+	for _, curVal := range []int{5, 3, 8, 1, 4, 7, 9} {
+		source.Insert(curVal)
+	}
+
+	bst, err := NewBSTFromSortedIterator(func(a, b int) int { return a - b }, func(a int) int { return a }, -1, source.Iterator())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, curVal := range []int{1, 3, 4, 5, 7, 8, 9} {
+		if !bst.Contains(curVal) {
+			t.Fatalf("expected tree to contain %d", curVal)
+		}
+	}
+
+	if h := bstNodeHeight(bst.root); h != 3 {
+		t.Fatalf("expected height of 3 but got %d", h)
+	}
+}
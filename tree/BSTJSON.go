@@ -0,0 +1,86 @@
+package tree
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// MarshalJSON writes the tree as a JSON array of its items in sorted order
+func (this *BST[K, T]) MarshalJSON() ([]byte, error) {
+	return marshalIteratorJSON(this.Iterator())
+}
+
+// UnmarshalJSON replaces the contents of the tree with the items in a JSON array
+// The tree must have been made with NewBST (or one of the other constructors) as it needs kCompFunc and tToKFunc
+// If you don't already have a tree use UnmarshalBSTJSON instead
+// The array does not need to be sorted but an error is returned if two items resolve to the same key
+func (this *BST[K, T]) UnmarshalJSON(data []byte) error {
+	if this.kCompFunc == nil || this.tToKFunc == nil {
+		return errors.New("unable to unmarshal into BST that was not made with NewBST, use UnmarshalBSTJSON instead")
+	}
+
+	decoded, err := UnmarshalBSTJSON(data, this.kCompFunc, this.tToKFunc, this.zeroValue)
+
+	if err != nil {
+		return err
+	}
+
+	this.root = decoded.root
+	this.owner = decoded.owner
+
+	return nil
+}
+
+// UnmarshalBSTJSON creates a new balanced BST from a JSON array of items
+// kCompFunc, tToKFunc and tZeroValue are the same as for NewBST
+// The array does not need to be sorted but an error is returned if two items resolve to the same key
+func UnmarshalBSTJSON[K, T any](data []byte, kCompFunc func(K, K) int, tToKFunc func(T) K, tZeroValue T) (*BST[K, T], error) {
+	if kCompFunc == nil || tToKFunc == nil {
+		// Let NewBST give the more specific error
+		return NewBST(kCompFunc, tToKFunc, tZeroValue)
+	}
+
+	var ts []T
+
+	if err := json.Unmarshal(data, &ts); err != nil {
+		return nil, err
+	}
+
+	// Marshaled trees are already sorted but hand edited JSON might not be
+	sort.SliceStable(ts, func(i, j int) bool {
+		return kCompFunc(tToKFunc(ts[i]), tToKFunc(ts[j])) < 0
+	})
+
+	return NewBSTFromSorted(kCompFunc, tToKFunc, tZeroValue, ts)
+}
+
+// MarshalJSON writes the snapshot as a JSON array of its items in sorted order
+func (this *BSTSnapshot[K, T]) MarshalJSON() ([]byte, error) {
+	return marshalIteratorJSON(this.Iterator())
+}
+
+// MarshalJSON writes this version of the tree as a JSON array of its items in sorted order
+func (this *PBST[K, T]) MarshalJSON() ([]byte, error) {
+	return marshalIteratorJSON(this.Iterator())
+}
+
+func marshalIteratorJSON[T any](iter godatacollections.Iterator[T]) ([]byte, error) {
+	defer iter.Close()
+
+	ts := make([]T, 0)
+
+	for iter.HasNext() {
+		curT, err := iter.Next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		ts = append(ts, curT)
+	}
+
+	return json.Marshal(ts)
+}
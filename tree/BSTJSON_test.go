@@ -0,0 +1,126 @@
+package tree
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type employee struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func employeeBST() *BST[int, employee] {
+	bst, _ := NewBST(func(a, b int) int { return a - b }, func(e employee) int { return e.ID }, employee{})
+
+	return bst
+}
+
+func TestBSTMarshalJSONIsSorted(t *testing.T) {
+	bst := employeeBST()
+
+	bst.Insert(employee{ID: 3, Name: "c"})
+	bst.Insert(employee{ID: 1, Name: "a"})
+	bst.Insert(employee{ID: 2, Name: "b"})
+
+	data, err := json.Marshal(bst)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[{"id":1,"name":"a"},{"id":2,"name":"b"},{"id":3,"name":"c"}]`
+	if string(data) != expected {
+		t.Fatalf("expected %s but got %s", expected, data)
+	}
+}
+
+func TestBSTUnmarshalJSONIntoExistingTree(t *testing.T) {
+	bst := employeeBST()
+	bst.Insert(employee{ID: 100})
+
+	// Out of order on purpose
+	err := json.Unmarshal([]byte(`[{"id":3,"name":"c"},{"id":1,"name":"a"},{"id":2,"name":"b"}]`), bst)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bst.Contains(100) {
+		t.Fatal("expected existing items to be replaced")
+	}
+
+	if bst.GetByKey(2).Name != "b" {
+		t.Fail()
+	}
+
+	ids := make([]int, 0)
+	for _, curEmployee := range bstValues(bst) {
+		ids = append(ids, curEmployee.ID)
+	}
+	expectValues(t, ids, 1, 2, 3)
+
+	// Should still be a usable tree
+	if err := bst.Insert(employee{ID: 4}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBSTUnmarshalJSONWithoutFuncsReturnsError(t *testing.T) {
+	bst := &BST[int, employee]{}
+
+	if err := json.Unmarshal([]byte(`[]`), bst); err == nil {
+		t.Fail()
+	}
+}
+
+func TestUnmarshalBSTJSONRoundTrip(t *testing.T) {
+	source, _ := NewBSTFromSorted(func(a, b int) int { return a - b }, func(a int) int { return a }, -1, sortedInts(100))
+
+	data, _ := json.Marshal(source)
+
+	decoded, err := UnmarshalBSTJSON(data, func(a, b int) int { return a - b }, func(a int) int { return a }, -1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectValues(t, bstValues(decoded), sortedInts(100)...)
+
+	if decoded.Height() != decoded.Balance().OptimalHeight {
+		t.Fatal("expected decoded tree to be balanced")
+	}
+}
+
+func TestUnmarshalBSTJSONErrors(t *testing.T) {
+	compFunc := func(a, b int) int { return a - b }
+	toKFunc := func(a int) int { return a }
+
+	if _, err := UnmarshalBSTJSON([]byte(`[1,2,1]`), compFunc, toKFunc, -1); err == nil {
+		t.Fatal("expected duplicate error")
+	}
+
+	if _, err := UnmarshalBSTJSON([]byte(`[1,`), compFunc, toKFunc, -1); err == nil {
+		t.Fatal("expected syntax error")
+	}
+
+	if _, err := UnmarshalBSTJSON[int, int]([]byte(`[]`), nil, toKFunc, -1); err == nil {
+		t.Fatal("expected missing func error")
+	}
+}
+
+func TestSnapshotAndPBSTMarshalJSON(t *testing.T) {
+	bst := intBST(-1)
+	bst.Insert(2)
+	bst.Insert(1)
+
+	data, _ := json.Marshal(bst.Snapshot())
+	if string(data) != "[1,2]" {
+		t.Fatalf("unexpected snapshot JSON %s", data)
+	}
+
+	data, _ = json.Marshal(pbstOf(5, 4))
+	if string(data) != "[4,5]" {
+		t.Fatalf("unexpected PBST JSON %s", data)
+	}
+}
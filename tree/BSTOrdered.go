@@ -0,0 +1,108 @@
+package tree
+
+import (
+	"github.com/ZacharyDuve/godatacollections"
+	"github.com/ZacharyDuve/godatacollections/stack"
+)
+
+// Min returns the item with the smallest key
+// Returns zero value and EmptyError if the tree is empty
+func (this *BST[K, T]) Min() (T, error) {
+	if this.root == nil {
+		return this.zeroValue, godatacollections.EmptyError()
+	}
+
+	curNode := this.root
+	for curNode.left != nil {
+		curNode = curNode.left
+	}
+
+	return curNode.t, nil
+}
+
+// Max returns the item with the largest key
+// Returns zero value and EmptyError if the tree is empty
+func (this *BST[K, T]) Max() (T, error) {
+	if this.root == nil {
+		return this.zeroValue, godatacollections.EmptyError()
+	}
+
+	curNode := this.root
+	for curNode.right != nil {
+		curNode = curNode.right
+	}
+
+	return curNode.t, nil
+}
+
+// Floor returns the item with the largest key that is <= key
+// Returns zero value and NotFoundError if every key is greater
+func (this *BST[K, T]) Floor(key K) (T, error) {
+	var best *bstNode[K, T]
+	curNode := this.root
+
+	for curNode != nil {
+		curComp := this.kCompFunc(key, curNode.key)
+		if curComp == 0 {
+			return curNode.t, nil
+		} else if curComp < 0 {
+			curNode = curNode.left
+		} else {
+			// This node is a candidate but there could be a closer one to the right
+			best = curNode
+			curNode = curNode.right
+		}
+	}
+
+	if best == nil {
+		return this.zeroValue, godatacollections.NotFoundError()
+	}
+
+	return best.t, nil
+}
+
+// Ceiling returns the item with the smallest key that is >= key
+// Returns zero value and NotFoundError if every key is less
+func (this *BST[K, T]) Ceiling(key K) (T, error) {
+	var best *bstNode[K, T]
+	curNode := this.root
+
+	for curNode != nil {
+		curComp := this.kCompFunc(key, curNode.key)
+		if curComp == 0 {
+			return curNode.t, nil
+		} else if curComp > 0 {
+			curNode = curNode.right
+		} else {
+			// This node is a candidate but there could be a closer one to the left
+			best = curNode
+			curNode = curNode.left
+		}
+	}
+
+	if best == nil {
+		return this.zeroValue, godatacollections.NotFoundError()
+	}
+
+	return best.t, nil
+}
+
+// RangeIterator returns an iterator over the items with keys >= start and < end in sorted order
+func (this *BST[K, T]) RangeIterator(start, end K) godatacollections.Iterator[T] {
+	iter := &bstIterator[K, T]{bst: this, nodeStack: stack.NewLStack[*bstNode[K, T]](nil), end: &end, zeroValue: this.zeroValue}
+
+	curNode := this.root
+	for curNode != nil {
+		if this.kCompFunc(start, curNode.key) <= 0 {
+			// This node is in range (as far as start goes) so it needs visiting after its left side
+			iter.nodeStack.Push(curNode)
+			curNode = curNode.left
+		} else {
+			curNode = curNode.right
+		}
+	}
+
+	iter.prepNext()
+
+	return iter
+}
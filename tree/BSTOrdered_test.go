@@ -0,0 +1,96 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+func evenBST() *BST[int, int] {
+	bst := intBST(-1)
+
+	for _, curVal := range []int{10, 4, 16, 2, 8, 12, 18, 6, 14} {
+		bst.Insert(curVal)
+	}
+
+	return bst
+}
+
+func TestBSTImplementsOrderedSet(t *testing.T) {
+	var _ godatacollections.OrderedSet[int, int] = intBST(0)
+}
+
+func TestBSTMinAndMax(t *testing.T) {
+	bst := evenBST()
+
+	if minVal, err := bst.Min(); err != nil || minVal != 2 {
+		t.Fatalf("expected min of 2 but got %d %v", minVal, err)
+	}
+
+	if maxVal, err := bst.Max(); err != nil || maxVal != 18 {
+		t.Fatalf("expected max of 18 but got %d %v", maxVal, err)
+	}
+}
+
+func TestBSTMinAndMaxOnEmptyReturnEmptyError(t *testing.T) {
+	bst := intBST(-1)
+
+	if minVal, err := bst.Min(); !godatacollections.IsEmptyError(err) || minVal != -1 {
+		t.Fail()
+	}
+
+	if maxVal, err := bst.Max(); !godatacollections.IsEmptyError(err) || maxVal != -1 {
+		t.Fail()
+	}
+}
+
+func TestBSTFloor(t *testing.T) {
+	bst := evenBST()
+
+	for key, expected := range map[int]int{2: 2, 3: 2, 9: 8, 10: 10, 11: 10, 15: 14, 100: 18} {
+		if floorVal, err := bst.Floor(key); err != nil || floorVal != expected {
+			t.Fatalf("expected floor of %d to be %d but got %d %v", key, expected, floorVal, err)
+		}
+	}
+
+	if floorVal, err := bst.Floor(1); !godatacollections.IsNotFoundError(err) || floorVal != -1 {
+		t.Fatal("expected not found for floor below min")
+	}
+}
+
+func TestBSTCeiling(t *testing.T) {
+	bst := evenBST()
+
+	for key, expected := range map[int]int{-5: 2, 2: 2, 3: 4, 9: 10, 11: 12, 17: 18, 18: 18} {
+		if ceilingVal, err := bst.Ceiling(key); err != nil || ceilingVal != expected {
+			t.Fatalf("expected ceiling of %d to be %d but got %d %v", key, expected, ceilingVal, err)
+		}
+	}
+
+	if ceilingVal, err := bst.Ceiling(19); !godatacollections.IsNotFoundError(err) || ceilingVal != -1 {
+		t.Fatal("expected not found for ceiling above max")
+	}
+}
+
+func TestBSTRangeIterator(t *testing.T) {
+	bst := evenBST()
+
+	expectValues(t, iterValues(bst.RangeIterator(5, 13)), 6, 8, 10, 12)
+	expectValues(t, iterValues(bst.RangeIterator(4, 12)), 4, 6, 8, 10)
+	expectValues(t, iterValues(bst.RangeIterator(-10, 3)), 2)
+	expectValues(t, iterValues(bst.RangeIterator(18, 100)), 18)
+	expectValues(t, iterValues(bst.RangeIterator(7, 7)))
+	expectValues(t, iterValues(bst.RangeIterator(19, 100)))
+	expectValues(t, iterValues(intBST(-1).RangeIterator(0, 10)))
+}
+
+func iterValues(iter godatacollections.Iterator[int]) []int {
+	values := make([]int, 0)
+
+	for iter.HasNext() {
+		curVal, _ := iter.Next()
+		values = append(values, curVal)
+	}
+
+	return values
+}
@@ -0,0 +1,52 @@
+package tree
+
+import (
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// BSTSnapshot is a read only point in time view of a BST
+// Changes made to the tree after the snapshot was taken have no effect on the snapshot
+type BSTSnapshot[K, T any] struct {
+	bst *BST[K, T]
+}
+
+// Snapshot returns a read only view of the tree as it is right now in O(1)
+// No nodes are copied up front. Instead the tree stops owning its current nodes so that
+// later changes copy the nodes on the path they change (copy-on-write) and leave the snapshot's alone
+func (this *BST[K, T]) Snapshot() *BSTSnapshot[K, T] {
+	frozen := this.newEmptyLike()
+	frozen.root = this.root
+
+	// New owner for the tree means every current node is now treated as shared
+	this.owner = &bstOwner{}
+
+	return &BSTSnapshot[K, T]{bst: frozen}
+}
+
+// Clone returns a new independent tree with the same items in O(1)
+// Both trees share nodes until one of them changes them, at which point that tree copies the nodes it changes
+func (this *BST[K, T]) Clone() *BST[K, T] {
+	clone := this.newEmptyLike()
+	clone.root = this.root
+
+	this.owner = &bstOwner{}
+
+	return clone
+}
+
+func (this *BSTSnapshot[K, T]) Contains(key K) bool {
+	return this.bst.Contains(key)
+}
+
+func (this *BSTSnapshot[K, T]) GetByKey(key K) T {
+	return this.bst.GetByKey(key)
+}
+
+func (this *BSTSnapshot[K, T]) Iterator() godatacollections.Iterator[T] {
+	return this.bst.Iterator()
+}
+
+// Clone returns a new mutable tree starting with the items in the snapshot in O(1)
+func (this *BSTSnapshot[K, T]) Clone() *BST[K, T] {
+	return this.bst.Clone()
+}
@@ -0,0 +1,151 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+func TestBSTSnapshotImplementsReadOnlySet(t *testing.T) {
+	var _ godatacollections.ReadOnlySet[int, int] = intBST(0).Snapshot()
+}
+
+func TestSnapshotUnaffectedByInsertAndRemove(t *testing.T) {
+	bst := intBST(-1)
+
+	for _, curVal := range []int{50, 25, 75, 10, 30, 60, 90} {
+		bst.Insert(curVal)
+	}
+
+	snapshot := bst.Snapshot()
+
+	bst.Insert(27)
+	bst.Insert(95)
+	// Leaf, one child and two children removals
+	bst.Remove(10)
+	bst.Remove(90)
+	bst.Remove(25)
+	bst.Remove(50)
+
+	expectValues(t, bstValues(snapshot), 10, 25, 30, 50, 60, 75, 90)
+	expectValues(t, bstValues(bst), 27, 30, 60, 75, 95)
+
+	if !snapshot.Contains(50) || snapshot.Contains(27) {
+		t.Fail()
+	}
+
+	if snapshot.GetByKey(25) != 25 || snapshot.GetByKey(95) != -1 {
+		t.Fail()
+	}
+}
+
+func TestSnapshotIsO1AndSharesNodes(t *testing.T) {
+	bst := intBST(-1)
+
+	for _, curVal := range []int{50, 25, 75} {
+		bst.Insert(curVal)
+	}
+
+	snapshot := bst.Snapshot()
+
+	if snapshot.bst.root != bst.root {
+		t.Fatal("expected snapshot to share root until a change happens")
+	}
+
+	bst.Insert(80)
+
+	if snapshot.bst.root == bst.root {
+		t.Fatal("expected root to be copied on change")
+	}
+
+	if snapshot.bst.root.left != bst.root.left {
+		t.Fatal("expected unchanged left side to still be shared")
+	}
+}
+
+func TestSnapshotUnaffectedByRebalanceSplitAndIteratorRemove(t *testing.T) {
+	bst := intBST(-1)
+
+	for i := 0; i < 20; i++ {
+		bst.Insert(i)
+	}
+
+	beforeRebalance := bst.Snapshot()
+	bst.Rebalance()
+
+	beforeIterRemove := bst.Snapshot()
+	iter := bst.MutableIterator()
+	for iter.HasNext() {
+		curVal, _ := iter.Next()
+		if curVal%2 == 0 {
+			iter.Remove()
+		}
+	}
+
+	beforeSplit := bst.Snapshot()
+	less, greaterOrEqual := bst.Split(10)
+	less.Insert(100)
+	greaterOrEqual.Remove(11)
+
+	expectValues(t, bstValues(beforeRebalance), sortedInts(20)...)
+	expectValues(t, bstValues(beforeIterRemove), sortedInts(20)...)
+	expectValues(t, bstValues(beforeSplit), 1, 3, 5, 7, 9, 11, 13, 15, 17, 19)
+	expectValues(t, bstValues(less), 1, 3, 5, 7, 9, 100)
+	expectValues(t, bstValues(greaterOrEqual), 13, 15, 17, 19)
+
+	if beforeRebalance.bst.Height() != 20 {
+		t.Fatal("expected snapshot to keep its old shape")
+	}
+}
+
+func TestMultipleSnapshotsEachKeepTheirPointInTime(t *testing.T) {
+	bst := intBST(-1)
+
+	snapshots := make([]*BSTSnapshot[int, int], 0)
+	for i := 0; i < 10; i++ {
+		bst.Insert(i)
+		snapshots = append(snapshots, bst.Snapshot())
+	}
+
+	for i := 0; i < 10; i++ {
+		bst.Remove(i)
+	}
+
+	for i, curSnapshot := range snapshots {
+		expectValues(t, bstValues(curSnapshot), sortedInts(i+1)...)
+	}
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	bst := intBST(-1)
+
+	for _, curVal := range []int{5, 3, 8} {
+		bst.Insert(curVal)
+	}
+
+	clone := bst.Clone()
+
+	clone.Insert(4)
+	clone.Remove(5)
+	bst.Insert(9)
+	bst.Remove(3)
+
+	expectValues(t, bstValues(bst), 5, 8, 9)
+	expectValues(t, bstValues(clone), 3, 4, 8)
+}
+
+func TestCloneOfSnapshotIsMutable(t *testing.T) {
+	bst := intBST(-1)
+
+	bst.Insert(1)
+	bst.Insert(2)
+
+	snapshot := bst.Snapshot()
+	clone := snapshot.Clone()
+
+	clone.Insert(3)
+	clone.Remove(1)
+
+	expectValues(t, bstValues(snapshot), 1, 2)
+	expectValues(t, bstValues(clone), 2, 3)
+}
@@ -0,0 +1,113 @@
+package tree
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Split moves the items of the tree into two new trees
+// The first tree holds every item with a key less than key and the second holds every item with a key greater than or equal to key
+// Runs in O(height) as only the nodes on the search path for key are touched
+// For a balanced tree (see Rebalance or NewBSTFromSorted) that is O(log n)
+// Nodes are moved rather than copied so this tree is left empty afterwards
+func (this *BST[K, T]) Split(key K) (*BST[K, T], *BST[K, T]) {
+	less := this.newEmptyLike()
+	greaterOrEqual := this.newEmptyLike()
+	// Both sides take over ownership of this tree's nodes. They never share a node so they can share an owner
+	less.owner = this.owner
+	greaterOrEqual.owner = this.owner
+
+	// Hooks are where the next node for each side gets attached
+	lessHook := &less.root
+	greaterOrEqualHook := &greaterOrEqual.root
+
+	curNode := this.writable(this.root)
+	for curNode != nil {
+		if this.kCompFunc(curNode.key, key) < 0 {
+			// This node and everything left of it belongs on the less side
+			// Its right side still needs to be split though
+			*lessHook = curNode
+			lessHook = &curNode.right
+			curNode = this.writable(curNode.right)
+		} else {
+			// This node and everything right of it belongs on the greater side
+			// Its left side still needs to be split though
+			*greaterOrEqualHook = curNode
+			greaterOrEqualHook = &curNode.left
+			curNode = this.writable(curNode.left)
+		}
+	}
+
+	// Cut off the last links as those nodes were already handed to the other side
+	*lessHook = nil
+	*greaterOrEqualHook = nil
+
+	this.root = nil
+	this.owner = &bstOwner{}
+
+	return less, greaterOrEqual
+}
+
+// Join moves the items of left and right into a single new tree
+// Every key in left must be less than every key in right otherwise an error is returned
+// Runs in O(height) as only the smallest node of right gets moved to become the new root
+// Nodes are moved rather than copied so both left and right are left empty afterwards
+// The new tree uses left's functions
+func Join[K, T any](left, right *BST[K, T]) (*BST[K, T], error) {
+	if left == nil || right == nil {
+		return nil, errors.New("unable to join a nil BST")
+	}
+
+	if left.root != nil && right.root != nil {
+		leftMax := left.root
+		for leftMax.right != nil {
+			leftMax = leftMax.right
+		}
+
+		rightMin := right.root
+		for rightMin.left != nil {
+			rightMin = rightMin.left
+		}
+
+		if left.kCompFunc(leftMax.key, rightMin.key) >= 0 {
+			return nil, fmt.Errorf("unable to join BSTs as left key %v is not less than right key %v", leftMax.key, rightMin.key)
+		}
+	}
+
+	joined := left.newEmptyLike()
+	// Joined takes over ownership of left's nodes. Right's nodes will get copied if joined ever changes them
+	joined.owner = left.owner
+
+	if left.root == nil {
+		joined.root = right.root
+	} else if right.root == nil {
+		joined.root = left.root
+	} else {
+		// Pull the smallest node out of right and use it as the root between the two trees
+		var rightMinParent *bstNode[K, T]
+		right.root = right.writable(right.root)
+		rightMin := right.root
+		for rightMin.left != nil {
+			rightMinParent = rightMin
+			rightMin.left = right.writable(rightMin.left)
+			rightMin = rightMin.left
+		}
+
+		if rightMinParent == nil {
+			// Right's root was the smallest so there is nothing on its left to worry about
+			rightMin.left = left.root
+		} else {
+			rightMinParent.left = rightMin.right
+			rightMin.left = left.root
+			rightMin.right = right.root
+		}
+
+		joined.root = rightMin
+	}
+
+	left.root = nil
+	left.owner = &bstOwner{}
+	right.root = nil
+
+	return joined, nil
+}
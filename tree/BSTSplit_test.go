@@ -0,0 +1,162 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+func bstValues[K, T any](bst godatacollections.ReadOnlySet[K, T]) []T {
+	values := make([]T, 0)
+
+	for iter := bst.Iterator(); iter.HasNext(); {
+		curVal, _ := iter.Next()
+		values = append(values, curVal)
+	}
+
+	return values
+}
+
+func expectValues(t *testing.T, actual []int, expected ...int) {
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, actual)
+	}
+
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("expected %v but got %v", expected, actual)
+		}
+	}
+}
+
+func TestSplitSeparatesLessAndGreaterOrEqual(t *testing.T) {
+	bst := intBST(-1)
+
+	for _, curVal := range []int{50, 25, 75, 10, 30, 60, 90, 5, 27, 35, 55, 65} {
+		bst.Insert(curVal)
+	}
+
+	less, greaterOrEqual := bst.Split(30)
+
+	expectValues(t, bstValues(less), 5, 10, 25, 27)
+	expectValues(t, bstValues(greaterOrEqual), 30, 35, 50, 55, 60, 65, 75, 90)
+
+	if bst.root != nil {
+		t.Fatal("expected split tree to be left empty")
+	}
+
+	// Both sides should still be usable trees
+	if err := less.Insert(29); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := greaterOrEqual.Remove(50); err != nil {
+		t.Fatal(err)
+	}
+
+	expectValues(t, bstValues(less), 5, 10, 25, 27, 29)
+	expectValues(t, bstValues(greaterOrEqual), 30, 35, 55, 60, 65, 75, 90)
+}
+
+func TestSplitWithKeyOutsideRange(t *testing.T) {
+	bst := intBST(-1)
+
+	for _, curVal := range []int{2, 1, 3} {
+		bst.Insert(curVal)
+	}
+
+	less, greaterOrEqual := bst.Split(0)
+
+	expectValues(t, bstValues(less))
+	expectValues(t, bstValues(greaterOrEqual), 1, 2, 3)
+
+	less, greaterOrEqual = greaterOrEqual.Split(100)
+
+	expectValues(t, bstValues(less), 1, 2, 3)
+	expectValues(t, bstValues(greaterOrEqual))
+}
+
+func TestJoinCombinesTrees(t *testing.T) {
+	left := intBST(-1)
+	right := intBST(-1)
+
+	for _, curVal := range []int{5, 3, 8} {
+		left.Insert(curVal)
+	}
+
+	for _, curVal := range []int{20, 15, 30, 12, 17} {
+		right.Insert(curVal)
+	}
+
+	joined, err := Join(left, right)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectValues(t, bstValues(joined), 3, 5, 8, 12, 15, 17, 20, 30)
+
+	if left.root != nil || right.root != nil {
+		t.Fatal("expected joined trees to be left empty")
+	}
+}
+
+func TestJoinWithEmptySide(t *testing.T) {
+	left := intBST(-1)
+	right := intBST(-1)
+
+	right.Insert(1)
+
+	joined, err := Join(left, right)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectValues(t, bstValues(joined), 1)
+
+	joined, err = Join(joined, intBST(-1))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectValues(t, bstValues(joined), 1)
+}
+
+func TestJoinOverlappingTreesReturnsError(t *testing.T) {
+	left := intBST(-1)
+	right := intBST(-1)
+
+	left.Insert(1)
+	left.Insert(10)
+	right.Insert(5)
+
+	joined, err := Join(left, right)
+
+	if err == nil {
+		t.Fail()
+	}
+
+	if joined != nil {
+		t.Fail()
+	}
+
+	// Nothing should have been moved
+	expectValues(t, bstValues(left), 1, 10)
+	expectValues(t, bstValues(right), 5)
+}
+
+func TestSplitThenJoinRestoresItems(t *testing.T) {
+	bst, _ := NewBSTFromSorted(func(a, b int) int { return a - b }, func(a int) int { return a }, -1, sortedInts(100))
+
+	less, greaterOrEqual := bst.Split(42)
+
+	joined, err := Join(less, greaterOrEqual)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectValues(t, bstValues(joined), sortedInts(100)...)
+}
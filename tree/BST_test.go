@@ -450,3 +450,111 @@ func containsInSlice[T comparable](t T, ts []T) bool {
 
 	return false
 }
+
+// -------------------------------------- Mutable Iterator ------------------------------------------
+
+func TestMutableIteratorRemoveBeforeNextReturnsError(t *testing.T) {
+	bst := intBST(-1)
+
+	bst.Insert(1)
+
+	iter := bst.MutableIterator()
+
+	if iter.Remove() == nil {
+		t.Fail()
+	}
+}
+
+func TestMutableIteratorRemoveTwiceReturnsError(t *testing.T) {
+	bst := intBST(-1)
+
+	bst.Insert(1)
+	bst.Insert(2)
+
+	iter := bst.MutableIterator()
+	iter.Next()
+
+	if iter.Remove() != nil {
+		t.Fatal("expected first remove to succeed")
+	}
+
+	if iter.Remove() == nil {
+		t.Fail()
+	}
+}
+
+func TestMutableIteratorRemovingEvenValuesKeepsOddValuesInOrder(t *testing.T) {
+	bst := intBST(-1)
+
+	// Insert in a mixed order so that nodes with two children get removed
+	for _, curVal := range []int{50, 25, 75, 10, 30, 60, 90, 5, 15, 27, 35, 55, 65, 80, 95, 28, 26} {
+		bst.Insert(curVal)
+	}
+
+	iter := bst.MutableIterator()
+	visited := make([]int, 0)
+
+	for iter.HasNext() {
+		curVal, err := iter.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		visited = append(visited, curVal)
+
+		if curVal%2 == 0 {
+			if err := iter.Remove(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	expectedVisited := []int{5, 10, 15, 25, 26, 27, 28, 30, 35, 50, 55, 60, 65, 75, 80, 90, 95}
+	if len(visited) != len(expectedVisited) {
+		t.Fatalf("expected to visit %v but visited %v", expectedVisited, visited)
+	}
+	for i := range expectedVisited {
+		if visited[i] != expectedVisited[i] {
+			t.Fatalf("expected to visit %v but visited %v", expectedVisited, visited)
+		}
+	}
+
+	remaining := make([]int, 0)
+	for iter := bst.Iterator(); iter.HasNext(); {
+		curVal, _ := iter.Next()
+		remaining = append(remaining, curVal)
+	}
+
+	expectedRemaining := []int{5, 15, 25, 27, 35, 55, 65, 75, 95}
+	if len(remaining) != len(expectedRemaining) {
+		t.Fatalf("expected %v to remain but got %v", expectedRemaining, remaining)
+	}
+	for i := range expectedRemaining {
+		if remaining[i] != expectedRemaining[i] {
+			t.Fatalf("expected %v to remain but got %v", expectedRemaining, remaining)
+		}
+	}
+}
+
+func TestMutableIteratorRemovingEverythingEmptiesTree(t *testing.T) {
+	bst := intBST(-1)
+
+	for _, curVal := range []int{4, 2, 6, 1, 3, 5, 7} {
+		bst.Insert(curVal)
+	}
+
+	iter := bst.MutableIterator()
+	count := 0
+	for iter.HasNext() {
+		iter.Next()
+		iter.Remove()
+		count++
+	}
+
+	if count != 7 {
+		t.Fail()
+	}
+
+	if bst.root != nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,355 @@
+package tree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ZacharyDuve/godatacollections"
+	"github.com/ZacharyDuve/godatacollections/stack"
+)
+
+// Interval is the half open range [Start, End) of points of type P
+type Interval[P any] struct {
+	Start P
+	End   P
+}
+
+// IntervalTree stores items that each cover an Interval and finds every item overlapping a query quickly
+// It is a BST ordered by interval start where every node also tracks the largest End below it
+// so whole subtrees that end before a query can be skipped
+// Many items may have the same interval so each item also has a key K, and (Start, key) must be unique
+// Like BST it does no balancing
+type IntervalTree[K, P, T any] struct {
+	pCompFunc       func(P, P) int
+	kCompFunc       func(K, K) int
+	tToKFunc        func(T) K
+	tToIntervalFunc func(T) Interval[P]
+	zeroValue       T
+	root            *intervalNode[K, P, T]
+	size            int
+}
+
+type intervalNode[K, P, T any] struct {
+	key      K
+	interval Interval[P]
+	t        T
+	// maxEnd is the largest End of any interval in this node's subtree, including itself
+	maxEnd P
+	left   *intervalNode[K, P, T]
+	right  *intervalNode[K, P, T]
+}
+
+// NewIntervalTree creates a new empty IntervalTree
+// pCompFunc compares two points the same way kCompFunc does for NewBST
+// tToIntervalFunc returns the interval covered by an item
+// kCompFunc, tToKFunc and tZeroValue are the same as for NewBST
+func NewIntervalTree[K, P, T any](pCompFunc func(P, P) int, tToIntervalFunc func(T) Interval[P], kCompFunc func(K, K) int, tToKFunc func(T) K, tZeroValue T) (*IntervalTree[K, P, T], error) {
+	if pCompFunc == nil {
+		return nil, errors.New("unable to create IntervalTree without a function to compare Points")
+	}
+
+	if tToIntervalFunc == nil {
+		return nil, errors.New("unable to create IntervalTree without a function to convert T to an Interval")
+	}
+
+	if kCompFunc == nil {
+		return nil, errors.New("unable to create IntervalTree without a function to compare Keys")
+	}
+
+	if tToKFunc == nil {
+		return nil, errors.New("unable to create IntervalTree without a function to convert T to a Key")
+	}
+
+	return &IntervalTree[K, P, T]{pCompFunc: pCompFunc, tToIntervalFunc: tToIntervalFunc, kCompFunc: kCompFunc, tToKFunc: tToKFunc, zeroValue: tZeroValue}, nil
+}
+
+// Len returns the number of items in the tree
+func (this *IntervalTree[K, P, T]) Len() int {
+	return this.size
+}
+
+// compare orders nodes by interval start and then by key
+func (this *IntervalTree[K, P, T]) compare(start P, key K, node *intervalNode[K, P, T]) int {
+	startComp := this.pCompFunc(start, node.interval.Start)
+
+	if startComp != 0 {
+		return startComp
+	}
+
+	return this.kCompFunc(key, node.key)
+}
+
+func (this *IntervalTree[K, P, T]) maxP(a, b P) P {
+	if this.pCompFunc(a, b) >= 0 {
+		return a
+	}
+
+	return b
+}
+
+// updateMaxEnd recalculates node's maxEnd from its own interval and its children
+func (this *IntervalTree[K, P, T]) updateMaxEnd(node *intervalNode[K, P, T]) {
+	node.maxEnd = node.interval.End
+
+	if node.left != nil {
+		node.maxEnd = this.maxP(node.maxEnd, node.left.maxEnd)
+	}
+
+	if node.right != nil {
+		node.maxEnd = this.maxP(node.maxEnd, node.right.maxEnd)
+	}
+}
+
+// Insert adds newT to the tree
+// Returns an error if newT's interval is empty or there is already an item with the same start and key
+func (this *IntervalTree[K, P, T]) Insert(newT T) error {
+	newKey := this.tToKFunc(newT)
+	newInterval := this.tToIntervalFunc(newT)
+
+	if this.pCompFunc(newInterval.Start, newInterval.End) >= 0 {
+		return fmt.Errorf("unable to insert T for key %v as its interval is empty", newKey)
+	}
+
+	newNode := &intervalNode[K, P, T]{key: newKey, interval: newInterval, t: newT, maxEnd: newInterval.End}
+
+	if this.root == nil {
+		this.root = newNode
+		this.size++
+		return nil
+	}
+
+	// Check for a duplicate before changing any maxEnd on the way down
+	if this.find(newInterval.Start, newKey) != nil {
+		return fmt.Errorf("unable to insert duplicate T for key %v", newKey)
+	}
+
+	curNode := this.root
+
+	for {
+		curNode.maxEnd = this.maxP(curNode.maxEnd, newInterval.End)
+
+		if this.compare(newInterval.Start, newKey, curNode) < 0 {
+			if curNode.left == nil {
+				curNode.left = newNode
+				break
+			}
+			curNode = curNode.left
+		} else {
+			if curNode.right == nil {
+				curNode.right = newNode
+				break
+			}
+			curNode = curNode.right
+		}
+	}
+
+	this.size++
+
+	return nil
+}
+
+// Remove removes the item with the same interval start and key as t
+func (this *IntervalTree[K, P, T]) Remove(t T) error {
+	key := this.tToKFunc(t)
+	start := this.tToIntervalFunc(t).Start
+
+	// path holds the nodes above the one being removed so their maxEnd can be fixed afterwards
+	path := make([]*intervalNode[K, P, T], 0)
+	var parent *intervalNode[K, P, T]
+	curNode := this.root
+
+	for curNode != nil {
+		curComp := this.compare(start, key, curNode)
+		if curComp == 0 {
+			break
+		}
+
+		path = append(path, curNode)
+		parent = curNode
+
+		if curComp < 0 {
+			curNode = curNode.left
+		} else {
+			curNode = curNode.right
+		}
+	}
+
+	if curNode == nil {
+		return fmt.Errorf("unable to delete node with key %v due to it not existing in tree", key)
+	}
+
+	if curNode.left != nil && curNode.right != nil {
+		// Two children so move the successor's item into this node and remove the successor instead
+		path = append(path, curNode)
+		parent = curNode
+		successor := curNode.right
+
+		for successor.left != nil {
+			path = append(path, successor)
+			parent = successor
+			successor = successor.left
+		}
+
+		curNode.key = successor.key
+		curNode.interval = successor.interval
+		curNode.t = successor.t
+		curNode = successor
+	}
+
+	// curNode now has at most one child which takes its place
+	replacement := curNode.left
+	if replacement == nil {
+		replacement = curNode.right
+	}
+
+	if parent == nil {
+		this.root = replacement
+	} else if parent.left == curNode {
+		parent.left = replacement
+	} else {
+		parent.right = replacement
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		this.updateMaxEnd(path[i])
+	}
+
+	this.size--
+
+	return nil
+}
+
+// Contains returns if there is an item with the same interval start and key as t
+func (this *IntervalTree[K, P, T]) Contains(t T) bool {
+	return this.find(this.tToIntervalFunc(t).Start, this.tToKFunc(t)) != nil
+}
+
+func (this *IntervalTree[K, P, T]) find(start P, key K) *intervalNode[K, P, T] {
+	curNode := this.root
+
+	for curNode != nil {
+		curComp := this.compare(start, key, curNode)
+		if curComp == 0 {
+			return curNode
+		} else if curComp < 0 {
+			curNode = curNode.left
+		} else {
+			curNode = curNode.right
+		}
+	}
+
+	return nil
+}
+
+// Iterator returns an iterator over every item ordered by interval start and then key
+func (this *IntervalTree[K, P, T]) Iterator() godatacollections.Iterator[T] {
+	return this.newIterator(nil, nil, false)
+}
+
+// Overlapping returns an iterator over every item whose interval overlaps query, ordered by interval start and then key
+// Intervals are half open so [1, 3) and [3, 5) do not overlap. An empty query overlaps nothing
+func (this *IntervalTree[K, P, T]) Overlapping(query Interval[P]) godatacollections.Iterator[T] {
+	if this.pCompFunc(query.Start, query.End) >= 0 {
+		return this.newEmptyIterator()
+	}
+
+	return this.newIterator(&query.Start, &query.End, false)
+}
+
+// Stabbing returns an iterator over every item whose interval contains point, ordered by interval start and then key
+func (this *IntervalTree[K, P, T]) Stabbing(point P) godatacollections.Iterator[T] {
+	return this.newIterator(&point, &point, true)
+}
+
+func (this *IntervalTree[K, P, T]) newEmptyIterator() *intervalTreeIterator[K, P, T] {
+	return &intervalTreeIterator[K, P, T]{tree: this, nodeStack: stack.NewLStack[*intervalNode[K, P, T]](nil)}
+}
+
+// newIterator creates an iterator over the items with End > low and Start < high, or Start <= high if highInclusive
+// A nil low or high means no bound on that side
+func (this *IntervalTree[K, P, T]) newIterator(low, high *P, highInclusive bool) *intervalTreeIterator[K, P, T] {
+	iter := this.newEmptyIterator()
+	iter.low = low
+	iter.high = high
+	iter.highInclusive = highInclusive
+
+	iter.pushLeft(this.root)
+	iter.prepNext()
+
+	return iter
+}
+
+type intervalTreeIterator[K, P, T any] struct {
+	tree          *IntervalTree[K, P, T]
+	nodeStack     *stack.LStack[*intervalNode[K, P, T]]
+	next          *intervalNode[K, P, T]
+	low           *P
+	high          *P
+	highInclusive bool
+}
+
+// endsAfterLow returns if an interval or subtree ending at end reaches past low
+func (this *intervalTreeIterator[K, P, T]) endsAfterLow(end P) bool {
+	return this.low == nil || this.tree.pCompFunc(end, *this.low) > 0
+}
+
+// startsBeforeHigh returns if an interval starting at start begins before high
+func (this *intervalTreeIterator[K, P, T]) startsBeforeHigh(start P) bool {
+	if this.high == nil {
+		return true
+	}
+
+	startComp := this.tree.pCompFunc(start, *this.high)
+
+	return startComp < 0 || (this.highInclusive && startComp == 0)
+}
+
+// pushLeft pushes node and its left children, skipping any subtree where nothing ends after low
+func (this *intervalTreeIterator[K, P, T]) pushLeft(node *intervalNode[K, P, T]) {
+	for node != nil && this.endsAfterLow(node.maxEnd) {
+		this.nodeStack.Push(node)
+		node = node.left
+	}
+}
+
+// prepNext walks in order until it finds a node that matches
+// Stops as soon as a node starts at or after high as every node after it in order does too
+func (this *intervalTreeIterator[K, P, T]) prepNext() {
+	this.next = nil
+
+	for {
+		// Save to ignore error as we are using a nil value for zero so we can tell when we have reached the end
+		curNode, _ := this.nodeStack.Pop()
+
+		if curNode == nil || !this.startsBeforeHigh(curNode.interval.Start) {
+			return
+		}
+
+		this.pushLeft(curNode.right)
+
+		if this.endsAfterLow(curNode.interval.End) {
+			this.next = curNode
+			return
+		}
+	}
+}
+
+func (this *intervalTreeIterator[K, P, T]) Close() error {
+	return nil
+}
+
+func (this *intervalTreeIterator[K, P, T]) HasNext() bool {
+	return this.next != nil
+}
+
+func (this *intervalTreeIterator[K, P, T]) Next() (T, error) {
+	if this.next == nil {
+		return this.tree.zeroValue, errors.New("nothing left to iterate over")
+	}
+
+	retNext := this.next
+
+	this.prepNext()
+
+	return retNext.t, nil
+}
@@ -0,0 +1,233 @@
+package tree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+type reservation struct {
+	id       int
+	interval Interval[int]
+}
+
+func compInts(a, b int) int {
+	return a - b
+}
+
+func reservationTree() *IntervalTree[int, int, reservation] {
+	tree, _ := NewIntervalTree(compInts, func(r reservation) Interval[int] { return r.interval },
+		compInts, func(r reservation) int { return r.id }, reservation{id: -1})
+
+	return tree
+}
+
+func reservationIDs(iter godatacollections.Iterator[reservation]) []int {
+	ids := make([]int, 0)
+
+	for iter.HasNext() {
+		cur, _ := iter.Next()
+		ids = append(ids, cur.id)
+	}
+
+	return ids
+}
+
+func expectIDs(t *testing.T, actual []int, expected ...int) {
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, actual)
+	}
+
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("expected %v but got %v", expected, actual)
+		}
+	}
+}
+
+// checkMaxEnd makes sure every node's maxEnd is the largest End in its subtree
+func checkMaxEnd(t *testing.T, node *intervalNode[int, int, reservation]) int {
+	if node == nil {
+		return -1 << 31
+	}
+
+	expected := max(node.interval.End, checkMaxEnd(t, node.left), checkMaxEnd(t, node.right))
+
+	if node.maxEnd != expected {
+		t.Fatalf("node %d has maxEnd %d but expected %d", node.key, node.maxEnd, expected)
+	}
+
+	return expected
+}
+
+func TestNewIntervalTreeWithNilFuncsErrors(t *testing.T) {
+	toInterval := func(r reservation) Interval[int] { return r.interval }
+	toK := func(r reservation) int { return r.id }
+
+	if _, err := NewIntervalTree(nil, toInterval, compInts, toK, reservation{}); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewIntervalTree(compInts, nil, compInts, toK, reservation{}); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewIntervalTree(compInts, toInterval, nil, toK, reservation{}); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewIntervalTree(compInts, toInterval, compInts, nil, reservation{}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestIntervalTreeInsertErrors(t *testing.T) {
+	tree := reservationTree()
+
+	if tree.Insert(reservation{id: 1, interval: Interval[int]{5, 5}}) == nil {
+		t.Fatal("expected error inserting empty interval")
+	}
+
+	if tree.Insert(reservation{id: 1, interval: Interval[int]{5, 3}}) == nil {
+		t.Fatal("expected error inserting backwards interval")
+	}
+
+	tree.Insert(reservation{id: 1, interval: Interval[int]{1, 5}})
+
+	if tree.Insert(reservation{id: 1, interval: Interval[int]{1, 9}}) == nil {
+		t.Fatal("expected error inserting duplicate start and key")
+	}
+
+	// Same interval but different key is fine
+	if err := tree.Insert(reservation{id: 2, interval: Interval[int]{1, 5}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if tree.Len() != 2 {
+		t.Fail()
+	}
+
+	checkMaxEnd(t, tree.root)
+}
+
+func TestIntervalTreeOverlappingIsHalfOpen(t *testing.T) {
+	tree := reservationTree()
+
+	tree.Insert(reservation{id: 1, interval: Interval[int]{0, 10}})
+	tree.Insert(reservation{id: 2, interval: Interval[int]{10, 20}})
+	tree.Insert(reservation{id: 3, interval: Interval[int]{5, 15}})
+	tree.Insert(reservation{id: 4, interval: Interval[int]{20, 30}})
+
+	expectIDs(t, reservationIDs(tree.Overlapping(Interval[int]{10, 20})), 3, 2)
+	expectIDs(t, reservationIDs(tree.Overlapping(Interval[int]{0, 5})), 1)
+	expectIDs(t, reservationIDs(tree.Overlapping(Interval[int]{9, 11})), 1, 3, 2)
+	expectIDs(t, reservationIDs(tree.Overlapping(Interval[int]{30, 40})))
+	expectIDs(t, reservationIDs(tree.Overlapping(Interval[int]{12, 12})))
+
+	expectIDs(t, reservationIDs(tree.Stabbing(10)), 3, 2)
+	expectIDs(t, reservationIDs(tree.Stabbing(0)), 1)
+	expectIDs(t, reservationIDs(tree.Stabbing(29)), 4)
+	expectIDs(t, reservationIDs(tree.Stabbing(30)))
+	expectIDs(t, reservationIDs(tree.Stabbing(-1)))
+
+	expectIDs(t, reservationIDs(tree.Iterator()), 1, 3, 2, 4)
+}
+
+func TestIntervalTreeRemove(t *testing.T) {
+	tree := reservationTree()
+	r1 := reservation{id: 1, interval: Interval[int]{0, 100}}
+	r2 := reservation{id: 2, interval: Interval[int]{10, 20}}
+	r3 := reservation{id: 3, interval: Interval[int]{30, 40}}
+
+	tree.Insert(r2)
+	tree.Insert(r1)
+	tree.Insert(r3)
+
+	if err := tree.Remove(r1); err != nil {
+		t.Fatal(err)
+	}
+
+	checkMaxEnd(t, tree.root)
+
+	if tree.Contains(r1) || !tree.Contains(r2) || tree.Len() != 2 {
+		t.Fail()
+	}
+
+	// r1 was the only interval covering 50 so the maxEnd must have shrunk to prune it
+	expectIDs(t, reservationIDs(tree.Stabbing(50)))
+
+	if tree.Remove(r1) == nil {
+		t.Fatal("expected error removing missing item")
+	}
+}
+
+func TestIntervalTreeRandomAgainstBruteForce(t *testing.T) {
+	tree := reservationTree()
+	live := make(map[int]reservation)
+
+	for i := 0; i < 2000; i++ {
+		if len(live) > 0 && rand.Intn(3) == 0 {
+			for id, r := range live {
+				if err := tree.Remove(r); err != nil {
+					t.Fatal(err)
+				}
+				delete(live, id)
+				break
+			}
+		} else {
+			start := rand.Intn(1000)
+			r := reservation{id: i, interval: Interval[int]{start, start + 1 + rand.Intn(50)}}
+			if err := tree.Insert(r); err != nil {
+				t.Fatal(err)
+			}
+			live[i] = r
+		}
+	}
+
+	checkMaxEnd(t, tree.root)
+
+	if tree.Len() != len(live) {
+		t.Fatalf("expected %d items but got %d", len(live), tree.Len())
+	}
+
+	for q := 0; q < 200; q++ {
+		qStart := rand.Intn(1100) - 50
+		query := Interval[int]{qStart, qStart + 1 + rand.Intn(60)}
+		point := rand.Intn(1100) - 50
+
+		expectedOverlap := make([]reservation, 0)
+		expectedStab := make([]reservation, 0)
+
+		for _, r := range live {
+			if r.interval.Start < query.End && query.Start < r.interval.End {
+				expectedOverlap = append(expectedOverlap, r)
+			}
+
+			if r.interval.Start <= point && point < r.interval.End {
+				expectedStab = append(expectedStab, r)
+			}
+		}
+
+		expectIDs(t, reservationIDs(tree.Overlapping(query)), sortedReservationIDs(expectedOverlap)...)
+		expectIDs(t, reservationIDs(tree.Stabbing(point)), sortedReservationIDs(expectedStab)...)
+	}
+}
+
+// sortedReservationIDs returns the ids in the same order as the tree iterates, start then id
+func sortedReservationIDs(rs []reservation) []int {
+	sort.Slice(rs, func(i, j int) bool {
+		if rs[i].interval.Start != rs[j].interval.Start {
+			return rs[i].interval.Start < rs[j].interval.Start
+		}
+		return rs[i].id < rs[j].id
+	})
+
+	ids := make([]int, len(rs))
+	for i := range rs {
+		ids[i] = rs[i].id
+	}
+
+	return ids
+}
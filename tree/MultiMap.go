@@ -0,0 +1,181 @@
+package tree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// MultiMap is an ordered collection like BST that allows many T for the same K
+// Items with the same key are kept in the order they were inserted
+// For a plain multiset (bag) of keys use the keys themselves as T with a tToKFunc that returns its argument
+type MultiMap[K, T any] struct {
+	tToKFunc  func(T) K
+	zeroValue T
+	// entries has one entry per distinct key holding every T for that key
+	entries *BST[K, *multiMapEntry[K, T]]
+	size    int
+}
+
+type multiMapEntry[K, T any] struct {
+	key K
+	ts  []T
+}
+
+// NewMultiMap creates a new empty MultiMap
+// Arguments are the same as for NewBST
+func NewMultiMap[K, T any](kCompFunc func(K, K) int, tToKFunc func(T) K, tZeroValue T) (*MultiMap[K, T], error) {
+	if kCompFunc == nil {
+		return nil, errors.New("unable to create MultiMap without a function to compare Keys")
+	}
+
+	if tToKFunc == nil {
+		return nil, errors.New("unable to create MultiMap without a function to convert T to a Key")
+	}
+
+	entries, err := NewBST(kCompFunc, func(entry *multiMapEntry[K, T]) K { return entry.key }, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MultiMap[K, T]{tToKFunc: tToKFunc, zeroValue: tZeroValue, entries: entries}, nil
+}
+
+// Len returns the total number of items, counting every item for a key
+func (this *MultiMap[K, T]) Len() int {
+	return this.size
+}
+
+// Insert adds newT after any other items with the same key
+func (this *MultiMap[K, T]) Insert(newT T) {
+	key := this.tToKFunc(newT)
+	entry := this.entries.GetByKey(key)
+
+	if entry == nil {
+		entry = &multiMapEntry[K, T]{key: key}
+		// Safe to ignore error as the key was just checked to not be there
+		this.entries.Insert(entry)
+	}
+
+	entry.ts = append(entry.ts, newT)
+	this.size++
+}
+
+// Contains returns if there is at least one item for key
+func (this *MultiMap[K, T]) Contains(key K) bool {
+	return this.entries.Contains(key)
+}
+
+// Count returns the number of items for key
+func (this *MultiMap[K, T]) Count(key K) int {
+	entry := this.entries.GetByKey(key)
+
+	if entry == nil {
+		return 0
+	}
+
+	return len(entry.ts)
+}
+
+// GetAll returns an iterator over every item for key in the order they were inserted
+// The iterator works on a copy so the MultiMap is free to change while iterating
+func (this *MultiMap[K, T]) GetAll(key K) godatacollections.Iterator[T] {
+	var ts []T
+
+	if entry := this.entries.GetByKey(key); entry != nil {
+		ts = append(ts, entry.ts...)
+	}
+
+	return &multiMapIterator[K, T]{current: ts, zeroValue: this.zeroValue}
+}
+
+// RemoveOne removes the earliest inserted item for key
+func (this *MultiMap[K, T]) RemoveOne(key K) error {
+	entry := this.entries.GetByKey(key)
+
+	if entry == nil {
+		return fmt.Errorf("unable to delete item with key %v due to it not existing in map", key)
+	}
+
+	if len(entry.ts) == 1 {
+		// Safe to ignore error as the entry was just found
+		this.entries.Remove(key)
+	} else {
+		// Clear the slot so the slice doesn't hold on to what it pointed at
+		entry.ts[0] = this.zeroValue
+		entry.ts = entry.ts[1:]
+	}
+
+	this.size--
+
+	return nil
+}
+
+// RemoveAll removes every item for key
+// Returns how many were removed, which is 0 if there were none
+func (this *MultiMap[K, T]) RemoveAll(key K) int {
+	entry := this.entries.GetByKey(key)
+
+	if entry == nil {
+		return 0
+	}
+
+	// Safe to ignore error as the entry was just found
+	this.entries.Remove(key)
+	this.size -= len(entry.ts)
+
+	return len(entry.ts)
+}
+
+// Iterator returns an iterator over every item in key order, with items for the same key in the order they were inserted
+// The MultiMap must not be changed while iterating
+func (this *MultiMap[K, T]) Iterator() godatacollections.Iterator[T] {
+	return &multiMapIterator[K, T]{entries: this.entries.Iterator(), zeroValue: this.zeroValue}
+}
+
+// RangeIterator is Iterator for only the items with keys >= start and < end
+func (this *MultiMap[K, T]) RangeIterator(start, end K) godatacollections.Iterator[T] {
+	return &multiMapIterator[K, T]{entries: this.entries.RangeIterator(start, end), zeroValue: this.zeroValue}
+}
+
+// multiMapIterator walks the items of each entry in turn
+type multiMapIterator[K, T any] struct {
+	// entries is nil when only iterating over current
+	entries   godatacollections.Iterator[*multiMapEntry[K, T]]
+	current   []T
+	zeroValue T
+}
+
+func (this *multiMapIterator[K, T]) Close() error {
+	if this.entries == nil {
+		return nil
+	}
+
+	return this.entries.Close()
+}
+
+func (this *multiMapIterator[K, T]) HasNext() bool {
+	// Every entry has at least one item so one more entry means one more item
+	return len(this.current) > 0 || (this.entries != nil && this.entries.HasNext())
+}
+
+func (this *multiMapIterator[K, T]) Next() (T, error) {
+	if len(this.current) == 0 {
+		if this.entries == nil || !this.entries.HasNext() {
+			return this.zeroValue, errors.New("nothing left to iterate over")
+		}
+
+		entry, err := this.entries.Next()
+		if err != nil {
+			return this.zeroValue, err
+		}
+
+		this.current = entry.ts
+	}
+
+	retNext := this.current[0]
+	this.current = this.current[1:]
+
+	return retNext, nil
+}
@@ -0,0 +1,144 @@
+package tree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+type event struct {
+	userID int
+	seq    int
+}
+
+func eventMultiMap() *MultiMap[int, event] {
+	multiMap, _ := NewMultiMap(compInts, func(e event) int { return e.userID }, event{userID: -1})
+
+	return multiMap
+}
+
+func eventSeqs(iter godatacollections.Iterator[event]) []int {
+	seqs := make([]int, 0)
+
+	for iter.HasNext() {
+		cur, _ := iter.Next()
+		seqs = append(seqs, cur.seq)
+	}
+
+	return seqs
+}
+
+func TestNewMultiMapWithNilFuncsErrors(t *testing.T) {
+	if _, err := NewMultiMap[int, event](nil, func(e event) int { return e.userID }, event{}); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewMultiMap[int, event](compInts, nil, event{}); err == nil {
+		t.Fail()
+	}
+}
+
+func TestMultiMapStoresDuplicatesInInsertOrder(t *testing.T) {
+	multiMap := eventMultiMap()
+
+	multiMap.Insert(event{userID: 2, seq: 1})
+	multiMap.Insert(event{userID: 1, seq: 2})
+	multiMap.Insert(event{userID: 2, seq: 3})
+	multiMap.Insert(event{userID: 3, seq: 4})
+	multiMap.Insert(event{userID: 2, seq: 5})
+
+	if multiMap.Len() != 5 || multiMap.Count(2) != 3 || multiMap.Count(1) != 1 || multiMap.Count(9) != 0 {
+		t.Fail()
+	}
+
+	if !multiMap.Contains(3) || multiMap.Contains(9) {
+		t.Fail()
+	}
+
+	expectValues(t, eventSeqs(multiMap.GetAll(2)), 1, 3, 5)
+	expectValues(t, eventSeqs(multiMap.GetAll(9)))
+	expectValues(t, eventSeqs(multiMap.Iterator()), 2, 1, 3, 5, 4)
+	expectValues(t, eventSeqs(multiMap.RangeIterator(2, 3)), 1, 3, 5)
+
+	if _, err := multiMap.GetAll(9).Next(); err == nil {
+		t.Fail()
+	}
+}
+
+func TestMultiMapRemoveOne(t *testing.T) {
+	multiMap := eventMultiMap()
+
+	multiMap.Insert(event{userID: 1, seq: 1})
+	multiMap.Insert(event{userID: 1, seq: 2})
+
+	if err := multiMap.RemoveOne(1); err != nil {
+		t.Fatal(err)
+	}
+
+	expectValues(t, eventSeqs(multiMap.GetAll(1)), 2)
+
+	multiMap.RemoveOne(1)
+
+	if multiMap.Contains(1) || multiMap.Len() != 0 {
+		t.Fatal("expected key to be gone after removing its last item")
+	}
+
+	if multiMap.RemoveOne(1) == nil {
+		t.Fatal("expected error removing missing key")
+	}
+}
+
+func TestMultiMapRemoveAll(t *testing.T) {
+	multiMap := eventMultiMap()
+
+	for seq := 0; seq < 4; seq++ {
+		multiMap.Insert(event{userID: seq % 2, seq: seq})
+	}
+
+	if removed := multiMap.RemoveAll(0); removed != 2 {
+		t.Fatalf("expected 2 removed but got %d", removed)
+	}
+
+	if multiMap.RemoveAll(0) != 0 || multiMap.Len() != 2 {
+		t.Fail()
+	}
+
+	expectValues(t, eventSeqs(multiMap.Iterator()), 1, 3)
+}
+
+func TestMultiMapGetAllIsACopy(t *testing.T) {
+	multiMap := eventMultiMap()
+
+	multiMap.Insert(event{userID: 1, seq: 1})
+	multiMap.Insert(event{userID: 1, seq: 2})
+
+	iter := multiMap.GetAll(1)
+	multiMap.RemoveOne(1)
+	multiMap.Insert(event{userID: 1, seq: 3})
+
+	expectValues(t, eventSeqs(iter), 1, 2)
+}
+
+func TestMultiMapAsBag(t *testing.T) {
+	bag, _ := NewMultiMap(compInts, func(i int) int { return i }, -1)
+	counts := make(map[int]int)
+	values := make([]int, 0)
+
+	for i := 0; i < 300; i++ {
+		v := rand.Intn(20)
+		bag.Insert(v)
+		counts[v]++
+		values = append(values, v)
+	}
+
+	sort.Ints(values)
+	expectValues(t, iterValues(bag.Iterator()), values...)
+
+	for v, count := range counts {
+		if bag.Count(v) != count {
+			t.Fatalf("expected count %d for %d but got %d", count, v, bag.Count(v))
+		}
+	}
+}
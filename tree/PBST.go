@@ -0,0 +1,235 @@
+package tree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ZacharyDuve/godatacollections"
+	"github.com/ZacharyDuve/godatacollections/stack"
+)
+
+// PBST is a persistent (immutable) Binary Search Tree
+// Insert and Remove don't change the tree they are called on, they return a new version of the tree instead
+// The new version shares every node that wasn't on the path to the change with the old version
+// so making a new version only costs O(height) new nodes
+// Since a version can never change it is safe to hand out to readers without copying or locking
+type PBST[K, T any] struct {
+	kCompFunc func(K, K) int
+	tToKFunc  func(T) K
+	zeroValue T
+	root      *pbstNode[K, T]
+	size      int
+}
+
+// NewPBST creates a new empty Persistent Binary Search Tree
+// Arguments are the same as for NewBST
+func NewPBST[K, T any](kCompFunc func(K, K) int, tToKFunc func(T) K, tZeroValue T) (*PBST[K, T], error) {
+	if kCompFunc == nil {
+		return nil, errors.New("unable to create PBST without a function to compare Keys")
+	}
+
+	if tToKFunc == nil {
+		return nil, errors.New("unable to create PBST without a function to convert T to a Key")
+	}
+
+	return &PBST[K, T]{kCompFunc: kCompFunc, tToKFunc: tToKFunc, zeroValue: tZeroValue}, nil
+}
+
+// pbstNode must never be changed once it is part of a version as other versions could be sharing it
+type pbstNode[K, T any] struct {
+	key   K
+	t     T
+	left  *pbstNode[K, T]
+	right *pbstNode[K, T]
+}
+
+// pbstPathStep is a node on the way down to a change and which way we went from it
+type pbstPathStep[K, T any] struct {
+	node     *pbstNode[K, T]
+	wentLeft bool
+}
+
+// newVersion makes a new version with the same functions but a different root
+func (this *PBST[K, T]) newVersion(root *pbstNode[K, T], size int) *PBST[K, T] {
+	return &PBST[K, T]{kCompFunc: this.kCompFunc, tToKFunc: this.tToKFunc, zeroValue: this.zeroValue, root: root, size: size}
+}
+
+// copyPath copies every node in path from the bottom up, pointing each copy at the new child below it
+// Returns the new root
+func copyPath[K, T any](path []pbstPathStep[K, T], newChild *pbstNode[K, T]) *pbstNode[K, T] {
+	for i := len(path) - 1; i >= 0; i-- {
+		nodeCopy := *path[i].node
+
+		if path[i].wentLeft {
+			nodeCopy.left = newChild
+		} else {
+			nodeCopy.right = newChild
+		}
+
+		newChild = &nodeCopy
+	}
+
+	return newChild
+}
+
+// Insert returns a new version of the tree that also has newT in it
+// An error is returned for a duplicate key, in which case no new version is made
+func (this *PBST[K, T]) Insert(newT T) (*PBST[K, T], error) {
+	newKey := this.tToKFunc(newT)
+
+	path := make([]pbstPathStep[K, T], 0)
+	curNode := this.root
+
+	for curNode != nil {
+		curComp := this.kCompFunc(newKey, curNode.key)
+		if curComp == 0 {
+			return nil, fmt.Errorf("unable to insert duplicate T for key %v", newKey)
+		}
+
+		path = append(path, pbstPathStep[K, T]{node: curNode, wentLeft: curComp < 0})
+
+		if curComp < 0 {
+			curNode = curNode.left
+		} else {
+			curNode = curNode.right
+		}
+	}
+
+	newRoot := copyPath(path, &pbstNode[K, T]{key: newKey, t: newT})
+
+	return this.newVersion(newRoot, this.size+1), nil
+}
+
+// Remove returns a new version of the tree without the item that resolves to key
+// An error is returned if there is no item for key, in which case no new version is made
+func (this *PBST[K, T]) Remove(key K) (*PBST[K, T], error) {
+	path := make([]pbstPathStep[K, T], 0)
+	curNode := this.root
+
+	for curNode != nil {
+		curComp := this.kCompFunc(key, curNode.key)
+		if curComp == 0 {
+			break
+		}
+
+		path = append(path, pbstPathStep[K, T]{node: curNode, wentLeft: curComp < 0})
+
+		if curComp < 0 {
+			curNode = curNode.left
+		} else {
+			curNode = curNode.right
+		}
+	}
+
+	if curNode == nil {
+		return nil, fmt.Errorf("unable to delete node with key %v due to it not existing in tree", key)
+	}
+
+	var replacement *pbstNode[K, T]
+
+	if curNode.left == nil {
+		replacement = curNode.right
+	} else if curNode.right == nil {
+		replacement = curNode.left
+	} else {
+		// Two children so the successor takes the removed node's place
+		// Need a copy of the right subtree that no longer has the successor in it
+		successorPath := make([]pbstPathStep[K, T], 0)
+		successor := curNode.right
+
+		for successor.left != nil {
+			successorPath = append(successorPath, pbstPathStep[K, T]{node: successor, wentLeft: true})
+			successor = successor.left
+		}
+
+		newRight := copyPath(successorPath, successor.right)
+
+		replacement = &pbstNode[K, T]{key: successor.key, t: successor.t, left: curNode.left, right: newRight}
+	}
+
+	newRoot := copyPath(path, replacement)
+
+	return this.newVersion(newRoot, this.size-1), nil
+}
+
+func (this *PBST[K, T]) Contains(key K) bool {
+	return this.find(key) != nil
+}
+
+func (this *PBST[K, T]) GetByKey(key K) T {
+	foundNode := this.find(key)
+
+	if foundNode == nil {
+		return this.zeroValue
+	}
+
+	return foundNode.t
+}
+
+func (this *PBST[K, T]) find(key K) *pbstNode[K, T] {
+	curNode := this.root
+
+	for curNode != nil {
+		curComp := this.kCompFunc(key, curNode.key)
+		if curComp == 0 {
+			return curNode
+		} else if curComp < 0 {
+			curNode = curNode.left
+		} else {
+			curNode = curNode.right
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of items in this version of the tree
+func (this *PBST[K, T]) Len() int {
+	return this.size
+}
+
+// Iterator returns an iterator over this version of the tree in sorted order
+// Later versions have no effect on the iterator
+func (this *PBST[K, T]) Iterator() godatacollections.Iterator[T] {
+	iter := &pbstIterator[K, T]{nodeStack: stack.NewLStack[*pbstNode[K, T]](nil), zeroValue: this.zeroValue}
+
+	iter.pushLeft(this.root)
+
+	return iter
+}
+
+type pbstIterator[K, T any] struct {
+	nodeStack *stack.LStack[*pbstNode[K, T]]
+	next      *pbstNode[K, T]
+	zeroValue T
+}
+
+func (this *pbstIterator[K, T]) pushLeft(node *pbstNode[K, T]) {
+	for node != nil {
+		this.nodeStack.Push(node)
+		node = node.left
+	}
+
+	// Save to ignore error as we are using a nil value for zero so we can tell when we have reached the end
+	this.next, _ = this.nodeStack.Pop()
+}
+
+func (this *pbstIterator[K, T]) Close() error {
+	return nil
+}
+
+func (this *pbstIterator[K, T]) HasNext() bool {
+	return this.next != nil
+}
+
+func (this *pbstIterator[K, T]) Next() (T, error) {
+	if this.next == nil {
+		return this.zeroValue, errors.New("nothing left to iterate over")
+	}
+
+	retNext := this.next
+
+	this.pushLeft(retNext.right)
+
+	return retNext.t, nil
+}
@@ -0,0 +1,166 @@
+package tree
+
+import (
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+func intPBST(zeroVal int) *PBST[int, int] {
+	pbst, _ := NewPBST(func(a, b int) int { return a - b }, func(a int) int { return a }, zeroVal)
+
+	return pbst
+}
+
+func pbstOf(values ...int) *PBST[int, int] {
+	pbst := intPBST(-1)
+
+	for _, curVal := range values {
+		pbst, _ = pbst.Insert(curVal)
+	}
+
+	return pbst
+}
+
+func TestPBSTImplementsReadOnlySet(t *testing.T) {
+	var _ godatacollections.ReadOnlySet[int, int] = intPBST(0)
+}
+
+func TestPBSTReturnsErrorIfMissingFuncs(t *testing.T) {
+	if _, err := NewPBST[int, int](nil, func(i int) int { return i }, -1); err == nil {
+		t.Fail()
+	}
+
+	if _, err := NewPBST[int, int](func(a, b int) int { return a - b }, nil, -1); err == nil {
+		t.Fail()
+	}
+}
+
+func TestPBSTInsertDoesNotChangeOldVersion(t *testing.T) {
+	v1 := pbstOf(5, 3, 8)
+
+	v2, err := v1.Insert(4)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v1.Contains(4) {
+		t.Fatal("expected old version to not have new item")
+	}
+
+	if !v2.Contains(4) {
+		t.Fatal("expected new version to have new item")
+	}
+
+	expectValues(t, bstValues(v1), 3, 5, 8)
+	expectValues(t, bstValues(v2), 3, 4, 5, 8)
+
+	if v1.Len() != 3 || v2.Len() != 4 {
+		t.Fail()
+	}
+}
+
+func TestPBSTInsertDuplicateReturnsError(t *testing.T) {
+	v1 := pbstOf(5)
+
+	v2, err := v1.Insert(5)
+
+	if err == nil {
+		t.Fail()
+	}
+
+	if v2 != nil {
+		t.Fail()
+	}
+}
+
+func TestPBSTRemoveDoesNotChangeOldVersion(t *testing.T) {
+	values := []int{50, 25, 75, 10, 30, 60, 90, 27, 35}
+	v1 := pbstOf(values...)
+
+	// Remove a leaf, a node with one child and a node with two children
+	for _, removeVal := range []int{10, 30, 50, 25} {
+		v2, err := v1.Remove(removeVal)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !v1.Contains(removeVal) {
+			t.Fatalf("expected old version to still have %d", removeVal)
+		}
+
+		if v2.Contains(removeVal) {
+			t.Fatalf("expected new version to not have %d", removeVal)
+		}
+
+		if v2.Len() != v1.Len()-1 {
+			t.Fail()
+		}
+
+		v1 = v2
+	}
+
+	expectValues(t, bstValues(v1), 27, 35, 60, 75, 90)
+}
+
+func TestPBSTRemoveMissingReturnsError(t *testing.T) {
+	v1 := pbstOf(1, 2)
+
+	v2, err := v1.Remove(3)
+
+	if err == nil {
+		t.Fail()
+	}
+
+	if v2 != nil {
+		t.Fail()
+	}
+}
+
+func TestPBSTSharesUnchangedNodes(t *testing.T) {
+	v1 := pbstOf(50, 25, 75)
+
+	v2, _ := v1.Insert(80)
+
+	// Only the right side path changed so the left side should be the exact same node
+	if v1.root.left != v2.root.left {
+		t.Fatal("expected left subtree to be shared")
+	}
+
+	if v1.root == v2.root || v1.root.right == v2.root.right {
+		t.Fatal("expected nodes on the path to be copies")
+	}
+}
+
+func TestPBSTIteratorUnaffectedByNewVersions(t *testing.T) {
+	v1 := pbstOf(1, 2, 3, 4)
+
+	iter := v1.Iterator()
+	iter.Next()
+
+	v2, _ := v1.Remove(3)
+	v2, _ = v2.Insert(10)
+
+	values := make([]int, 0)
+	for iter.HasNext() {
+		curVal, _ := iter.Next()
+		values = append(values, curVal)
+	}
+
+	expectValues(t, values, 2, 3, 4)
+	expectValues(t, bstValues(v2), 1, 2, 4, 10)
+}
+
+func TestPBSTGetByKey(t *testing.T) {
+	v1 := pbstOf(1, 2)
+
+	if v1.GetByKey(2) != 2 {
+		t.Fail()
+	}
+
+	if v1.GetByKey(3) != -1 {
+		t.Fail()
+	}
+}
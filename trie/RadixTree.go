@@ -0,0 +1,338 @@
+package trie
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ZacharyDuve/godatacollections"
+	"github.com/ZacharyDuve/godatacollections/stack"
+)
+
+// RadixTree is a compressed trie for string or []byte keys
+// Chains of nodes that only have one child are squashed into a single edge so the tree stays small
+// even for long keys. On top of the normal Set functions it can find every key with a given prefix
+// and the longest key that is a prefix of a given key
+// Keys are compared byte by byte so iteration is in lexicographic byte order
+type RadixTree[K ~string | ~[]byte, T any] struct {
+	tToKFunc  func(T) K
+	zeroValue T
+	// root always has an empty prefix and is never removed
+	root *radixNode[T]
+	size int
+}
+
+type radixNode[T any] struct {
+	// prefix is the part of the key on the edge leading into this node
+	prefix string
+	// children are sorted by the first byte of their prefix, which is unique among siblings
+	children []*radixNode[T]
+	hasValue bool
+	t        T
+}
+
+// NewRadixTree creates a new empty RadixTree
+// tToKFunc is how to get the key for an item of type T
+// tZeroValue is the value to return when there is nothing to return
+func NewRadixTree[K ~string | ~[]byte, T any](tToKFunc func(T) K, tZeroValue T) (*RadixTree[K, T], error) {
+	if tToKFunc == nil {
+		return nil, errors.New("unable to create RadixTree without a function to convert T to a Key")
+	}
+
+	return &RadixTree[K, T]{tToKFunc: tToKFunc, zeroValue: tZeroValue, root: &radixNode[T]{}}, nil
+}
+
+// Len returns the number of items in the tree
+func (this *RadixTree[K, T]) Len() int {
+	return this.size
+}
+
+// childIndex returns where the child starting with b is or would go in children and the child if it exists
+func (this *radixNode[T]) childIndex(b byte) (int, *radixNode[T]) {
+	index := sort.Search(len(this.children), func(i int) bool {
+		return this.children[i].prefix[0] >= b
+	})
+
+	if index < len(this.children) && this.children[index].prefix[0] == b {
+		return index, this.children[index]
+	}
+
+	return index, nil
+}
+
+func (this *radixNode[T]) addChild(index int, child *radixNode[T]) {
+	this.children = append(this.children, nil)
+	copy(this.children[index+1:], this.children[index:])
+	this.children[index] = child
+}
+
+func commonPrefixLen(a, b string) int {
+	i := 0
+
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
+
+func (this *RadixTree[K, T]) Insert(newT T) error {
+	newKey := this.tToKFunc(newT)
+	remaining := string(newKey)
+	curNode := this.root
+
+	for {
+		if remaining == "" {
+			if curNode.hasValue {
+				return fmt.Errorf("unable to insert duplicate T for key %q", string(newKey))
+			}
+
+			curNode.hasValue = true
+			curNode.t = newT
+			this.size++
+
+			return nil
+		}
+
+		childIndex, child := curNode.childIndex(remaining[0])
+
+		if child == nil {
+			curNode.addChild(childIndex, &radixNode[T]{prefix: remaining, hasValue: true, t: newT})
+			this.size++
+
+			return nil
+		}
+
+		common := commonPrefixLen(remaining, child.prefix)
+
+		if common < len(child.prefix) {
+			// Key leaves the child's edge part way along so the edge needs splitting where they differ
+			splitNode := &radixNode[T]{prefix: child.prefix[:common], children: []*radixNode[T]{child}}
+			child.prefix = child.prefix[common:]
+			curNode.children[childIndex] = splitNode
+		}
+
+		remaining = remaining[common:]
+		curNode = curNode.children[childIndex]
+	}
+}
+
+func (this *RadixTree[K, T]) Remove(key K) error {
+	remaining := string(key)
+	var parent *radixNode[T]
+	curNode := this.root
+
+	for remaining != "" {
+		_, child := curNode.childIndex(remaining[0])
+
+		if child == nil || commonPrefixLen(remaining, child.prefix) < len(child.prefix) {
+			return fmt.Errorf("unable to delete item with key %q due to it not existing in tree", string(key))
+		}
+
+		remaining = remaining[len(child.prefix):]
+		parent = curNode
+		curNode = child
+	}
+
+	if !curNode.hasValue {
+		return fmt.Errorf("unable to delete item with key %q due to it not existing in tree", string(key))
+	}
+
+	curNode.hasValue = false
+	curNode.t = this.zeroValue
+	this.size--
+
+	if curNode == this.root {
+		return nil
+	}
+
+	if len(curNode.children) == 0 {
+		childIndex, _ := parent.childIndex(curNode.prefix[0])
+		parent.children = append(parent.children[:childIndex], parent.children[childIndex+1:]...)
+
+		// Parent may now be a valueless node with a single child which needs squashing too
+		if parent != this.root {
+			parent.mergeWithOnlyChild()
+		}
+	} else {
+		curNode.mergeWithOnlyChild()
+	}
+
+	return nil
+}
+
+// mergeWithOnlyChild squashes this node and its child into one if this node has no value and only one child
+func (this *radixNode[T]) mergeWithOnlyChild() {
+	if this.hasValue || len(this.children) != 1 {
+		return
+	}
+
+	child := this.children[0]
+	this.prefix += child.prefix
+	this.children = child.children
+	this.hasValue = child.hasValue
+	this.t = child.t
+}
+
+func (this *RadixTree[K, T]) Contains(key K) bool {
+	foundNode := this.find(string(key))
+
+	return foundNode != nil && foundNode.hasValue
+}
+
+func (this *RadixTree[K, T]) GetByKey(key K) T {
+	foundNode := this.find(string(key))
+
+	if foundNode == nil || !foundNode.hasValue {
+		return this.zeroValue
+	}
+
+	return foundNode.t
+}
+
+// find returns the node for exactly key, whether it has a value or not, or nil
+func (this *RadixTree[K, T]) find(key string) *radixNode[T] {
+	curNode := this.root
+
+	for key != "" {
+		_, child := curNode.childIndex(key[0])
+
+		if child == nil || commonPrefixLen(key, child.prefix) < len(child.prefix) {
+			return nil
+		}
+
+		key = key[len(child.prefix):]
+		curNode = child
+	}
+
+	return curNode
+}
+
+// LongestPrefixMatch returns the item whose key is the longest prefix of key, which includes key itself
+// Returns zero value and NotFoundError if no item's key is a prefix of key
+func (this *RadixTree[K, T]) LongestPrefixMatch(key K) (T, error) {
+	remaining := string(key)
+	curNode := this.root
+	var bestNode *radixNode[T]
+
+	for {
+		if curNode.hasValue {
+			bestNode = curNode
+		}
+
+		if remaining == "" {
+			break
+		}
+
+		_, child := curNode.childIndex(remaining[0])
+
+		if child == nil || commonPrefixLen(remaining, child.prefix) < len(child.prefix) {
+			break
+		}
+
+		remaining = remaining[len(child.prefix):]
+		curNode = child
+	}
+
+	if bestNode == nil {
+		return this.zeroValue, godatacollections.NotFoundError()
+	}
+
+	return bestNode.t, nil
+}
+
+// Iterator returns an iterator over every item in lexicographic key order
+func (this *RadixTree[K, T]) Iterator() godatacollections.Iterator[T] {
+	return this.newIterator(this.root)
+}
+
+// PrefixIterator returns an iterator over every item whose key starts with prefix in lexicographic key order
+func (this *RadixTree[K, T]) PrefixIterator(prefix K) godatacollections.Iterator[T] {
+	remaining := string(prefix)
+	curNode := this.root
+
+	for remaining != "" {
+		_, child := curNode.childIndex(remaining[0])
+
+		if child == nil {
+			return this.newIterator(nil)
+		}
+
+		common := commonPrefixLen(remaining, child.prefix)
+
+		if common == len(remaining) {
+			// Prefix ends part way along or at the end of this edge so everything below child matches
+			return this.newIterator(child)
+		}
+
+		if common < len(child.prefix) {
+			return this.newIterator(nil)
+		}
+
+		remaining = remaining[common:]
+		curNode = child
+	}
+
+	return this.newIterator(curNode)
+}
+
+func (this *RadixTree[K, T]) newIterator(start *radixNode[T]) *radixTreeIterator[T] {
+	iter := &radixTreeIterator[T]{nodeStack: stack.NewLStack[*radixNode[T]](nil), zeroValue: this.zeroValue}
+
+	if start != nil {
+		iter.nodeStack.Push(start)
+	}
+
+	iter.prepNext()
+
+	return iter
+}
+
+type radixTreeIterator[T any] struct {
+	nodeStack *stack.LStack[*radixNode[T]]
+	next      *radixNode[T]
+	zeroValue T
+}
+
+// prepNext walks the tree in pre-order until it finds a node with a value
+// Pre-order gives lexicographic order as a node's key is a prefix of, and so before, all keys under it
+func (this *radixTreeIterator[T]) prepNext() {
+	this.next = nil
+
+	for this.next == nil {
+		// Save to ignore error as we are using a nil value for zero so we can tell when we have reached the end
+		curNode, _ := this.nodeStack.Pop()
+
+		if curNode == nil {
+			return
+		}
+
+		for i := len(curNode.children) - 1; i >= 0; i-- {
+			this.nodeStack.Push(curNode.children[i])
+		}
+
+		if curNode.hasValue {
+			this.next = curNode
+		}
+	}
+}
+
+func (this *radixTreeIterator[T]) Close() error {
+	return nil
+}
+
+func (this *radixTreeIterator[T]) HasNext() bool {
+	return this.next != nil
+}
+
+func (this *radixTreeIterator[T]) Next() (T, error) {
+	if this.next == nil {
+		return this.zeroValue, errors.New("nothing left to iterate over")
+	}
+
+	retNext := this.next
+
+	this.prepNext()
+
+	return retNext.t, nil
+}
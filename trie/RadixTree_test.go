@@ -0,0 +1,255 @@
+package trie
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// RadixTree needs to be usable anywhere a Set is
+var _ godatacollections.Set[string, string] = &RadixTree[string, string]{}
+
+type route struct {
+	prefix []byte
+	name   string
+}
+
+func stringTree() *RadixTree[string, string] {
+	tree, _ := NewRadixTree(func(s string) string { return s }, "")
+
+	return tree
+}
+
+func stringTreeOf(values ...string) *RadixTree[string, string] {
+	tree := stringTree()
+
+	for _, v := range values {
+		tree.Insert(v)
+	}
+
+	return tree
+}
+
+func iterValues(iter godatacollections.Iterator[string]) []string {
+	values := make([]string, 0)
+
+	for iter.HasNext() {
+		curVal, _ := iter.Next()
+		values = append(values, curVal)
+	}
+
+	return values
+}
+
+func expectValues(t *testing.T, actual []string, expected ...string) {
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %q but got %q", expected, actual)
+	}
+
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("expected %q but got %q", expected, actual)
+		}
+	}
+}
+
+func TestNewRadixTreeWithNilFuncErrors(t *testing.T) {
+	_, err := NewRadixTree[string, string](nil, "")
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestRadixTreeInsertContainsGetByKey(t *testing.T) {
+	tree := stringTreeOf("romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "r")
+
+	if tree.Len() != 8 {
+		t.Fatalf("expected 8 items but got %d", tree.Len())
+	}
+
+	for _, v := range []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "r"} {
+		if !tree.Contains(v) || tree.GetByKey(v) != v {
+			t.Fatalf("expected to find %q", v)
+		}
+	}
+
+	// Split points and missing keys must not look like items
+	for _, v := range []string{"", "rom", "roman", "rub", "rubic", "rubiconx", "x"} {
+		if tree.Contains(v) || tree.GetByKey(v) != "" {
+			t.Fatalf("did not expect to find %q", v)
+		}
+	}
+}
+
+func TestRadixTreeInsertDuplicateErrors(t *testing.T) {
+	tree := stringTreeOf("abc")
+
+	if tree.Insert("abc") == nil {
+		t.Fail()
+	}
+
+	if tree.Len() != 1 {
+		t.Fail()
+	}
+}
+
+func TestRadixTreeEmptyKey(t *testing.T) {
+	tree := stringTreeOf("", "a")
+
+	if !tree.Contains("") {
+		t.Fail()
+	}
+
+	expectValues(t, iterValues(tree.Iterator()), "", "a")
+
+	if err := tree.Remove(""); err != nil {
+		t.Fatal(err)
+	}
+
+	expectValues(t, iterValues(tree.Iterator()), "a")
+}
+
+func TestRadixTreeIteratorIsSorted(t *testing.T) {
+	tree := stringTree()
+	expected := make([]string, 0)
+
+	for i := 0; i < 300; i++ {
+		key := randomKey()
+		if tree.Insert(key) == nil {
+			expected = append(expected, key)
+		}
+	}
+
+	sort.Strings(expected)
+
+	expectValues(t, iterValues(tree.Iterator()), expected...)
+}
+
+func TestRadixTreeRemove(t *testing.T) {
+	keys := []string{"test", "toaster", "toasting", "slow", "slowly", "team", "tea", "t"}
+	tree := stringTreeOf(keys...)
+
+	if tree.Remove("to") == nil || tree.Remove("missing") == nil {
+		t.Fatal("expected error removing missing key")
+	}
+
+	for i, key := range keys {
+		if err := tree.Remove(key); err != nil {
+			t.Fatal(err)
+		}
+
+		if tree.Contains(key) {
+			t.Fatalf("still contains %q after removing it", key)
+		}
+
+		remaining := append([]string(nil), keys[i+1:]...)
+		sort.Strings(remaining)
+		expectValues(t, iterValues(tree.Iterator()), remaining...)
+	}
+
+	if tree.Len() != 0 || len(tree.root.children) != 0 {
+		t.Fatal("expected tree to be empty")
+	}
+}
+
+func TestRadixTreeRemoveCompressesNodes(t *testing.T) {
+	tree := stringTreeOf("abc", "abd", "ab")
+
+	tree.Remove("abd")
+	tree.Remove("ab")
+
+	// Only "abc" is left so it should be a single edge again
+	if len(tree.root.children) != 1 || tree.root.children[0].prefix != "abc" || len(tree.root.children[0].children) != 0 {
+		t.Fatal("expected a single compressed edge")
+	}
+}
+
+func TestRadixTreeRandomAgainstMap(t *testing.T) {
+	tree := stringTree()
+	expected := make(map[string]bool)
+
+	for i := 0; i < 5000; i++ {
+		key := randomKey()
+
+		if rand.Intn(3) == 0 {
+			err := tree.Remove(key)
+			if (err == nil) != expected[key] {
+				t.Fatalf("remove of %q gave %v", key, err)
+			}
+			delete(expected, key)
+		} else {
+			err := tree.Insert(key)
+			if (err == nil) == expected[key] {
+				t.Fatalf("insert of %q gave %v", key, err)
+			}
+			expected[key] = true
+		}
+	}
+
+	if tree.Len() != len(expected) {
+		t.Fatalf("expected %d items but got %d", len(expected), tree.Len())
+	}
+
+	sortedKeys := make([]string, 0, len(expected))
+	for key := range expected {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	expectValues(t, iterValues(tree.Iterator()), sortedKeys...)
+}
+
+func TestRadixTreePrefixIterator(t *testing.T) {
+	tree := stringTreeOf("romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus")
+
+	expectValues(t, iterValues(tree.PrefixIterator("rom")), "romane", "romanus", "romulus")
+	expectValues(t, iterValues(tree.PrefixIterator("roma")), "romane", "romanus")
+	expectValues(t, iterValues(tree.PrefixIterator("rubicon")), "rubicon")
+	expectValues(t, iterValues(tree.PrefixIterator("r")), "romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus")
+	expectValues(t, iterValues(tree.PrefixIterator("")), "romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus")
+	expectValues(t, iterValues(tree.PrefixIterator("rubx")))
+	expectValues(t, iterValues(tree.PrefixIterator("rubiconx")))
+	expectValues(t, iterValues(tree.PrefixIterator("x")))
+}
+
+func TestRadixTreeLongestPrefixMatch(t *testing.T) {
+	tree, _ := NewRadixTree(func(r route) []byte { return r.prefix }, route{})
+
+	tree.Insert(route{prefix: []byte("/"), name: "root"})
+	tree.Insert(route{prefix: []byte("/api/"), name: "api"})
+	tree.Insert(route{prefix: []byte("/api/users/"), name: "users"})
+
+	checks := map[string]string{
+		"/":               "root",
+		"/index.html":     "root",
+		"/api":            "root",
+		"/api/":           "api",
+		"/api/orders/1":   "api",
+		"/api/users/":     "users",
+		"/api/users/1234": "users",
+	}
+
+	for key, expected := range checks {
+		found, err := tree.LongestPrefixMatch([]byte(key))
+		if err != nil || found.name != expected {
+			t.Fatalf("expected %q for %q but got %q %v", expected, key, found.name, err)
+		}
+	}
+
+	if _, err := tree.LongestPrefixMatch([]byte("api")); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+}
+
+func randomKey() string {
+	// Small alphabet and short keys so that keys share lots of prefixes
+	key := make([]byte, rand.Intn(6))
+
+	for i := range key {
+		key[i] = "abc"[rand.Intn(3)]
+	}
+
+	return string(key)
+}
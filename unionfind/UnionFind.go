@@ -0,0 +1,247 @@
+package unionfind
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+// UnionFind (disjoint set) keeps track of which keys have been joined into the same component
+// Find and Union are close to O(1) using path compression and union by rank
+// Components are never split, keys can only be added and joined
+type UnionFind[K any] struct {
+	// keys, parent, rank and next are indexed by the order keys were added in
+	keys   []K
+	parent []int
+	rank   []uint8
+	// size is only kept up to date for roots
+	size []int
+	// next links the members of each component into a cycle so they can be iterated over
+	next       []int
+	components int
+	// indexOf and addIndex look up where a key is kept
+	indexOf  func(K) (int, bool)
+	addIndex func(K, int)
+}
+
+func newUnionFind[K any](indexOf func(K) (int, bool), addIndex func(K, int)) *UnionFind[K] {
+	return &UnionFind[K]{indexOf: indexOf, addIndex: addIndex}
+}
+
+// NewUnionFind creates a new empty UnionFind for keys that can be used as map keys
+func NewUnionFind[K comparable]() *UnionFind[K] {
+	indexes := make(map[K]int)
+
+	return newUnionFind(func(key K) (int, bool) {
+		index, ok := indexes[key]
+		return index, ok
+	}, func(key K, index int) {
+		indexes[key] = index
+	})
+}
+
+// NewUnionFindWithHash creates a new empty UnionFind for keys that can't be used as map keys
+// hashFunc must return the same hash for keys that equalFunc says are equal
+func NewUnionFindWithHash[K any](hashFunc func(K) uint64, equalFunc func(K, K) bool) (*UnionFind[K], error) {
+	if hashFunc == nil {
+		return nil, errors.New("unable to create UnionFind without a function to hash Keys")
+	}
+
+	if equalFunc == nil {
+		return nil, errors.New("unable to create UnionFind without a function to compare Keys")
+	}
+
+	buckets := make(map[uint64][]int)
+	var unionFind *UnionFind[K]
+
+	unionFind = newUnionFind(func(key K) (int, bool) {
+		for _, index := range buckets[hashFunc(key)] {
+			if equalFunc(key, unionFind.keys[index]) {
+				return index, true
+			}
+		}
+
+		return 0, false
+	}, func(key K, index int) {
+		hash := hashFunc(key)
+		buckets[hash] = append(buckets[hash], index)
+	})
+
+	return unionFind, nil
+}
+
+// Len returns the number of keys
+func (this *UnionFind[K]) Len() int {
+	return len(this.keys)
+}
+
+// Components returns the number of separate components
+func (this *UnionFind[K]) Components() int {
+	return this.components
+}
+
+// MakeSet adds key in a component of its own
+func (this *UnionFind[K]) MakeSet(key K) error {
+	if _, ok := this.indexOf(key); ok {
+		return fmt.Errorf("unable to make set for duplicate key %v", key)
+	}
+
+	index := len(this.keys)
+
+	this.keys = append(this.keys, key)
+	this.parent = append(this.parent, index)
+	this.rank = append(this.rank, 0)
+	this.size = append(this.size, 1)
+	this.next = append(this.next, index)
+	this.addIndex(key, index)
+	this.components++
+
+	return nil
+}
+
+func (this *UnionFind[K]) index(key K) (int, error) {
+	index, ok := this.indexOf(key)
+
+	if !ok {
+		return 0, godatacollections.NotFoundError()
+	}
+
+	return index, nil
+}
+
+// findRoot returns the root of index's component, pointing everything on the way straight at the root
+func (this *UnionFind[K]) findRoot(index int) int {
+	root := index
+
+	for this.parent[root] != root {
+		root = this.parent[root]
+	}
+
+	for this.parent[index] != root {
+		index, this.parent[index] = this.parent[index], root
+	}
+
+	return root
+}
+
+// Find returns the key that represents key's component
+// Two keys are in the same component when Find returns the same key for both, until the next Union
+// Returns NotFoundError if key was never added
+func (this *UnionFind[K]) Find(key K) (K, error) {
+	index, err := this.index(key)
+
+	if err != nil {
+		var zeroValue K
+		return zeroValue, err
+	}
+
+	return this.keys[this.findRoot(index)], nil
+}
+
+// Union joins the components of a and b
+// Returns true if they were separate and have been joined or false if they were already the same component
+// Returns NotFoundError if either key was never added
+func (this *UnionFind[K]) Union(a, b K) (bool, error) {
+	aIndex, err := this.index(a)
+	if err != nil {
+		return false, err
+	}
+
+	bIndex, err := this.index(b)
+	if err != nil {
+		return false, err
+	}
+
+	aRoot := this.findRoot(aIndex)
+	bRoot := this.findRoot(bIndex)
+
+	if aRoot == bRoot {
+		return false, nil
+	}
+
+	// Hang the shorter tree under the taller one so trees stay shallow
+	if this.rank[aRoot] < this.rank[bRoot] {
+		aRoot, bRoot = bRoot, aRoot
+	} else if this.rank[aRoot] == this.rank[bRoot] {
+		this.rank[aRoot]++
+	}
+
+	this.parent[bRoot] = aRoot
+	this.size[aRoot] += this.size[bRoot]
+	// Swapping nexts splices the two member cycles into one
+	this.next[aRoot], this.next[bRoot] = this.next[bRoot], this.next[aRoot]
+	this.components--
+
+	return true, nil
+}
+
+// Connected returns if a and b are in the same component
+// Returns NotFoundError if either key was never added
+func (this *UnionFind[K]) Connected(a, b K) (bool, error) {
+	aIndex, err := this.index(a)
+	if err != nil {
+		return false, err
+	}
+
+	bIndex, err := this.index(b)
+	if err != nil {
+		return false, err
+	}
+
+	return this.findRoot(aIndex) == this.findRoot(bIndex), nil
+}
+
+// ComponentSize returns the number of keys in key's component
+// Returns NotFoundError if key was never added
+func (this *UnionFind[K]) ComponentSize(key K) (int, error) {
+	index, err := this.index(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return this.size[this.findRoot(index)], nil
+}
+
+// Members returns an iterator over every key in key's component, starting with key
+// Takes O(1) per key. The UnionFind must not be changed while iterating
+// Returns NotFoundError if key was never added
+func (this *UnionFind[K]) Members(key K) (godatacollections.Iterator[K], error) {
+	index, err := this.index(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &membersIterator[K]{unionFind: this, start: index, next: index}, nil
+}
+
+type membersIterator[K any] struct {
+	unionFind *UnionFind[K]
+	start     int
+	// next is -1 once the cycle has come back around to start
+	next int
+}
+
+func (this *membersIterator[K]) Close() error {
+	return nil
+}
+
+func (this *membersIterator[K]) HasNext() bool {
+	return this.next != -1
+}
+
+func (this *membersIterator[K]) Next() (K, error) {
+	if this.next == -1 {
+		var zeroValue K
+		return zeroValue, errors.New("nothing left to iterate over")
+	}
+
+	retKey := this.unionFind.keys[this.next]
+	this.next = this.unionFind.next[this.next]
+
+	if this.next == this.start {
+		this.next = -1
+	}
+
+	return retKey, nil
+}
@@ -0,0 +1,220 @@
+package unionfind
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ZacharyDuve/godatacollections"
+)
+
+func memberStrings(t *testing.T, unionFind *UnionFind[string], key string) []string {
+	iter, err := unionFind.Members(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	members := make([]string, 0)
+
+	for iter.HasNext() {
+		member, _ := iter.Next()
+		members = append(members, member)
+	}
+
+	sort.Strings(members)
+
+	return members
+}
+
+func expectMembers(t *testing.T, actual []string, expected ...string) {
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected %v but got %v", expected, actual)
+	}
+}
+
+func TestUnionFindMakeSetDuplicateErrors(t *testing.T) {
+	unionFind := NewUnionFind[string]()
+
+	if unionFind.MakeSet("a") != nil {
+		t.Fail()
+	}
+
+	if unionFind.MakeSet("a") == nil {
+		t.Fail()
+	}
+
+	if unionFind.Len() != 1 || unionFind.Components() != 1 {
+		t.Fail()
+	}
+}
+
+func TestUnionFindUnknownKeysAreNotFound(t *testing.T) {
+	unionFind := NewUnionFind[string]()
+	unionFind.MakeSet("a")
+
+	if _, err := unionFind.Find("x"); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+
+	if _, err := unionFind.Union("a", "x"); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+
+	if _, err := unionFind.Connected("x", "a"); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+
+	if _, err := unionFind.Members("x"); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+
+	if _, err := unionFind.ComponentSize("x"); !godatacollections.IsNotFoundError(err) {
+		t.Fail()
+	}
+}
+
+func TestUnionFindUnion(t *testing.T) {
+	unionFind := NewUnionFind[string]()
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		unionFind.MakeSet(key)
+	}
+
+	if joined, _ := unionFind.Union("a", "b"); !joined {
+		t.Fail()
+	}
+
+	unionFind.Union("c", "d")
+	unionFind.Union("b", "d")
+
+	if joined, _ := unionFind.Union("a", "c"); joined {
+		t.Fatal("a and c were already connected")
+	}
+
+	if connected, _ := unionFind.Connected("a", "d"); !connected {
+		t.Fail()
+	}
+
+	if connected, _ := unionFind.Connected("a", "e"); connected {
+		t.Fail()
+	}
+
+	aRoot, _ := unionFind.Find("a")
+	dRoot, _ := unionFind.Find("d")
+	eRoot, _ := unionFind.Find("e")
+
+	if aRoot != dRoot || eRoot != "e" {
+		t.Fail()
+	}
+
+	if unionFind.Components() != 2 {
+		t.Fatalf("expected 2 components but got %d", unionFind.Components())
+	}
+
+	if size, _ := unionFind.ComponentSize("c"); size != 4 {
+		t.Fatalf("expected size 4 but got %d", size)
+	}
+
+	expectMembers(t, memberStrings(t, unionFind, "c"), "a", "b", "c", "d")
+	expectMembers(t, memberStrings(t, unionFind, "e"), "e")
+}
+
+func TestUnionFindRandomAgainstLabels(t *testing.T) {
+	const n = 500
+	unionFind := NewUnionFind[int]()
+	// label is a slow but obviously correct way to track components
+	label := make([]int, n)
+
+	for i := 0; i < n; i++ {
+		unionFind.MakeSet(i)
+		label[i] = i
+	}
+
+	components := n
+
+	for i := 0; i < 400; i++ {
+		a, b := rand.Intn(n), rand.Intn(n)
+		joined, _ := unionFind.Union(a, b)
+
+		if joined != (label[a] != label[b]) {
+			t.Fatalf("union of %d and %d gave %v", a, b, joined)
+		}
+
+		if joined {
+			oldLabel := label[b]
+			for j := range label {
+				if label[j] == oldLabel {
+					label[j] = label[a]
+				}
+			}
+			components--
+		}
+	}
+
+	if unionFind.Components() != components {
+		t.Fatalf("expected %d components but got %d", components, unionFind.Components())
+	}
+
+	for i := 0; i < n; i++ {
+		iter, _ := unionFind.Members(i)
+		count := 0
+
+		for iter.HasNext() {
+			member, _ := iter.Next()
+			if label[member] != label[i] {
+				t.Fatalf("%d listed as a member of %d's component", member, i)
+			}
+			count++
+		}
+
+		if size, _ := unionFind.ComponentSize(i); size != count {
+			t.Fatalf("component of %d has size %d but iterated %d", i, size, count)
+		}
+	}
+}
+
+type device struct {
+	serial []byte
+}
+
+func TestUnionFindWithHash(t *testing.T) {
+	_, err := NewUnionFindWithHash[device](nil, func(a, b device) bool { return true })
+	if err == nil {
+		t.Fail()
+	}
+
+	_, err = NewUnionFindWithHash(func(d device) uint64 { return 0 }, nil)
+	if err == nil {
+		t.Fail()
+	}
+
+	// A terrible hash puts everything in a few buckets so equality has to do the work
+	hashFunc := func(d device) uint64 { return uint64(len(d.serial) % 2) }
+	equalFunc := func(a, b device) bool { return string(a.serial) == string(b.serial) }
+	unionFind, _ := NewUnionFindWithHash(hashFunc, equalFunc)
+
+	for _, serial := range []string{"a1", "b22", "c3", "d44"} {
+		if err := unionFind.MakeSet(device{serial: []byte(serial)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if unionFind.MakeSet(device{serial: []byte("c3")}) == nil {
+		t.Fatal("expected duplicate error for an equal key")
+	}
+
+	unionFind.Union(device{serial: []byte("a1")}, device{serial: []byte("d44")})
+
+	if connected, _ := unionFind.Connected(device{serial: []byte("d44")}, device{serial: []byte("a1")}); !connected {
+		t.Fail()
+	}
+
+	if connected, _ := unionFind.Connected(device{serial: []byte("a1")}, device{serial: []byte("c3")}); connected {
+		t.Fail()
+	}
+
+	if unionFind.Components() != 3 {
+		t.Fail()
+	}
+}